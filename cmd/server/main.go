@@ -5,11 +5,13 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"darts-training-app/internal/config"
 	"darts-training-app/internal/database"
 	"darts-training-app/internal/handlers"
 	"darts-training-app/internal/middleware"
+	"darts-training-app/internal/models"
 	"darts-training-app/internal/services"
 
 	"github.com/gin-contrib/cors"
@@ -35,18 +37,95 @@ func main() {
 		log.Printf("Warning: Failed to seed default data: %v", err)
 	}
 
+	// Initialize authentication, the dev issuer replaces the OIDC provider locally
+	var authenticator services.Authenticator
+	var userInfoProvider services.UserInfoProvider
+	var devIssuer *services.DevIssuer
+	authStatus := func() models.AuthStatus {
+		return models.AuthStatus{Mode: cfg.AuthMode, Ready: true}
+	}
+	if cfg.AuthMode == "dev" {
+		devIssuer, err = services.NewDevIssuer(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize dev auth: %v", err)
+		}
+		log.Printf("WARNING: AUTH_MODE=dev accepts locally minted tokens, never enable it in production")
+		authenticator = devIssuer
+	} else {
+		if len(cfg.JWTAudiences) == 0 {
+			log.Printf("WARNING: JWT_AUDIENCE is not set, tokens issued for other APIs are accepted")
+		}
+		// Keys are loaded in the background, the server starts while the provider is down
+		authManager := services.NewAuthManager(cfg)
+		authManager.StartRefresh(15 * time.Minute)
+		authenticator = authManager
+		authStatus = authManager.Status
+		if cfg.UserInfoEnabled {
+			userInfoProvider = authManager
+		}
+	}
+
+	// Outgoing mail, see MAIL_SENDER
+	mailSender, err := services.NewMailSender(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize mail sender: %v", err)
+	}
+
 	// Initialize services
-	authManager := services.NewAuthManager(cfg)
 	teamService := services.NewTeamService(db.DB)
 	playerService := services.NewPlayerService(db.DB)
-	trainingService := services.NewTrainingService(db.DB)
+	trainingService := services.NewTrainingService(db.DB, services.NewLogNotifier())
 	gameService := services.NewGameService(db.DB)
+	tournamentService := services.NewTournamentService(db.DB)
+	standingsService := services.NewStandingsService(db.DB)
+	ledgerService := services.NewLedgerService(db.DB)
+	pricingService := services.NewPricingService(db.DB)
+	expenseService := services.NewExpenseService(db.DB)
+	bankingService := services.NewBankingService(db.DB)
+	invoiceService := services.NewInvoiceService(db.DB)
+	authorizationService := services.NewAuthorizationService(db.DB, cfg)
+	identityService := services.NewIdentityService(playerService, userInfoProvider, cfg)
+	apiKeyService := services.NewAPIKeyService(db.DB)
+	clubService := services.NewClubService(db.DB)
+	if err := clubService.ApplyDefaultCreditor(cfg); err != nil {
+		log.Printf("Warning: Failed to apply the SEPA creditor to the default club: %v", err)
+	}
+	privacyService := services.NewPrivacyService(db.DB)
+	auditService := services.NewAuditService(db.DB)
+	invitationService := services.NewInvitationService(db.DB, mailSender, cfg)
+	mergeService := services.NewMergeService(db.DB)
+	guestService := services.NewGuestService(db.DB, cfg)
+	seasonService := services.NewSeasonService(db.DB)
+	fixtureService := services.NewFixtureService(db.DB, cfg)
 
 	// Initialize handlers
 	teamHandler := handlers.NewTeamHandler(teamService)
 	playerHandler := handlers.NewPlayerHandler(playerService)
 	trainingHandler := handlers.NewTrainingHandler(trainingService)
 	gameHandler := handlers.NewGameHandler(gameService)
+	tournamentHandler := handlers.NewTournamentHandler(tournamentService)
+	standingsHandler := handlers.NewStandingsHandler(standingsService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	pricingHandler := handlers.NewPricingHandler(pricingService)
+	expenseHandler := handlers.NewExpenseHandler(expenseService)
+	bankingHandler := handlers.NewBankingHandler(bankingService)
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceService)
+	roleHandler := handlers.NewRoleHandler(authorizationService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	clubHandler := handlers.NewClubHandler(clubService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	mergeHandler := handlers.NewMergeHandler(mergeService)
+	guestHandler := handlers.NewGuestHandler(guestService)
+	seasonHandler := handlers.NewSeasonHandler(seasonService)
+	fixtureHandler := handlers.NewFixtureHandler(fixtureService)
+
+	// Invoice the previous month once a day
+	invoiceService.StartMonthlyJob(24 * time.Hour)
+
+	// Start and end team memberships as days pass
+	teamService.StartDailySync(time.Hour)
 
 	// Setup Gin router
 	if cfg.Port == "8080" {
@@ -63,7 +142,7 @@ func main() {
 	config := cors.DefaultConfig()
 	config.AllowOrigins = strings.Split(cfg.FrontendURL, ",")
 	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-Club"}
 	config.AllowCredentials = true
 	router.Use(cors.New(config))
 
@@ -73,67 +152,249 @@ func main() {
 			"status":  "ok",
 			"service": "darts-training-app",
 			"version": "1.0.0",
+			"auth":    authStatus(),
 		})
 	})
 
+	// Readiness check, fails until tokens can be verified
+	router.GET("/health/ready", func(c *gin.Context) {
+		status := authStatus()
+		if !status.Ready {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "auth": status})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "auth": status})
+	})
+
+	// Development token issuer
+	if devIssuer != nil {
+		devAuthHandler := handlers.NewDevAuthHandler(devIssuer)
+		dev := router.Group("/dev")
+		{
+			dev.GET("/.well-known/openid-configuration", devAuthHandler.GetOpenIDConfiguration)
+			dev.GET("/.well-known/jwks.json", devAuthHandler.GetJWKS)
+			dev.GET("/users", devAuthHandler.GetUsers)
+			dev.POST("/token", devAuthHandler.MintToken)
+		}
+	}
+
+	// Permission checks, see services.rolePermissions for the role mapping
+	canReadTeams := middleware.RequirePermission(models.PermissionTeamsRead)
+	canWriteTeams := middleware.RequirePermission(models.PermissionTeamsWrite)
+	canSetLineups := middleware.RequirePermission(models.PermissionTeamsWrite, models.PermissionLineupsWrite)
+	canReadPlayers := middleware.RequirePermission(models.PermissionPlayersRead)
+	canWritePlayers := middleware.RequirePermission(models.PermissionPlayersWrite)
+	canWriteOwnPlayer := middleware.RequireOwnPlayerOr(models.PermissionPlayersWrite, models.PermissionPlayersOwn)
+	canWriteOwnProfile := middleware.RequirePermission(models.PermissionPlayersWrite, models.PermissionPlayersOwn)
+	canInvitePlayers := middleware.RequirePermission(models.PermissionPlayersInvite, models.PermissionPlayersWrite)
+	canReadSessions := middleware.RequirePermission(models.PermissionSessionsRead)
+	canWriteSessions := middleware.RequirePermission(models.PermissionSessionsWrite)
+	canScoreGames := middleware.RequirePermission(models.PermissionGamesScore, models.PermissionSessionsWrite)
+	canReadLedger := middleware.RequirePermission(models.PermissionLedgerRead)
+	canReadOwnLedger := middleware.RequireOwnPlayerOr(models.PermissionLedgerRead, models.PermissionLedgerOwn)
+	canWriteLedger := middleware.RequirePermission(models.PermissionLedgerWrite)
+	canWritePricing := middleware.RequirePermission(models.PermissionPricingWrite)
+	canManageRoles := middleware.RequirePermission(models.PermissionRolesManage)
+	canManageClubs := middleware.RequireTokenPermission(models.PermissionClubsManage)
+
 	// API routes
 	api := router.Group("/api")
 	{
 		// Protected routes (require authentication)
 		protected := api.Group("")
-		protected.Use(middleware.CheckAuth(authManager))
+		protected.Use(middleware.CheckAuth(authenticator, apiKeyService))
+		protected.Use(middleware.ResolveClub(clubService))
+		protected.Use(middleware.ResolveIdentity(identityService, "/api/invitations/accept"))
+		protected.Use(middleware.LoadAccess(authorizationService))
 		{
 			// Team routes
 			teams := protected.Group("/teams")
 			{
-				teams.GET("", teamHandler.GetAllTeams)
-				teams.POST("", teamHandler.CreateTeam)
-				teams.GET("/:id", teamHandler.GetTeamByID)
-				teams.PUT("/:id", teamHandler.UpdateTeam)
-				teams.DELETE("/:id", teamHandler.DeleteTeam)
-				teams.GET("/:id/players", teamHandler.GetTeamPlayers)
+				teams.GET("", canReadTeams, teamHandler.GetAllTeams)
+				teams.POST("", canWriteTeams, teamHandler.CreateTeam)
+				teams.GET("/:id", canReadTeams, teamHandler.GetTeamByID)
+				teams.PUT("/:id", canWriteTeams, teamHandler.UpdateTeam)
+				teams.DELETE("/:id", canWriteTeams, teamHandler.DeleteTeam)
+				teams.GET("/:id/players", canReadTeams, teamHandler.GetTeamPlayers)
+				teams.GET("/:id/memberships", canReadTeams, teamHandler.GetTeamMemberships)
+				teams.POST("/:id/memberships", canWriteTeams, teamHandler.CreateMembership)
+				teams.PUT("/:id/memberships/:membershipId", canWriteTeams, teamHandler.UpdateMembership)
+				teams.DELETE("/:id/memberships/:membershipId", canWriteTeams, teamHandler.DeleteMembership)
+			}
+
+			// Season routes
+			seasons := protected.Group("/seasons")
+			{
+				seasons.GET("", canReadTeams, seasonHandler.GetSeasons)
+				seasons.POST("", canWriteTeams, seasonHandler.CreateSeason)
+				seasons.GET("/:id", canReadTeams, seasonHandler.GetSeasonByID)
+				seasons.PUT("/:id", canWriteTeams, seasonHandler.UpdateSeason)
+				seasons.DELETE("/:id", canWriteTeams, seasonHandler.DeleteSeason)
+			}
+
+			// Fixture routes, captains set lineups and record results
+			fixtures := protected.Group("/fixtures")
+			{
+				fixtures.GET("", canReadTeams, fixtureHandler.GetFixtures)
+				fixtures.POST("", canWriteTeams, fixtureHandler.CreateFixture)
+				fixtures.GET("/:id", canReadTeams, fixtureHandler.GetFixtureByID)
+				fixtures.PUT("/:id", canWriteTeams, fixtureHandler.UpdateFixture)
+				fixtures.DELETE("/:id", canWriteTeams, fixtureHandler.DeleteFixture)
+				fixtures.PUT("/:id/lineup", canSetLineups, fixtureHandler.SetLineup)
+				fixtures.PUT("/:id/games/:gameId", canSetLineups, fixtureHandler.RecordGameResult)
 			}
 
 			// Player routes
 			players := protected.Group("/players")
 			{
-				players.GET("", playerHandler.GetAllPlayers)
-				players.POST("", playerHandler.CreatePlayer)
-				players.GET("/:id", playerHandler.GetPlayerByID)
-				players.PUT("/:id", playerHandler.UpdatePlayer)
-				players.DELETE("/:id", playerHandler.DeletePlayer)
-				players.PUT("/:id/activate", playerHandler.ActivatePlayer)
-				players.PUT("/:id/deactivate", playerHandler.DeactivatePlayer)
-				players.GET("/team/:teamId", playerHandler.GetPlayersByTeam)
+				players.GET("", canReadPlayers, playerHandler.GetAllPlayers)
+				players.POST("", canWritePlayers, playerHandler.CreatePlayer)
+				players.GET("/:id", canReadPlayers, playerHandler.GetPlayerByID)
+				players.PUT("/:id", canWriteOwnPlayer, playerHandler.UpdatePlayer)
+				players.DELETE("/:id", canWritePlayers, playerHandler.DeletePlayer)
+				players.PUT("/:id/activate", canWritePlayers, playerHandler.ActivatePlayer)
+				players.PUT("/:id/deactivate", canWritePlayers, playerHandler.DeactivatePlayer)
+				players.GET("/team/:teamId", canReadPlayers, playerHandler.GetPlayersByTeam)
+				players.GET("/:id/memberships", canReadPlayers, teamHandler.GetPlayerMemberships)
 				players.GET("/me", playerHandler.GetCurrentUser)
 				players.POST("/me", playerHandler.CreateCurrentUser)
+				players.PUT("/me", canWriteOwnProfile, playerHandler.UpdateCurrentUser)
+				players.GET("/:id/balance", canReadOwnLedger, ledgerHandler.GetPlayerBalance)
+				players.POST("/:id/payments", canWriteLedger, ledgerHandler.RecordPayment)
+				players.GET("/:id/mandate", canReadOwnLedger, bankingHandler.GetMandate)
+				players.PUT("/:id/mandate", canWriteLedger, bankingHandler.SaveMandate)
+				players.DELETE("/:id/mandate", canWriteLedger, bankingHandler.RevokeMandate)
+				players.GET("/:id/invoices", canReadOwnLedger, invoiceHandler.GetPlayerInvoices)
+				players.GET("/:id/invoices/:invoiceId", canReadOwnLedger, invoiceHandler.GetInvoice)
+				players.GET("/:id/export", canWriteOwnPlayer, privacyHandler.ExportPlayerData)
+				players.POST("/:id/anonymize", canWritePlayers, privacyHandler.AnonymizePlayer)
+				players.POST("/:id/merge", canManageRoles, mergeHandler.MergeIntoPlayer)
+			}
+
+			// Invitation routes, accepting is open to every logged in user
+			invitations := protected.Group("/invitations")
+			{
+				invitations.GET("", canInvitePlayers, invitationHandler.GetInvitations)
+				invitations.POST("", canInvitePlayers, invitationHandler.CreateInvitation)
+				invitations.POST("/accept", invitationHandler.AcceptInvitation)
+				invitations.POST("/:id/resend", canInvitePlayers, invitationHandler.ResendInvitation)
+				invitations.DELETE("/:id", canInvitePlayers, invitationHandler.RevokeInvitation)
+			}
+
+			// Guest routes, guests are managed by whoever runs the trainings
+			guests := protected.Group("/guests")
+			{
+				guests.GET("", canWriteSessions, guestHandler.GetGuests)
+				guests.POST("", canWriteSessions, guestHandler.CreateGuest)
+				guests.GET("/suggestions", canWriteSessions, guestHandler.GetMembershipSuggestions)
+				guests.GET("/:id", canWriteSessions, guestHandler.GetGuestByID)
+				guests.PUT("/:id", canWriteSessions, guestHandler.UpdateGuest)
+				guests.DELETE("/:id", canWriteSessions, guestHandler.DeleteGuest)
+				guests.POST("/:id/convert", canWritePlayers, guestHandler.ConvertGuest)
+			}
+
+			// Ledger routes
+			ledger := protected.Group("/ledger")
+			{
+				ledger.GET("/outstanding", canReadLedger, ledgerHandler.GetOutstandingDebts)
+				ledger.GET("/outstanding/export", canReadLedger, bankingHandler.ExportOutstandingCSV)
+				ledger.POST("/sepa-export", canWriteLedger, bankingHandler.ExportSepaDirectDebit)
+				ledger.GET("/sepa-collections", canReadLedger, bankingHandler.GetSepaCollections)
+				ledger.GET("/sepa-collections/:id", canReadLedger, bankingHandler.GetSepaCollection)
+				ledger.POST("/sepa-collections/:id/confirm", canWriteLedger, bankingHandler.ConfirmSepaCollection)
+				ledger.POST("/sepa-collections/:id/reject", canWriteLedger, bankingHandler.RejectSepaCollection)
+				ledger.POST("/bank-statements", canWriteLedger, bankingHandler.ImportBankStatement)
+				ledger.GET("/bank-transactions", canReadLedger, bankingHandler.GetBankTransactions)
+				ledger.POST("/bank-transactions/:id/assign", canWriteLedger, bankingHandler.AssignBankTransaction)
+				ledger.POST("/bank-transactions/:id/ignore", canWriteLedger, bankingHandler.IgnoreBankTransaction)
+			}
+
+			// Invoice routes
+			invoices := protected.Group("/invoices")
+			{
+				invoices.POST("/generate", canWriteLedger, invoiceHandler.GenerateInvoices)
+			}
+
+			// Pricing policy routes
+			pricing := protected.Group("/pricing-policies")
+			{
+				pricing.GET("", canReadSessions, pricingHandler.GetAllPricingPolicies)
+				pricing.POST("", canWritePricing, pricingHandler.CreatePricingPolicy)
+				pricing.GET("/:id", canReadSessions, pricingHandler.GetPricingPolicyByID)
+				pricing.PUT("/:id", canWritePricing, pricingHandler.UpdatePricingPolicy)
+				pricing.DELETE("/:id", canWritePricing, pricingHandler.DeletePricingPolicy)
 			}
 
 			// Training session routes
 			training := protected.Group("/training-sessions")
 			{
-				training.GET("", trainingHandler.GetAllTrainingSessions)
-				training.POST("", trainingHandler.CreateTrainingSession)
-				training.GET("/:id", trainingHandler.GetTrainingSessionByID)
-				training.PUT("/:id", trainingHandler.UpdateTrainingSession)
-				training.DELETE("/:id", trainingHandler.DeleteTrainingSession)
-				training.POST("/:id/start", trainingHandler.StartTraining)
-				training.POST("/:id/finish", trainingHandler.FinishTraining)
-				training.GET("/:id/costs", trainingHandler.GetTrainingCosts)
-				training.POST("/:id/players", trainingHandler.AddTrainingPlayer)
-				training.DELETE("/players/:playerId", trainingHandler.RemoveTrainingPlayer)
+				training.GET("", canReadSessions, trainingHandler.GetAllTrainingSessions)
+				training.POST("", canWriteSessions, trainingHandler.CreateTrainingSession)
+				training.GET("/:id", canReadSessions, trainingHandler.GetTrainingSessionByID)
+				training.PUT("/:id", canWriteSessions, trainingHandler.UpdateTrainingSession)
+				training.DELETE("/:id", canWriteSessions, trainingHandler.DeleteTrainingSession)
+				training.POST("/:id/start", canWriteSessions, trainingHandler.StartTraining)
+				training.POST("/:id/finish", canWriteSessions, trainingHandler.FinishTraining)
+				training.POST("/:id/cancel", canWriteSessions, trainingHandler.CancelTraining)
+				training.POST("/:id/postpone", canWriteSessions, trainingHandler.PostponeTraining)
+				training.POST("/:id/reschedule", canWriteSessions, trainingHandler.RescheduleTraining)
+				training.POST("/:id/reopen", canWriteSessions, trainingHandler.ReopenTraining)
+				training.GET("/:id/transitions", canReadSessions, trainingHandler.GetTrainingTransitions)
+				training.GET("/:id/costs", canReadSessions, trainingHandler.GetTrainingCosts)
+				training.GET("/:id/expenses", canReadSessions, expenseHandler.GetExpenses)
+				training.POST("/:id/expenses", canWriteLedger, expenseHandler.CreateExpense)
+				training.PUT("/:id/expenses/:expenseId", canWriteLedger, expenseHandler.UpdateExpense)
+				training.DELETE("/:id/expenses/:expenseId", canWriteLedger, expenseHandler.DeleteExpense)
+				training.GET("/:id/standings", canReadSessions, standingsHandler.GetStandings)
+				training.POST("/:id/players", canWriteSessions, trainingHandler.AddTrainingPlayer)
+				training.DELETE("/players/:playerId", canWriteSessions, trainingHandler.RemoveTrainingPlayer)
+				training.POST("/:id/tournament", canWriteSessions, tournamentHandler.CreateTournament)
+				training.GET("/:id/tournament", canReadSessions, tournamentHandler.GetBracket)
 			}
 
 			// Game routes
 			games := protected.Group("/games")
 			{
-				games.GET("", gameHandler.GetAllGames)
-				games.GET("/modes", gameHandler.GetAllGameModes)
-				games.GET("/training/:sessionId", gameHandler.GetGamesByTrainingSession)
-				games.POST("/training/:sessionId", gameHandler.CreateGame)
-				games.POST("/training/:sessionId/generate", gameHandler.GenerateGames)
-				games.PUT("/:id", gameHandler.UpdateGame)
-				games.DELETE("/:id", gameHandler.DeleteGame)
+				games.GET("", canReadSessions, gameHandler.GetAllGames)
+				games.GET("/modes", canReadSessions, gameHandler.GetAllGameModes)
+				games.GET("/training/:sessionId", canReadSessions, gameHandler.GetGamesByTrainingSession)
+				games.POST("/training/:sessionId", canWriteSessions, gameHandler.CreateGame)
+				games.POST("/training/:sessionId/generate", canWriteSessions, gameHandler.GenerateGames)
+				games.GET("/training/:sessionId/swiss", canReadSessions, gameHandler.GetSwissStandings)
+				games.PUT("/:id", canScoreGames, gameHandler.UpdateGame)
+				games.DELETE("/:id", canWriteSessions, gameHandler.DeleteGame)
+			}
+
+			// Role routes
+			roles := protected.Group("/roles")
+			{
+				roles.GET("/me", roleHandler.GetMyAccess)
+				roles.GET("", canManageRoles, roleHandler.GetRoleAssignments)
+				roles.POST("", canManageRoles, roleHandler.AssignRole)
+				roles.DELETE("/:id", canManageRoles, roleHandler.RemoveRoleAssignment)
+			}
+
+			// API key routes
+			apiKeys := protected.Group("/api-keys")
+			{
+				apiKeys.GET("", canManageRoles, apiKeyHandler.GetAPIKeys)
+				apiKeys.POST("", canManageRoles, apiKeyHandler.CreateAPIKey)
+				apiKeys.DELETE("/:id", canManageRoles, apiKeyHandler.RevokeAPIKey)
+			}
+
+			// Club routes
+			clubs := protected.Group("/clubs")
+			{
+				clubs.GET("/current", clubHandler.GetCurrentClub)
+				clubs.PUT("/current/creditor", canManageRoles, clubHandler.UpdateCurrentClubCreditor)
+				clubs.GET("", canManageClubs, clubHandler.GetClubs)
+				clubs.POST("", canManageClubs, clubHandler.CreateClub)
+			}
+
+			// Audit log routes
+			auditLogs := protected.Group("/audit-logs")
+			{
+				auditLogs.GET("", canManageRoles, auditHandler.GetAuditLogs)
 			}
 		}
 	}
@@ -4,6 +4,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +23,57 @@ type Config struct {
 	// Additional fields for AuthManager
 	OidcBaseURL                     string
 	ClientCredentialAuthHeaderValue string
+
+	// Authentication mode: "auth0" verifies tokens of the OIDC provider, "dev"
+	// runs an embedded token issuer for local development and tests
+	AuthMode       string
+	DevAuthIssuer  string
+	DevAuthKeyFile string
+
+	// JWT validation: expected issuer (default: issuer of the OIDC discovery
+	// document), accepted audiences (the API identifier), allowed signing
+	// algorithms and clock skew tolerance for exp, nbf and iat
+	JWTIssuer     string
+	JWTAudiences  []string
+	JWTAlgorithms []string
+	JWTLeeway     time.Duration
+
+	// Identity resolution: create players for new logins with a verified
+	// email and ask the OIDC userinfo endpoint for missing profile claims
+	AutoCreatePlayers bool
+	UserInfoEnabled   bool
+
+	// Role for authenticated users without a role in the token or role table
+	DefaultRole string
+
+	// SEPA creditor used for direct-debit exports
+	SepaCreditorName string
+	SepaCreditorIBAN string
+	SepaCreditorBIC  string
+	SepaCreditorID   string
+
+	// Player invitations: key signing the invitation tokens, their validity
+	// and the frontend page the invitation link points to
+	InvitationSecret string
+	InvitationTTL    time.Duration
+	InvitationURL    string
+
+	// Outgoing mail: "log" writes mails to the application log, "file" stores
+	// them as .eml files in MailDir, "smtp" delivers them via the SMTP server
+	MailSender   string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Guests with this many attended trainings are suggested to become members
+	GuestMembershipVisits int
+
+	// Default match format of league fixtures: singles followed by doubles
+	FixtureSingles int
+	FixtureDoubles int
 }
 
 func LoadConfig() (*Config, error) {
@@ -43,10 +97,89 @@ func LoadConfig() (*Config, error) {
 		// Calculate AuthManager fields
 		OidcBaseURL:                     getEnv("OIDC_BASE_URL", "https://"+auth0Domain),
 		ClientCredentialAuthHeaderValue: calculateAuthHeader(getEnv("AUTH0_CLIENT_ID", ""), getEnv("AUTH0_CLIENT_SECRET", "")),
+
+		AuthMode:       getEnv("AUTH_MODE", "auth0"),
+		DevAuthIssuer:  getEnv("DEV_AUTH_ISSUER", "http://localhost:"+getEnv("PORT", "8080")+"/dev"),
+		DevAuthKeyFile: getEnv("DEV_AUTH_KEY_FILE", ""),
+
+		JWTIssuer:     getEnv("JWT_ISSUER", ""),
+		JWTAudiences:  getEnvList("JWT_AUDIENCE", ""),
+		JWTAlgorithms: getEnvList("JWT_ALGORITHMS", "RS256"),
+
+		AutoCreatePlayers: getEnvBool("AUTO_CREATE_PLAYERS", true),
+		UserInfoEnabled:   getEnvBool("OIDC_USERINFO_ENABLED", true),
+
+		DefaultRole: getEnv("DEFAULT_ROLE", "member"),
+
+		SepaCreditorName: getEnv("SEPA_CREDITOR_NAME", ""),
+		SepaCreditorIBAN: getEnv("SEPA_CREDITOR_IBAN", ""),
+		SepaCreditorBIC:  getEnv("SEPA_CREDITOR_BIC", ""),
+		SepaCreditorID:   getEnv("SEPA_CREDITOR_ID", ""),
+
+		InvitationSecret: getEnv("INVITATION_SECRET", ""),
+		InvitationURL:    getEnv("INVITATION_URL", getEnv("FRONTEND_URL", "http://localhost:4200")+"/invitations/accept"),
+
+		MailSender:   getEnv("MAIL_SENDER", "log"),
+		MailFrom:     getEnv("MAIL_FROM", "noreply@darts.local"),
+		MailDir:      getEnv("MAIL_DIR", "mail"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+	}
+
+	leeway, err := time.ParseDuration(getEnv("JWT_LEEWAY", "30s"))
+	if err != nil || leeway < 0 {
+		return nil, fmt.Errorf("invalid JWT_LEEWAY '%s', use a duration like 30s", getEnv("JWT_LEEWAY", "30s"))
+	}
+	config.JWTLeeway = leeway
+
+	invitationTTL, err := time.ParseDuration(getEnv("INVITATION_TTL", "168h"))
+	if err != nil || invitationTTL <= 0 {
+		return nil, fmt.Errorf("invalid INVITATION_TTL '%s', use a duration like 168h", getEnv("INVITATION_TTL", "168h"))
+	}
+	config.InvitationTTL = invitationTTL
+
+	guestMembershipVisits, err := strconv.Atoi(getEnv("GUEST_MEMBERSHIP_VISITS", "3"))
+	if err != nil || guestMembershipVisits < 1 {
+		return nil, fmt.Errorf("invalid GUEST_MEMBERSHIP_VISITS '%s', use a positive number", getEnv("GUEST_MEMBERSHIP_VISITS", "3"))
+	}
+	config.GuestMembershipVisits = guestMembershipVisits
+
+	fixtureSingles, err := strconv.Atoi(getEnv("FIXTURE_SINGLES", "8"))
+	if err != nil || fixtureSingles < 0 {
+		return nil, fmt.Errorf("invalid FIXTURE_SINGLES '%s', use a number", getEnv("FIXTURE_SINGLES", "8"))
+	}
+	config.FixtureSingles = fixtureSingles
+
+	fixtureDoubles, err := strconv.Atoi(getEnv("FIXTURE_DOUBLES", "2"))
+	if err != nil || fixtureDoubles < 0 {
+		return nil, fmt.Errorf("invalid FIXTURE_DOUBLES '%s', use a number", getEnv("FIXTURE_DOUBLES", "2"))
+	}
+	config.FixtureDoubles = fixtureDoubles
+	if fixtureSingles+fixtureDoubles == 0 {
+		return nil, fmt.Errorf("FIXTURE_SINGLES and FIXTURE_DOUBLES must add up to at least one game")
 	}
 
 	// Validate required fields
-	if config.Auth0Domain == "" || config.Auth0ClientID == "" || config.Auth0ClientSecret == "" {
+	if config.AuthMode != "auth0" && config.AuthMode != "dev" {
+		return nil, fmt.Errorf("invalid AUTH_MODE '%s', use auth0 or dev", config.AuthMode)
+	}
+	if len(config.JWTAlgorithms) == 0 {
+		return nil, fmt.Errorf("JWT_ALGORITHMS must name at least one signing algorithm")
+	}
+	for _, algorithm := range config.JWTAlgorithms {
+		if strings.EqualFold(algorithm, "none") {
+			return nil, fmt.Errorf("JWT_ALGORITHMS must not allow unsigned tokens")
+		}
+	}
+	if config.MailSender != "log" && config.MailSender != "file" && config.MailSender != "smtp" {
+		return nil, fmt.Errorf("invalid MAIL_SENDER '%s', use log, file or smtp", config.MailSender)
+	}
+	if config.MailSender == "smtp" && config.SMTPHost == "" {
+		return nil, fmt.Errorf("SMTP_HOST is required for MAIL_SENDER=smtp")
+	}
+	if config.AuthMode == "auth0" && (config.Auth0Domain == "" || config.Auth0ClientID == "" || config.Auth0ClientSecret == "") {
 		return nil, fmt.Errorf("Auth0 configuration is missing. Please set AUTH0_DOMAIN, AUTH0_CLIENT_ID, and AUTH0_CLIENT_SECRET")
 	}
 
@@ -60,6 +193,26 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList reads a comma separated list and drops empty entries
+func getEnvList(key, defaultValue string) []string {
+	values := []string{}
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func calculateAuthHeader(clientID, clientSecret string) string {
 	if clientID == "" || clientSecret == "" {
 		return ""
@@ -25,18 +25,68 @@ func NewDatabase(databaseURL string) (*Database, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := registerTenantScope(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scope: %w", err)
+	}
+
+	// Existing rows are moved to the default club before the schema requires a club
+	if err := migrateClubs(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate clubs: %w", err)
+	}
+
+	// Replaced by a unique index on the names of policies that are not deleted
+	if err := db.Exec("DROP INDEX IF EXISTS idx_pricing_policy_club_name").Error; err != nil {
+		return nil, fmt.Errorf("failed to drop pricing policy name index: %w", err)
+	}
+
 	// Auto-migrate the schema
 	if err := db.AutoMigrate(
+		&models.Club{},
 		&models.Team{},
 		&models.Player{},
+		&models.Season{},
+		&models.TeamMembership{},
 		&models.GameMode{},
+		&models.Fixture{},
+		&models.PricingPolicy{},
 		&models.TrainingSession{},
+		&models.Guest{},
 		&models.TrainingPlayer{},
 		&models.TrainingGame{},
+		&models.Tournament{},
+		&models.TournamentEntrant{},
+		&models.TournamentMatch{},
+		&models.SwissBye{},
+		&models.TrainingSessionTransition{},
+		&models.LedgerEntry{},
+		&models.SessionExpense{},
+		&models.SepaMandate{},
+		&models.BankTransaction{},
+		&models.SepaCollection{},
+		&models.SepaCollectionItem{},
+		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.InvoiceCounter{},
+		&models.RoleAssignment{},
+		&models.APIKey{},
+		&models.AuditLog{},
+		&models.Invitation{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := migrateMoneyColumns(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate money columns: %w", err)
+	}
+
+	if err := migrateGuests(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate guests: %w", err)
+	}
+
+	if err := migrateTeamMemberships(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate team memberships: %w", err)
+	}
+
 	// Enable UUID extension for PostgreSQL
 	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error; err != nil {
 		log.Printf("Warning: Could not enable UUID extension: %v", err)
@@ -45,6 +95,234 @@ func NewDatabase(databaseURL string) (*Database, error) {
 	return &Database{DB: db}, nil
 }
 
+// moneyColumns lists the former float amount columns and the prefix of the
+// cents/currency columns that replace them. Table names are used instead of
+// models because the old columns are no longer part of the schema.
+var moneyColumns = []struct {
+	table     string
+	oldColumn string
+	prefix    string
+}{
+	{"training_sessions", "cost_per_player", "cost_per_player_"},
+	{"pricing_policies", "member_rate", "member_rate_"},
+	{"pricing_policies", "guest_rate", "guest_rate_"},
+	{"pricing_policies", "monthly_cap", "monthly_cap_"},
+	{"ledger_entries", "amount", "amount_"},
+}
+
+// migrateMoneyColumns converts float amounts to integer cents, rounding half
+// away from zero, and drops the old column once it has been copied
+func migrateMoneyColumns(db *gorm.DB) error {
+	for _, column := range moneyColumns {
+		if !db.Migrator().HasColumn(column.table, column.oldColumn) {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			update := fmt.Sprintf(
+				"UPDATE %s SET %scents = ROUND(%s::numeric * 100), %scurrency = ? WHERE %s IS NOT NULL",
+				column.table, column.prefix, column.oldColumn, column.prefix, column.oldColumn,
+			)
+			if err := tx.Exec(update, models.DefaultCurrency).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(column.table, column.oldColumn)
+		})
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", column.table, column.oldColumn, err)
+		}
+
+		log.Printf("Migrated %s.%s to cents", column.table, column.oldColumn)
+	}
+
+	return nil
+}
+
+// clubTables are the tables owned by a club, with the global unique indexes
+// that became unique per club and the columns whose rows pass on their club
+var clubTables = []struct {
+	table         string
+	globalIndexes []string
+	owners        []clubOwner
+}{
+	{"teams", []string{"idx_teams_name"}, nil},
+	{"players", []string{"idx_players_email", "idx_players_auth0_user_id"}, nil},
+	{"training_sessions", nil, nil},
+	{"game_modes", nil, nil},
+	{"role_assignments", []string{"idx_role_assignment"}, nil},
+	{"bank_transactions", []string{"idx_bank_transactions_bank_reference"}, nil},
+	{"api_keys", nil, nil},
+	{"training_games", nil, []clubOwner{{"training_session_id", "training_sessions"}, {"fixture_id", "fixtures"}}},
+	{"tournaments", nil, []clubOwner{{"training_session_id", "training_sessions"}}},
+	{"ledger_entries", nil, []clubOwner{{"player_id", "players"}}},
+	{"pricing_policies", []string{"idx_pricing_policies_name"}, nil},
+	{"invoices", []string{"idx_invoices_number"}, []clubOwner{{"player_id", "players"}}},
+	{"sepa_mandates", []string{"idx_sepa_mandates_mandate_reference"}, []clubOwner{{"player_id", "players"}}},
+}
+
+// clubOwner is a column referencing the row of ownerTable a row inherits its club from
+type clubOwner struct {
+	column     string
+	ownerTable string
+}
+
+// migrateClubs creates the default club and assigns it to all rows of a
+// deployment that predates clubs. Rows of tables added to a club later take
+// the club of their owner. AutoMigrate adds the NOT NULL constraint and the
+// per club unique indexes afterwards.
+func migrateClubs(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.Club{}); err != nil {
+		return err
+	}
+
+	defaultClub := models.Club{Name: "Standardverein", Slug: models.DefaultClubSlug}
+	if err := db.Where("slug = ?", models.DefaultClubSlug).FirstOrCreate(&defaultClub).Error; err != nil {
+		return fmt.Errorf("failed to create default club: %w", err)
+	}
+
+	for _, table := range clubTables {
+		if !db.Migrator().HasTable(table.table) || db.Migrator().HasColumn(table.table, "club_id") {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN club_id uuid", table.table)).Error; err != nil {
+				return err
+			}
+			for _, owner := range table.owners {
+				if !tx.Migrator().HasColumn(table.table, owner.column) || !tx.Migrator().HasColumn(owner.ownerTable, "club_id") {
+					continue
+				}
+				if err := tx.Exec(fmt.Sprintf("UPDATE %[1]s SET club_id = o.club_id FROM %[2]s o WHERE o.id = %[1]s.%[3]s AND %[1]s.club_id IS NULL",
+					table.table, owner.ownerTable, owner.column)).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(fmt.Sprintf("UPDATE %s SET club_id = ? WHERE club_id IS NULL", table.table), defaultClub.ID).Error; err != nil {
+				return err
+			}
+			for _, index := range table.globalIndexes {
+				if err := tx.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s", index)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", table.table, err)
+		}
+
+		log.Printf("Assigned %s to the default club", table.table)
+	}
+
+	return migrateInvoiceCounters(db, defaultClub.ID)
+}
+
+// migrateInvoiceCounters makes the invoice numbers a sequence per club, the
+// numbers issued before continue in the default club
+func migrateInvoiceCounters(db *gorm.DB, defaultClubID uuid.UUID) error {
+	if !db.Migrator().HasTable("invoice_counters") || db.Migrator().HasColumn("invoice_counters", "club_id") {
+		return nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE invoice_counters ADD COLUMN club_id uuid").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE invoice_counters SET club_id = ?", defaultClubID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("ALTER TABLE invoice_counters DROP CONSTRAINT IF EXISTS invoice_counters_pkey").Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE invoice_counters ADD PRIMARY KEY (club_id, year)").Error
+	})
+	if err != nil {
+		return fmt.Errorf("invoice_counters: %w", err)
+	}
+
+	log.Printf("Assigned invoice_counters to the default club")
+	return nil
+}
+
+// migrateGuests creates guest profiles for training entries that only have a
+// guest name, one per name and club, and links the entries and games to them
+func migrateGuests(db *gorm.DB) error {
+	var legacy int64
+	if err := db.Model(&models.TrainingPlayer{}).Where("is_guest = ? AND guest_id IS NULL AND guest_name IS NOT NULL", true).Count(&legacy).Error; err != nil {
+		return err
+	}
+	if legacy == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO guests (id, club_id, name, created_at, updated_at)
+			SELECT gen_random_uuid(), training_sessions.club_id, training_players.guest_name, MIN(training_players.created_at), NOW()
+			FROM training_players JOIN training_sessions ON training_sessions.id = training_players.training_session_id
+			WHERE training_players.is_guest AND training_players.guest_id IS NULL AND training_players.guest_name IS NOT NULL
+			GROUP BY training_sessions.club_id, training_players.guest_name`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE training_players SET guest_id = guests.id
+			FROM training_sessions, guests
+			WHERE training_sessions.id = training_players.training_session_id
+			AND guests.club_id = training_sessions.club_id AND guests.name = training_players.guest_name
+			AND training_players.is_guest AND training_players.guest_id IS NULL`).Error; err != nil {
+			return err
+		}
+		for _, slot := range []string{"1", "2"} {
+			if err := tx.Exec(fmt.Sprintf(`UPDATE training_games SET guest%[1]s_id = training_players.guest_id
+				FROM training_players
+				WHERE training_players.training_session_id = training_games.training_session_id
+				AND training_players.is_guest AND training_players.guest_name = training_games.guest%[1]s_name
+				AND training_games.player%[1]s_id IS NULL AND training_games.guest%[1]s_id IS NULL`, slot)).Error; err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Created guest profiles for %d guest entries", legacy)
+		return nil
+	})
+}
+
+// migrateTeamMemberships records the team of each player as an open
+// membership since the player was created, captains as captain, and drops
+// the former is_captain column
+func migrateTeamMemberships(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("players", "is_captain") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		insert := tx.Exec(`INSERT INTO team_memberships (id, club_id, team_id, player_id, role, valid_from, created_at, updated_at)
+			SELECT gen_random_uuid(), players.club_id, players.team_id, players.id,
+				CASE WHEN players.is_captain THEN ? ELSE ? END, players.created_at::date, NOW(), NOW()
+			FROM players
+			WHERE players.team_id IS NOT NULL AND players.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM team_memberships WHERE team_memberships.player_id = players.id)`,
+			models.TeamRoleCaptain, models.TeamRolePlayer)
+		if insert.Error != nil {
+			return insert.Error
+		}
+
+		var captainsWithoutTeam int64
+		if err := tx.Table("players").Where("is_captain AND team_id IS NULL AND deleted_at IS NULL").Count(&captainsWithoutTeam).Error; err != nil {
+			return err
+		}
+		if captainsWithoutTeam > 0 {
+			log.Printf("Warning: %d captains without a team lose the captain role", captainsWithoutTeam)
+		}
+
+		if err := tx.Migrator().DropColumn("players", "is_captain"); err != nil {
+			return err
+		}
+
+		log.Printf("Created %d team memberships from player teams", insert.RowsAffected)
+		return nil
+	})
+}
+
 func (d *Database) Close() error {
 	sqlDB, err := d.DB.DB()
 	if err != nil {
@@ -54,9 +332,24 @@ func (d *Database) Close() error {
 }
 
 func (d *Database) SeedDefaultData() error {
+	var clubs []models.Club
+	if err := d.DB.Find(&clubs).Error; err != nil {
+		return fmt.Errorf("failed to fetch clubs: %w", err)
+	}
+
+	for _, club := range clubs {
+		if err := SeedGameModes(d.DB, club.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SeedGameModes creates the default game modes of a club that has none yet
+func SeedGameModes(db *gorm.DB, clubID uuid.UUID) error {
 	// Check if game modes already exist
 	var count int64
-	d.DB.Model(&models.GameMode{}).Count(&count)
+	db.Model(&models.GameMode{}).Where("club_id = ?", clubID).Count(&count)
 	if count > 0 {
 		return nil // Data already seeded
 	}
@@ -68,29 +361,32 @@ func (d *Database) SeedDefaultData() error {
 
 	gameModes := []models.GameMode{
 		{
+			ClubID:      clubID,
 			Name:        "501 Double Out",
 			Description: utils.StringPtr("Classic 501 game, must finish on a double"),
 			Rules:       rules501,
 		},
 		{
+			ClubID:      clubID,
 			Name:        "Cricket",
 			Description: utils.StringPtr("Standard cricket game with numbers 20-15 and bull"),
 			Rules:       rulesCricket,
 		},
 		{
+			ClubID:      clubID,
 			Name:        "Around the Clock",
 			Description: utils.StringPtr("Hit numbers 1-20 in sequence, then bull"),
 			Rules:       rulesClock,
-	},
+		},
 	}
 
 	for _, gameMode := range gameModes {
-		if err := d.DB.Create(&gameMode).Error; err != nil {
+		if err := db.Create(&gameMode).Error; err != nil {
 			return fmt.Errorf("failed to create game mode %s: %w", gameMode.Name, err)
 		}
 	}
 
-	log.Println("Default game modes seeded successfully")
+	log.Printf("Default game modes seeded for club %s", clubID)
 	return nil
 }
 
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// clubContextKey stores the ID of the club a request acts for
+type clubContextKey struct{}
+
+// WithClub returns a context whose queries are limited to the given club
+func WithClub(ctx context.Context, clubID uuid.UUID) context.Context {
+	return context.WithValue(ctx, clubContextKey{}, clubID)
+}
+
+// ClubFromContext returns the club set by WithClub
+func ClubFromContext(ctx context.Context) (uuid.UUID, bool) {
+	if ctx == nil {
+		return uuid.Nil, false
+	}
+	clubID, ok := ctx.Value(clubContextKey{}).(uuid.UUID)
+	return clubID, ok && clubID != uuid.Nil
+}
+
+// registerTenantScope adds callbacks that scope every statement on a model
+// with a ClubID field to the club of the statement's context. Queries,
+// updates and deletes get a club_id condition, creates get the club assigned.
+// Child rows that are looked up by their own ID, like games and ledger
+// entries, carry a ClubID too instead of relying on InClub. Statements without
+// a club in their context, e.g. background jobs, and raw SQL are not scoped.
+func registerTenantScope(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeToClub); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scopeToClub); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeToClub); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeToClub); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", assignClub)
+}
+
+// clubField returns the club of the statement's context and the ClubID field
+// of its model, or nil if the statement is not scoped
+func clubField(db *gorm.DB) (uuid.UUID, *schema.Field) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return uuid.Nil, nil
+	}
+	clubID, ok := ClubFromContext(db.Statement.Context)
+	if !ok {
+		return uuid.Nil, nil
+	}
+	return clubID, db.Statement.Schema.LookUpField("ClubID")
+}
+
+func scopeToClub(db *gorm.DB) {
+	clubID, field := clubField(db)
+	if field == nil {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: clubID},
+	}})
+}
+
+func assignClub(db *gorm.DB) {
+	clubID, field := clubField(db)
+	if field == nil {
+		return
+	}
+
+	assign := func(value reflect.Value) {
+		if _, zero := field.ValueOf(db.Statement.Context, value); zero {
+			if err := field.Set(db.Statement.Context, value, clubID); err != nil {
+				db.AddError(err)
+			}
+		}
+	}
+
+	switch value := db.Statement.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			assign(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		assign(value)
+	}
+}
+
+// InClub limits a query on a table without club_id to rows whose column
+// references a row of the current club in ownerTable, e.g. ledger entries
+// of the club's players with InClub("ledger_entries.player_id", "players")
+func InClub(column string, ownerTable string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		clubID, ok := ClubFromContext(db.Statement.Context)
+		if !ok {
+			return db
+		}
+		return db.Where(fmt.Sprintf("%s IN (SELECT id FROM %s WHERE club_id = ?)", column, ownerTable), clubID)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	var entityID *uuid.UUID
+	if entityParam := c.Query("entity_id"); entityParam != "" {
+		id, err := uuid.Parse(entityParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity ID format"})
+			return
+		}
+		entityID = &id
+	}
+
+	logs, err := h.auditService.WithContext(c.Request.Context()).GetAuditLogs(entityID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
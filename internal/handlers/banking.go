@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxStatementSize limits uploaded bank statements to 10 MB
+const maxStatementSize = 10 << 20
+
+type BankingHandler struct {
+	bankingService *services.BankingService
+}
+
+func NewBankingHandler(bankingService *services.BankingService) *BankingHandler {
+	return &BankingHandler{
+		bankingService: bankingService,
+	}
+}
+
+func (h *BankingHandler) GetMandate(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	mandate, err := h.bankingService.WithContext(c.Request.Context()).GetMandate(id)
+	if err != nil {
+		if err.Error() == "mandate not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Mandate not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mandate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mandate.ToResponse())
+}
+
+func (h *BankingHandler) SaveMandate(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	var req models.SepaMandateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mandate, err := h.bankingService.WithContext(c.Request.Context()).SaveMandate(id, &req)
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		if err.Error() == "invalid IBAN" || err.Error() == "invalid signature date" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasSuffix(err.Error(), "is already in use") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save mandate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mandate.ToResponse())
+}
+
+func (h *BankingHandler) RevokeMandate(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	if err := h.bankingService.WithContext(c.Request.Context()).RevokeMandate(id); err != nil {
+		if err.Error() == "mandate not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Mandate not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke mandate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mandate revoked successfully"})
+}
+
+func (h *BankingHandler) ExportOutstandingCSV(c *gin.Context) {
+	data, err := h.bankingService.WithContext(c.Request.Context()).ExportOutstandingCSV()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export outstanding balances"})
+		return
+	}
+
+	filename := fmt.Sprintf("offene-betraege-%s.csv", time.Now().Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+func (h *BankingHandler) ExportSepaDirectDebit(c *gin.Context) {
+	var req models.SepaExportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	collection, data, err := h.bankingService.WithContext(c.Request.Context()).ExportSepaDirectDebit(&req, currentSubject(c))
+	if err != nil {
+		switch err.Error() {
+		case "sepa creditor is not configured":
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SEPA creditor is not configured"})
+		case "invalid collection date", "collection date must not be in the past":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "no outstanding balances with a sepa mandate":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export SEPA direct debits"})
+		}
+		return
+	}
+
+	filename := fmt.Sprintf("lastschriften-%s.xml", time.Now().Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("X-Sepa-Collection-ID", collection.ID.String())
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
+
+func (h *BankingHandler) GetSepaCollections(c *gin.Context) {
+	collections, err := h.bankingService.WithContext(c.Request.Context()).GetSepaCollections()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch SEPA collections"})
+		return
+	}
+
+	response := make([]models.SepaCollectionResponse, len(collections))
+	for i, collection := range collections {
+		response[i] = collection.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BankingHandler) GetSepaCollection(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SEPA collection ID format"})
+		return
+	}
+
+	collection, err := h.bankingService.WithContext(c.Request.Context()).GetSepaCollection(id)
+	if err != nil {
+		h.handleSepaCollectionError(c, err, "Failed to fetch SEPA collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection.ToResponse())
+}
+
+// ConfirmSepaCollection books the debits of a collection the bank executed
+func (h *BankingHandler) ConfirmSepaCollection(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SEPA collection ID format"})
+		return
+	}
+
+	collection, err := h.bankingService.WithContext(c.Request.Context()).ConfirmSepaCollection(id, currentSubject(c))
+	if err != nil {
+		h.handleSepaCollectionError(c, err, "Failed to confirm SEPA collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection.ToResponse())
+}
+
+// RejectSepaCollection releases the amounts of a collection the bank did not execute
+func (h *BankingHandler) RejectSepaCollection(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SEPA collection ID format"})
+		return
+	}
+
+	collection, err := h.bankingService.WithContext(c.Request.Context()).RejectSepaCollection(id)
+	if err != nil {
+		h.handleSepaCollectionError(c, err, "Failed to reject SEPA collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection.ToResponse())
+}
+
+func (h *BankingHandler) ImportBankStatement(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Statement file is required"})
+		return
+	}
+	if file.Size > maxStatementSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Statement file is too large"})
+		return
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read statement file"})
+		return
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read statement file"})
+		return
+	}
+
+	result, err := h.bankingService.WithContext(c.Request.Context()).ImportBankStatement(data, c.PostForm("format"), currentSubject(c))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import bank statement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *BankingHandler) GetBankTransactions(c *gin.Context) {
+	transactions, err := h.bankingService.WithContext(c.Request.Context()).GetBankTransactions(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bank transactions"})
+		return
+	}
+
+	// Convert to response format
+	response := make([]models.BankTransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		response[i] = transaction.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BankingHandler) AssignBankTransaction(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bank transaction ID format"})
+		return
+	}
+
+	var req models.BankTransactionAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	playerID, err := uuid.Parse(req.PlayerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	transaction, err := h.bankingService.WithContext(c.Request.Context()).AssignBankTransaction(id, playerID, currentSubject(c))
+	if err != nil {
+		h.handleBankTransactionError(c, err, "Failed to assign bank transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction.ToResponse())
+}
+
+func (h *BankingHandler) IgnoreBankTransaction(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bank transaction ID format"})
+		return
+	}
+
+	transaction, err := h.bankingService.WithContext(c.Request.Context()).IgnoreBankTransaction(id)
+	if err != nil {
+		h.handleBankTransactionError(c, err, "Failed to ignore bank transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction.ToResponse())
+}
+
+func (h *BankingHandler) handleBankTransactionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case err.Error() == "bank transaction not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bank transaction not found"})
+	case err.Error() == "player not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+	case strings.HasPrefix(err.Error(), "bank transaction is already"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+func (h *BankingHandler) handleSepaCollectionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case err.Error() == "sepa collection not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "SEPA collection not found"})
+	case strings.HasPrefix(err.Error(), "sepa collection is already"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
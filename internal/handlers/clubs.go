@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClubHandler struct {
+	clubService *services.ClubService
+}
+
+func NewClubHandler(clubService *services.ClubService) *ClubHandler {
+	return &ClubHandler{
+		clubService: clubService,
+	}
+}
+
+// GetCurrentClub returns the club the request acts for
+func (h *ClubHandler) GetCurrentClub(c *gin.Context) {
+	club, exists := c.Get("Club")
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, club)
+}
+
+// UpdateCurrentClubCreditor sets the creditor account of the request's club
+func (h *ClubHandler) UpdateCurrentClubCreditor(c *gin.Context) {
+	current, exists := c.Get("Club")
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
+
+	var req models.ClubCreditorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	club, err := h.clubService.UpdateCreditor(current.(*models.Club).ID, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid IBAN") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "club not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update club"})
+		return
+	}
+
+	c.JSON(http.StatusOK, club)
+}
+
+func (h *ClubHandler) GetClubs(c *gin.Context) {
+	clubs, err := h.clubService.GetClubs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clubs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, clubs)
+}
+
+func (h *ClubHandler) CreateClub(c *gin.Context) {
+	var req models.ClubCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	club, err := h.clubService.CreateClub(&req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid club slug") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasSuffix(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create club"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, club)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DevAuthHandler exposes the embedded development token issuer. Its routes are
+// only registered when AUTH_MODE=dev.
+type DevAuthHandler struct {
+	devIssuer *services.DevIssuer
+}
+
+func NewDevAuthHandler(devIssuer *services.DevIssuer) *DevAuthHandler {
+	return &DevAuthHandler{
+		devIssuer: devIssuer,
+	}
+}
+
+func (h *DevAuthHandler) GetOpenIDConfiguration(c *gin.Context) {
+	c.JSON(http.StatusOK, h.devIssuer.OpenIDConfiguration())
+}
+
+func (h *DevAuthHandler) GetJWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.devIssuer.JWKS())
+}
+
+func (h *DevAuthHandler) GetUsers(c *gin.Context) {
+	c.JSON(http.StatusOK, h.devIssuer.Users())
+}
+
+func (h *DevAuthHandler) MintToken(c *gin.Context) {
+	var req services.DevTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.devIssuer.MintToken(&req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid role") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mint token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
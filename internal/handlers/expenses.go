@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ExpenseHandler struct {
+	expenseService *services.ExpenseService
+}
+
+func NewExpenseHandler(expenseService *services.ExpenseService) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+	}
+}
+
+func (h *ExpenseHandler) GetExpenses(c *gin.Context) {
+	idParam := c.Param("id")
+	trainingID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	expenses, err := h.expenseService.WithContext(c.Request.Context()).GetExpensesByTrainingSession(trainingID)
+	if err != nil {
+		if err.Error() == "training session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session expenses"})
+		return
+	}
+
+	// Convert to response format
+	response := make([]models.SessionExpenseResponse, len(expenses))
+	for i, expense := range expenses {
+		response[i] = expense.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
+	idParam := c.Param("id")
+	trainingID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	var req models.SessionExpenseCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.expenseService.WithContext(c.Request.Context()).CreateExpense(trainingID, &req, currentSubject(c))
+	if err != nil {
+		h.handleExpenseError(c, err, "Failed to create session expense")
+		return
+	}
+
+	c.JSON(http.StatusCreated, expense.ToResponse())
+}
+
+func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
+	idParam := c.Param("id")
+	trainingID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	expenseIDParam := c.Param("expenseId")
+	expenseID, err := uuid.Parse(expenseIDParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID format"})
+		return
+	}
+
+	var req models.SessionExpenseUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.expenseService.WithContext(c.Request.Context()).UpdateExpense(trainingID, expenseID, &req)
+	if err != nil {
+		h.handleExpenseError(c, err, "Failed to update session expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, expense.ToResponse())
+}
+
+func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
+	idParam := c.Param("id")
+	trainingID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	expenseIDParam := c.Param("expenseId")
+	expenseID, err := uuid.Parse(expenseIDParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID format"})
+		return
+	}
+
+	if err := h.expenseService.WithContext(c.Request.Context()).DeleteExpense(trainingID, expenseID); err != nil {
+		h.handleExpenseError(c, err, "Failed to delete session expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session expense deleted successfully"})
+}
+
+func (h *ExpenseHandler) handleExpenseError(c *gin.Context, err error, fallback string) {
+	switch {
+	case err.Error() == "training session not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
+	case err.Error() == "session expense not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session expense not found"})
+	case strings.HasPrefix(err.Error(), "cannot change expenses"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == "expense amount must be positive",
+		err.Error() == "invalid payer ID",
+		err.Error() == "payer not found",
+		strings.HasPrefix(err.Error(), "invalid split mode"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
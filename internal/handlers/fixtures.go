@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FixtureHandler struct {
+	fixtureService *services.FixtureService
+}
+
+func NewFixtureHandler(fixtureService *services.FixtureService) *FixtureHandler {
+	return &FixtureHandler{
+		fixtureService: fixtureService,
+	}
+}
+
+func (h *FixtureHandler) GetFixtures(c *gin.Context) {
+	var teamID, seasonID *uuid.UUID
+	if value := c.Query("team_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID format"})
+			return
+		}
+		teamID = &id
+	}
+	if value := c.Query("season_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID format"})
+			return
+		}
+		seasonID = &id
+	}
+
+	fixtures, err := h.fixtureService.WithContext(c.Request.Context()).GetFixtures(teamID, seasonID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fixtures"})
+		return
+	}
+
+	response := make([]models.FixtureResponse, len(fixtures))
+	for i, fixture := range fixtures {
+		response[i] = fixture.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *FixtureHandler) GetFixtureByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fixture ID format"})
+		return
+	}
+
+	fixture, err := h.fixtureService.WithContext(c.Request.Context()).GetFixtureByID(id)
+	if err != nil {
+		writeFixtureError(c, err, "Failed to fetch fixture")
+		return
+	}
+
+	c.JSON(http.StatusOK, fixture.ToResponse())
+}
+
+func (h *FixtureHandler) CreateFixture(c *gin.Context) {
+	var req models.FixtureCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fixture, err := h.fixtureService.WithContext(c.Request.Context()).CreateFixture(&req)
+	if err != nil {
+		writeFixtureError(c, err, "Failed to create fixture")
+		return
+	}
+
+	c.JSON(http.StatusCreated, fixture.ToResponse())
+}
+
+func (h *FixtureHandler) UpdateFixture(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fixture ID format"})
+		return
+	}
+
+	var req models.FixtureUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fixture, err := h.fixtureService.WithContext(c.Request.Context()).UpdateFixture(id, &req)
+	if err != nil {
+		writeFixtureError(c, err, "Failed to update fixture")
+		return
+	}
+
+	c.JSON(http.StatusOK, fixture.ToResponse())
+}
+
+func (h *FixtureHandler) DeleteFixture(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fixture ID format"})
+		return
+	}
+
+	if err := h.fixtureService.WithContext(c.Request.Context()).DeleteFixture(id); err != nil {
+		writeFixtureError(c, err, "Failed to delete fixture")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fixture deleted successfully"})
+}
+
+// SetLineup replaces the lineup, allowed for team managers and the captains
+// of the fixture's team
+func (h *FixtureHandler) SetLineup(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fixture ID format"})
+		return
+	}
+
+	var req models.FixtureLineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	playerID, canManage := fixtureCaller(c)
+	fixture, err := h.fixtureService.WithContext(c.Request.Context()).SetLineup(id, &req, playerID, canManage, currentSubject(c))
+	if err != nil {
+		writeFixtureError(c, err, "Failed to set lineup")
+		return
+	}
+
+	c.JSON(http.StatusOK, fixture.ToResponse())
+}
+
+func (h *FixtureHandler) RecordGameResult(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fixture ID format"})
+		return
+	}
+	gameID, err := uuid.Parse(c.Param("gameId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID format"})
+		return
+	}
+
+	var req models.FixtureGameResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	playerID, canManage := fixtureCaller(c)
+	game, err := h.fixtureService.WithContext(c.Request.Context()).RecordGameResult(id, gameID, &req, playerID, canManage)
+	if err != nil {
+		writeFixtureError(c, err, "Failed to record game result")
+		return
+	}
+
+	c.JSON(http.StatusOK, game.ToResponse())
+}
+
+// fixtureCaller returns the caller's player and whether they manage teams
+func fixtureCaller(c *gin.Context) (*uuid.UUID, bool) {
+	var playerID *uuid.UUID
+	if principal := currentPrincipal(c); principal != nil {
+		playerID = principal.PlayerID
+	}
+	return playerID, hasPermission(c, models.PermissionTeamsWrite)
+}
+
+// writeFixtureError maps the errors of the fixture service methods to status
+// codes, fallback is the message for unexpected errors
+func writeFixtureError(c *gin.Context, err error, fallback string) {
+	switch {
+	case strings.HasSuffix(err.Error(), " not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "only a captain"):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "cannot "):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "invalid ") || strings.HasPrefix(err.Error(), "match format") ||
+		strings.HasPrefix(err.Error(), "fixture must") || strings.HasPrefix(err.Error(), "lineup needs") ||
+		strings.HasPrefix(err.Error(), "game ") || strings.HasPrefix(err.Error(), "all players"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
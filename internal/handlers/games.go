@@ -21,7 +21,7 @@ func NewGameHandler(gameService *services.GameService) *GameHandler {
 }
 
 func (h *GameHandler) GetAllGameModes(c *gin.Context) {
-	gameModes, err := h.gameService.GetAllGameModes()
+	gameModes, err := h.gameService.WithContext(c.Request.Context()).GetAllGameModes()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch game modes"})
 		return
@@ -44,7 +44,7 @@ func (h *GameHandler) GetGamesByTrainingSession(c *gin.Context) {
 		return
 	}
 
-	games, err := h.gameService.GetGamesByTrainingSession(sessionID)
+	games, err := h.gameService.WithContext(c.Request.Context()).GetGamesByTrainingSession(sessionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch training games"})
 		return
@@ -71,6 +71,8 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 		GameModeID uuid.UUID  `json:"game_mode_id" binding:"required"`
 		Player1ID  *uuid.UUID `json:"player1_id"`
 		Player2ID  *uuid.UUID `json:"player2_id"`
+		Guest1ID   *uuid.UUID `json:"guest1_id"`
+		Guest2ID   *uuid.UUID `json:"guest2_id"`
 		Guest1Name *string    `json:"guest1_name"`
 		Guest2Name *string    `json:"guest2_name"`
 	}
@@ -79,11 +81,13 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 		return
 	}
 
-	game, err := h.gameService.CreateGame(
+	game, err := h.gameService.WithContext(c.Request.Context()).CreateGame(
 		trainingSessionID,
 		req.GameModeID,
 		req.Player1ID,
 		req.Player2ID,
+		req.Guest1ID,
+		req.Guest2ID,
 		req.Guest1Name,
 		req.Guest2Name,
 	)
@@ -96,7 +100,7 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Game mode not found"})
 			return
 		}
-		if err.Error() == "player 1 not found" || err.Error() == "player 2 not found" {
+		if err.Error() == "player 1 not found" || err.Error() == "player 2 not found" || err.Error() == "guest not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
@@ -104,7 +108,7 @@ func (h *GameHandler) CreateGame(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		if err.Error() == "exactly two players are required for each game" {
+		if err.Error() == "exactly two players are required for each game" || err.Error() == "guest name is ambiguous, use the guest ID" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -134,7 +138,7 @@ func (h *GameHandler) UpdateGame(c *gin.Context) {
 		return
 	}
 
-	game, err := h.gameService.UpdateGame(id, req.Player1Score, req.Player2Score, req.Status, req.Winner)
+	game, err := h.gameService.WithContext(c.Request.Context()).UpdateGame(id, req.Player1Score, req.Player2Score, req.Status, req.Winner)
 	if err != nil {
 		if err.Error() == "game not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
@@ -144,6 +148,14 @@ func (h *GameHandler) UpdateGame(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if err.Error() == "knockout games cannot end in a draw" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "fixture games are scored via the fixture" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update game"})
 		return
 	}
@@ -159,13 +171,15 @@ func (h *GameHandler) DeleteGame(c *gin.Context) {
 		return
 	}
 
-	err = h.gameService.DeleteGame(id)
+	err = h.gameService.WithContext(c.Request.Context()).DeleteGame(id)
 	if err != nil {
 		if err.Error() == "game not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
 			return
 		}
-		if err.Error() == "cannot delete game that is in progress or completed" {
+		if err.Error() == "cannot delete game that is in progress or completed" ||
+			err.Error() == "cannot delete game that belongs to a tournament" ||
+			err.Error() == "cannot delete game that belongs to a fixture" {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
@@ -186,13 +200,14 @@ func (h *GameHandler) GenerateGames(c *gin.Context) {
 
 	var req struct {
 		GameModeID uuid.UUID `json:"game_mode_id" binding:"required"`
+		Format     string    `json:"format"` // round_robin (default) or swiss
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	games, err := h.gameService.GenerateGamesForTraining(trainingSessionID, req.GameModeID)
+	games, err := h.gameService.WithContext(c.Request.Context()).GenerateGamesForTraining(trainingSessionID, req.GameModeID, req.Format)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
@@ -206,6 +221,14 @@ func (h *GameHandler) GenerateGames(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if err.Error() == "invalid pairing format: "+req.Format ||
+			err.Error() == "can only generate swiss rounds for planned or active training sessions" ||
+			err.Error() == "cannot mix swiss rounds with other games" ||
+			err.Error() == "previous swiss round is not finished yet" ||
+			err.Error() == "no more swiss rounds possible without repeat pairings" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate games"})
 		return
 	}
@@ -219,6 +242,28 @@ func (h *GameHandler) GenerateGames(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// GetSwissStandings returns the Swiss table with Buchholz tiebreaks for a training session
+func (h *GameHandler) GetSwissStandings(c *gin.Context) {
+	sessionIDParam := c.Param("sessionId")
+	sessionID, err := uuid.Parse(sessionIDParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	standings, err := h.gameService.WithContext(c.Request.Context()).GetSwissStandings(sessionID)
+	if err != nil {
+		if err.Error() == "training session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch swiss standings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
 // GetAllGames returns all games with optional filtering
 func (h *GameHandler) GetAllGames(c *gin.Context) {
 	// Parse query parameters
@@ -248,7 +293,7 @@ func (h *GameHandler) GetAllGames(c *gin.Context) {
 		status = &statusParam
 	}
 
-	games, err := h.gameService.GetAllGames(trainingSessionID, playerID, status)
+	games, err := h.gameService.WithContext(c.Request.Context()).GetAllGames(trainingSessionID, playerID, status)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch games"})
 		return
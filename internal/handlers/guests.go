@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GuestHandler struct {
+	guestService *services.GuestService
+}
+
+func NewGuestHandler(guestService *services.GuestService) *GuestHandler {
+	return &GuestHandler{
+		guestService: guestService,
+	}
+}
+
+// GetGuests lists the guests of the club, ?search= filters by name
+func (h *GuestHandler) GetGuests(c *gin.Context) {
+	guests, stats, err := h.guestService.WithContext(c.Request.Context()).GetGuests(c.Query("search"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch guests"})
+		return
+	}
+
+	response := make([]models.GuestResponse, len(guests))
+	for i, guest := range guests {
+		response[i] = guest.ToResponse(stats[guest.ID])
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMembershipSuggestions lists frequent guests who could become members
+func (h *GuestHandler) GetMembershipSuggestions(c *gin.Context) {
+	guests, stats, err := h.guestService.WithContext(c.Request.Context()).GetMembershipSuggestions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch membership suggestions"})
+		return
+	}
+
+	response := make([]models.GuestResponse, len(guests))
+	for i, guest := range guests {
+		response[i] = guest.ToResponse(stats[guest.ID])
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *GuestHandler) GetGuestByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest ID format"})
+		return
+	}
+
+	guest, stats, err := h.guestService.WithContext(c.Request.Context()).GetGuestByID(id)
+	if err != nil {
+		if err.Error() == "guest not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Guest not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch guest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, guest.ToResponse(stats))
+}
+
+func (h *GuestHandler) CreateGuest(c *gin.Context) {
+	var req models.GuestCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	guest, err := h.guestService.WithContext(c.Request.Context()).CreateGuest(&req)
+	if err != nil {
+		if err.Error() == "inviting player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create guest"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, guest.ToResponse(models.GuestStats{}))
+}
+
+func (h *GuestHandler) UpdateGuest(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest ID format"})
+		return
+	}
+
+	var req models.GuestUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	guestService := h.guestService.WithContext(c.Request.Context())
+	if _, err := guestService.UpdateGuest(id, &req); err != nil {
+		if err.Error() == "guest not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Guest not found"})
+			return
+		}
+		if err.Error() == "inviting player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update guest"})
+		return
+	}
+
+	guest, stats, err := guestService.GetGuestByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch guest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, guest.ToResponse(stats))
+}
+
+func (h *GuestHandler) DeleteGuest(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest ID format"})
+		return
+	}
+
+	if err := h.guestService.WithContext(c.Request.Context()).DeleteGuest(id); err != nil {
+		if err.Error() == "guest not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Guest not found"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "cannot delete guest") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete guest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Guest deleted successfully"})
+}
+
+// ConvertGuest makes a guest a member and moves the guest's trainings and
+// games to the new player
+func (h *GuestHandler) ConvertGuest(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest ID format"})
+		return
+	}
+
+	var req models.GuestConvertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	player, err := h.guestService.WithContext(c.Request.Context()).ConvertGuest(id, &req, currentSubject(c))
+	if err != nil {
+		switch {
+		case err.Error() == "guest not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Guest not found"})
+		case err.Error() == "team not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		case err.Error() == "email is required to convert a guest" || strings.HasPrefix(err.Error(), "invalid"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err.Error() == "guest is already converted" || strings.HasSuffix(err.Error(), "already exists"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to convert guest"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, player.ToResponse(playerViewer(c)))
+}
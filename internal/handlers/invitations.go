@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvitationHandler struct {
+	invitationService *services.InvitationService
+}
+
+func NewInvitationHandler(invitationService *services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+func (h *InvitationHandler) GetInvitations(c *gin.Context) {
+	invitations, err := h.invitationService.WithContext(c.Request.Context()).GetInvitations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+
+	response := make([]models.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		response[i] = invitation.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req models.InvitationCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, err := h.invitationService.WithContext(c.Request.Context()).CreateInvitation(&req, currentSubject(c))
+	if err != nil {
+		if !writeInvitationError(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation.ToResponse())
+}
+
+// ResendInvitation mails a new link, the previous link stops working
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID format"})
+		return
+	}
+
+	invitation, err := h.invitationService.WithContext(c.Request.Context()).ResendInvitation(id)
+	if err != nil {
+		if !writeInvitationError(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resend invitation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation.ToResponse())
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID format"})
+		return
+	}
+
+	if err := h.invitationService.WithContext(c.Request.Context()).RevokeInvitation(id); err != nil {
+		if !writeInvitationError(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invitation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked successfully"})
+}
+
+// AcceptInvitation links the login of the caller to the invited player
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	if _, isAPIKey := c.Get("APIKey"); isAPIKey {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invitations can only be accepted by users"})
+		return
+	}
+	subject := currentSubject(c)
+	if subject == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	if strings.HasSuffix(*subject, "@clients") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invitations can only be accepted by users"})
+		return
+	}
+
+	var req models.InvitationAcceptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	player, err := h.invitationService.WithContext(c.Request.Context()).AcceptInvitation(req.Token, *subject)
+	if err != nil {
+		if !writeInvitationError(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invitation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player.ToResponse(models.PlayerViewer{PlayerID: &player.ID}))
+}
+
+// writeInvitationError answers expected invitation errors and reports whether
+// it did
+func writeInvitationError(c *gin.Context, err error) bool {
+	switch err.Error() {
+	case "invalid player ID format", "invalid invitation":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "player not found", "invitation not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invitation has expired":
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case "player is already linked to an account", "player already has a pending invitation",
+		"invitation is no longer valid", "account is already linked to another player", "cannot invite an anonymized player":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "failed to send invitation mail":
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send invitation mail"})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvoiceHandler struct {
+	invoiceService *services.InvoiceService
+}
+
+func NewInvoiceHandler(invoiceService *services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+func (h *InvoiceHandler) GenerateInvoices(c *gin.Context) {
+	var req models.InvoiceGenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoices, err := h.invoiceService.WithContext(c.Request.Context()).GenerateMonthlyInvoices(req.Year, req.Month)
+	if err != nil {
+		if err.Error() == "invalid invoice period" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate invoices"})
+		return
+	}
+
+	// Convert to response format
+	response := models.InvoiceGenerateResponse{
+		PeriodYear:  req.Year,
+		PeriodMonth: req.Month,
+		Invoices:    make([]models.InvoiceResponse, len(invoices)),
+	}
+	for i, invoice := range invoices {
+		response.Invoices[i] = invoice.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *InvoiceHandler) GetPlayerInvoices(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	invoices, err := h.invoiceService.WithContext(c.Request.Context()).GetPlayerInvoices(id)
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoices"})
+		return
+	}
+
+	// Convert to response format
+	response := make([]models.InvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		response[i] = invoice.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetInvoice downloads an invoice as PDF (default) or HTML, ?format=json
+// returns the invoice data
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+	invoiceID, err := uuid.Parse(c.Param("invoiceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID format"})
+		return
+	}
+
+	invoice, err := h.invoiceService.WithContext(c.Request.Context()).GetInvoice(id, invoiceID)
+	if err != nil {
+		if err.Error() == "invoice not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice"})
+		return
+	}
+
+	switch c.DefaultQuery("format", "pdf") {
+	case "pdf":
+		data, err := h.invoiceService.WithContext(c.Request.Context()).RenderPDF(invoice)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render invoice"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "rechnung-"+invoice.Number+".pdf"))
+		c.Data(http.StatusOK, "application/pdf", data)
+	case "html":
+		data, err := h.invoiceService.WithContext(c.Request.Context()).RenderHTML(invoice)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render invoice"})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+	case "json":
+		c.JSON(http.StatusOK, invoice.ToResponse())
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, use pdf, html or json"})
+	}
+}
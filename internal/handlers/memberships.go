@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (h *TeamHandler) GetTeamMemberships(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID format"})
+		return
+	}
+
+	memberships, err := h.teamService.WithContext(c.Request.Context()).GetTeamMemberships(teamID)
+	if err != nil {
+		if err.Error() == "team not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team memberships"})
+		return
+	}
+
+	response := make([]models.TeamMembershipResponse, len(memberships))
+	for i, membership := range memberships {
+		response[i] = membership.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TeamHandler) GetPlayerMemberships(c *gin.Context) {
+	playerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	memberships, err := h.teamService.WithContext(c.Request.Context()).GetPlayerMemberships(playerID)
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team memberships"})
+		return
+	}
+
+	response := make([]models.TeamMembershipResponse, len(memberships))
+	for i, membership := range memberships {
+		response[i] = membership.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TeamHandler) CreateMembership(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID format"})
+		return
+	}
+
+	var req models.TeamMembershipCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	membership, err := h.teamService.WithContext(c.Request.Context()).CreateMembership(teamID, &req)
+	if err != nil {
+		writeMembershipError(c, err, "Failed to create team membership")
+		return
+	}
+
+	c.JSON(http.StatusCreated, membership.ToResponse())
+}
+
+func (h *TeamHandler) UpdateMembership(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID format"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("membershipId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid membership ID format"})
+		return
+	}
+
+	var req models.TeamMembershipUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	membership, err := h.teamService.WithContext(c.Request.Context()).UpdateMembership(teamID, id, &req)
+	if err != nil {
+		writeMembershipError(c, err, "Failed to update team membership")
+		return
+	}
+
+	c.JSON(http.StatusOK, membership.ToResponse())
+}
+
+func (h *TeamHandler) DeleteMembership(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID format"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("membershipId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid membership ID format"})
+		return
+	}
+
+	if err := h.teamService.WithContext(c.Request.Context()).DeleteMembership(teamID, id); err != nil {
+		writeMembershipError(c, err, "Failed to delete team membership")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Team membership deleted successfully"})
+}
+
+// writeMembershipError maps the errors of the membership service methods to
+// status codes, fallback is the message for unexpected errors
+func writeMembershipError(c *gin.Context, err error, fallback string) {
+	switch {
+	case err.Error() == "team not found" || err.Error() == "player not found" ||
+		err.Error() == "season not found" || err.Error() == "membership not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "invalid ") || strings.HasPrefix(err.Error(), "membership must"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case strings.HasSuffix(err.Error(), "in this period"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MergeHandler struct {
+	mergeService *services.MergeService
+}
+
+func NewMergeHandler(mergeService *services.MergeService) *MergeHandler {
+	return &MergeHandler{
+		mergeService: mergeService,
+	}
+}
+
+// MergeIntoPlayer merges a duplicate player or a guest into the player of the
+// URL. With dry_run the result only previews the changes.
+func (h *MergeHandler) MergeIntoPlayer(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	var req models.PlayerMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.mergeService.WithContext(c.Request.Context()).MergeIntoPlayer(id, &req, currentSubject(c))
+	if err != nil {
+		switch {
+		case err.Error() == "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		case err.Error() == "source player not found" || err.Error() == "guest not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "either") || strings.HasPrefix(err.Error(), "invalid") || err.Error() == "cannot merge a player into itself":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "both players") || strings.HasSuffix(err.Error(), "the same training session") ||
+			err.Error() == "cannot merge into an anonymized player" || err.Error() == "guest is already converted":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge player"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
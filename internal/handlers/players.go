@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"darts-training-app/internal/models"
 	"darts-training-app/internal/services"
@@ -37,16 +38,16 @@ func (h *PlayerHandler) GetAllPlayers(c *gin.Context) {
 
 		// Check if we should only return active players
 		if activeOnlyParam == "true" {
-			players, err = h.playerService.GetActivePlayersByTeam(teamID)
+			players, err = h.playerService.WithContext(c.Request.Context()).GetActivePlayersByTeam(teamID)
 		} else {
-			players, err = h.playerService.GetPlayersByTeam(teamID)
+			players, err = h.playerService.WithContext(c.Request.Context()).GetPlayersByTeam(teamID)
 		}
 	} else {
 		// Check if we should only return active players
 		if activeOnlyParam == "true" {
-			players, err = h.playerService.GetActivePlayers()
+			players, err = h.playerService.WithContext(c.Request.Context()).GetActivePlayers()
 		} else {
-			players, err = h.playerService.GetAllPlayers()
+			players, err = h.playerService.WithContext(c.Request.Context()).GetAllPlayers()
 		}
 	}
 
@@ -56,9 +57,10 @@ func (h *PlayerHandler) GetAllPlayers(c *gin.Context) {
 	}
 
 	// Convert to response format
+	viewer := playerViewer(c)
 	response := make([]models.PlayerResponse, len(players))
 	for i, player := range players {
-		response[i] = player.ToResponse()
+		response[i] = player.ToResponse(viewer)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -72,7 +74,8 @@ func (h *PlayerHandler) GetPlayerByID(c *gin.Context) {
 		return
 	}
 
-	player, err := h.playerService.GetPlayerByID(id)
+	playerService := h.playerService.WithContext(c.Request.Context())
+	player, err := playerService.GetPlayerByID(id)
 	if err != nil {
 		if err.Error() == "player not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
@@ -82,7 +85,13 @@ func (h *PlayerHandler) GetPlayerByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, player.ToResponseWithTeam())
+	stats, err := playerService.GetPlayerStats(player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, player.ToResponseWithTeam(playerViewer(c), stats))
 }
 
 func (h *PlayerHandler) GetPlayersByTeam(c *gin.Context) {
@@ -93,16 +102,17 @@ func (h *PlayerHandler) GetPlayersByTeam(c *gin.Context) {
 		return
 	}
 
-	players, err := h.playerService.GetPlayersByTeam(id)
+	players, err := h.playerService.WithContext(c.Request.Context()).GetPlayersByTeam(id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team players"})
 		return
 	}
 
 	// Convert to response format
+	viewer := playerViewer(c)
 	response := make([]models.PlayerResponse, len(players))
 	for i, player := range players {
-		response[i] = player.ToResponse()
+		response[i] = player.ToResponse(viewer)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -115,7 +125,7 @@ func (h *PlayerHandler) CreatePlayer(c *gin.Context) {
 		return
 	}
 
-	player, err := h.playerService.CreatePlayer(&req)
+	player, err := h.playerService.WithContext(c.Request.Context()).CreatePlayer(&req)
 	if err != nil {
 		if err.Error() == "player with email '"+req.Email+"' already exists" {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
@@ -125,11 +135,15 @@ func (h *PlayerHandler) CreatePlayer(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.HasPrefix(err.Error(), "invalid player category") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create player"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, player.ToResponse())
+	c.JSON(http.StatusCreated, player.ToResponse(playerViewer(c)))
 }
 
 func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
@@ -146,7 +160,14 @@ func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
 		return
 	}
 
-	player, err := h.playerService.UpdatePlayer(id, &req)
+	// Members editing their own profile may not change club-managed fields
+	if !hasPermission(c, models.PermissionPlayersWrite) &&
+		(req.IsActive != nil || req.Category != nil || req.TeamID != nil) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only name, email and nickname can be changed on your own profile"})
+		return
+	}
+
+	player, err := h.playerService.WithContext(c.Request.Context()).UpdatePlayer(id, &req)
 	if err != nil {
 		if err.Error() == "player not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
@@ -156,6 +177,14 @@ func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.HasPrefix(err.Error(), "invalid player category") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "player is already a member of the team in this period" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		if err.Error() == "player with email '"+*req.Email+"' already exists" {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
@@ -164,7 +193,7 @@ func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, player.ToResponse())
+	c.JSON(http.StatusOK, player.ToResponse(playerViewer(c)))
 }
 
 func (h *PlayerHandler) DeletePlayer(c *gin.Context) {
@@ -175,7 +204,7 @@ func (h *PlayerHandler) DeletePlayer(c *gin.Context) {
 		return
 	}
 
-	err = h.playerService.DeletePlayer(id)
+	err = h.playerService.WithContext(c.Request.Context()).DeletePlayer(id)
 	if err != nil {
 		if err.Error() == "player not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
@@ -195,7 +224,8 @@ func (h *PlayerHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
-	player, err := h.playerService.GetPlayerByAuth0ID(userID.(string))
+	playerService := h.playerService.WithContext(c.Request.Context())
+	player, err := playerService.GetPlayerByAuth0ID(userID.(string))
 	if err != nil {
 		if err.Error() == "player not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Player profile not found. Please create a player profile first."})
@@ -205,29 +235,57 @@ func (h *PlayerHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, player.ToResponseWithTeam())
+	stats, err := playerService.GetPlayerStats(player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, player.ToResponseWithTeam(playerViewer(c), stats))
 }
 
-func (h *PlayerHandler) CreateCurrentUser(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+// UpdateCurrentUser changes the self-service fields and privacy settings of
+// the caller's own player profile
+func (h *PlayerHandler) UpdateCurrentUser(c *gin.Context) {
+	principal := currentPrincipal(c)
+	if principal == nil || principal.PlayerID == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player profile not found. Please create a player profile first."})
+		return
+	}
+
+	var req models.PlayerProfileUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	userEmail, _ := c.Get("user_email")
-	userName, _ := c.Get("user_name")
-	userNickname, _ := c.Get("user_nickname")
+	player, err := h.playerService.WithContext(c.Request.Context()).UpdateProfile(*principal.PlayerID, &req)
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid throwing hand") || strings.HasPrefix(err.Error(), "invalid dart weight") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update player profile"})
+		return
+	}
 
-	// Check if player already exists
-	_, err := h.playerService.GetPlayerByAuth0ID(userID.(string))
-	if err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Player profile already exists"})
+	c.JSON(http.StatusOK, player.ToResponse(playerViewer(c)))
+}
+
+func (h *PlayerHandler) CreateCurrentUser(c *gin.Context) {
+	principal := currentPrincipal(c)
+	if principal == nil || principal.Subject == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
-	if err.Error() != "player not found" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing player"})
+	// Check if player already exists
+	if principal.PlayerID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Player profile already exists"})
 		return
 	}
 
@@ -238,25 +296,40 @@ func (h *PlayerHandler) CreateCurrentUser(c *gin.Context) {
 	}
 
 	// Override with authenticated user data
-	req.Email = userEmail.(string)
+	if principal.Email != "" {
+		req.Email = principal.Email
+	}
 	if req.Name == "" {
-		req.Name = userName.(string)
+		req.Name = principal.Name
 	}
-	if req.Nickname == nil {
-		nickname := userNickname.(string)
+	if req.Nickname == nil && principal.Nickname != "" {
+		nickname := principal.Nickname
 		req.Nickname = &nickname
 	}
+	// Club-managed fields are not self-service
+	req.IsActive = true
+	req.Category = nil
+	req.TeamID = nil
 
-	player, err := h.playerService.CreatePlayer(&req)
+	player, err := h.playerService.WithContext(c.Request.Context()).CreatePlayer(&req)
 	if err != nil {
 		if err.Error() == "player with email '"+req.Email+"' already exists" {
-			// Player exists with email but not Auth0 ID, link them
-			existingPlayer, fetchErr := h.playerService.GetPlayerByAuth0ID(userID.(string))
-			if fetchErr != nil {
+			// An existing player is only linked when the provider verified the email
+			if !principal.EmailVerified || principal.Email != req.Email {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			existingPlayer, linkErr := h.playerService.WithContext(c.Request.Context()).FindOrCreatePlayerByAuth0(models.Auth0User{
+				Sub:           principal.Subject,
+				Email:         principal.Email,
+				EmailVerified: principal.EmailVerified,
+				Name:          req.Name,
+			})
+			if linkErr != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link existing player profile"})
 				return
 			}
-			c.JSON(http.StatusOK, existingPlayer.ToResponse())
+			c.JSON(http.StatusOK, existingPlayer.ToResponse(models.PlayerViewer{PlayerID: &existingPlayer.ID}))
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create player profile"})
@@ -264,12 +337,12 @@ func (h *PlayerHandler) CreateCurrentUser(c *gin.Context) {
 	}
 
 	// Link with Auth0 ID
-	if err := h.playerService.UpdatePlayerAuth0ID(player.ID, userID.(string)); err != nil {
+	if err := h.playerService.WithContext(c.Request.Context()).UpdatePlayerAuth0ID(player.ID, principal.Subject); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link player profile with Auth0"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, player.ToResponse())
+	c.JSON(http.StatusCreated, player.ToResponse(models.PlayerViewer{PlayerID: &player.ID}))
 }
 
 // ActivatePlayer activates a player
@@ -281,7 +354,7 @@ func (h *PlayerHandler) ActivatePlayer(c *gin.Context) {
 		return
 	}
 
-	err = h.playerService.ActivatePlayer(id)
+	err = h.playerService.WithContext(c.Request.Context()).ActivatePlayer(id)
 	if err != nil {
 		if err.Error() == "player not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
@@ -303,7 +376,7 @@ func (h *PlayerHandler) DeactivatePlayer(c *gin.Context) {
 		return
 	}
 
-	err = h.playerService.DeactivatePlayer(id)
+	err = h.playerService.WithContext(c.Request.Context()).DeactivatePlayer(id)
 	if err != nil {
 		if err.Error() == "player not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
@@ -315,3 +388,13 @@ func (h *PlayerHandler) DeactivatePlayer(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Player deactivated successfully"})
 }
+
+// playerViewer describes the caller for the privacy settings of the players
+// in a response
+func playerViewer(c *gin.Context) models.PlayerViewer {
+	viewer := models.PlayerViewer{CanManage: hasPermission(c, models.PermissionPlayersWrite)}
+	if principal := currentPrincipal(c); principal != nil {
+		viewer.PlayerID = principal.PlayerID
+	}
+	return viewer
+}
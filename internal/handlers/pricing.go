@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PricingHandler struct {
+	pricingService *services.PricingService
+}
+
+func NewPricingHandler(pricingService *services.PricingService) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+	}
+}
+
+func (h *PricingHandler) GetAllPricingPolicies(c *gin.Context) {
+	policies, err := h.pricingService.WithContext(c.Request.Context()).GetAllPricingPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pricing policies"})
+		return
+	}
+
+	// Convert to response format
+	response := make([]models.PricingPolicyResponse, len(policies))
+	for i, policy := range policies {
+		response[i] = policy.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PricingHandler) GetPricingPolicyByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pricing policy ID format"})
+		return
+	}
+
+	policy, err := h.pricingService.WithContext(c.Request.Context()).GetPricingPolicyByID(id)
+	if err != nil {
+		if err.Error() == "pricing policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pricing policy not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pricing policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy.ToResponse())
+}
+
+func (h *PricingHandler) CreatePricingPolicy(c *gin.Context) {
+	var req models.PricingPolicyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.pricingService.WithContext(c.Request.Context()).CreatePricingPolicy(&req)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid pricing mode") || strings.HasPrefix(err.Error(), "pricing policy amounts") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pricing policy"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy.ToResponse())
+}
+
+func (h *PricingHandler) UpdatePricingPolicy(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pricing policy ID format"})
+		return
+	}
+
+	var req models.PricingPolicyUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.pricingService.WithContext(c.Request.Context()).UpdatePricingPolicy(id, &req)
+	if err != nil {
+		if err.Error() == "pricing policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pricing policy not found"})
+			return
+		}
+		if strings.HasSuffix(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid pricing mode") || strings.HasPrefix(err.Error(), "pricing policy amounts") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pricing policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy.ToResponse())
+}
+
+func (h *PricingHandler) DeletePricingPolicy(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pricing policy ID format"})
+		return
+	}
+
+	err = h.pricingService.WithContext(c.Request.Context()).DeletePricingPolicy(id)
+	if err != nil {
+		if err.Error() == "pricing policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pricing policy not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing policy deleted successfully"})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// ExportPlayerData returns all data stored about a player as JSON or ZIP archive
+func (h *PrivacyHandler) ExportPlayerData(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	privacyService := h.privacyService.WithContext(c.Request.Context())
+	filename := fmt.Sprintf("spielerdaten-%s-%s", id.String()[:8], time.Now().Format("2006-01-02"))
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		export, err := privacyService.ExportPlayerData(id, currentSubject(c))
+		if err != nil {
+			if err.Error() == "player not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export player data"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		c.JSON(http.StatusOK, export)
+	case "zip":
+		data, err := privacyService.ExportPlayerArchive(id, currentSubject(c))
+		if err != nil {
+			if err.Error() == "player not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export player data"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".zip"))
+		c.Data(http.StatusOK, "application/zip", data)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, use json or zip"})
+	}
+}
+
+// AnonymizePlayer erases the personal data of a player on request
+func (h *PrivacyHandler) AnonymizePlayer(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID format"})
+		return
+	}
+
+	player, err := h.privacyService.WithContext(c.Request.Context()).AnonymizePlayer(id, currentSubject(c))
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		if err.Error() == "player is already anonymized" || strings.HasPrefix(err.Error(), "cannot anonymize") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to anonymize player"})
+		return
+	}
+
+	c.JSON(http.StatusOK, player.ToResponse(playerViewer(c)))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RoleHandler struct {
+	authorizationService *services.AuthorizationService
+}
+
+func NewRoleHandler(authorizationService *services.AuthorizationService) *RoleHandler {
+	return &RoleHandler{
+		authorizationService: authorizationService,
+	}
+}
+
+// GetMyAccess returns the effective roles and permissions of the caller
+func (h *RoleHandler) GetMyAccess(c *gin.Context) {
+	c.JSON(http.StatusOK, currentAccess(c))
+}
+
+func (h *RoleHandler) GetRoleAssignments(c *gin.Context) {
+	assignments, err := h.authorizationService.WithContext(c.Request.Context()).GetRoleAssignments()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch role assignments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	var req models.RoleAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.authorizationService.WithContext(c.Request.Context()).AssignRole(&req, currentSubject(c))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid role") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasSuffix(err.Error(), "is already assigned") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign role"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+func (h *RoleHandler) RemoveRoleAssignment(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role assignment ID format"})
+		return
+	}
+
+	if err := h.authorizationService.WithContext(c.Request.Context()).RemoveRoleAssignment(id); err != nil {
+		if err.Error() == "role assignment not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role assignment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove role assignment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role assignment removed successfully"})
+}
+
+// currentAccess returns the roles and permissions resolved by middleware.LoadAccess
+func currentAccess(c *gin.Context) *models.AccessResponse {
+	if access, ok := c.Get("Access"); ok {
+		if resolved, ok := access.(*models.AccessResponse); ok {
+			return resolved
+		}
+	}
+	return &models.AccessResponse{Roles: []string{}, Permissions: []string{}}
+}
+
+func hasPermission(c *gin.Context, permission string) bool {
+	return services.HasPermission(currentAccess(c).Permissions, permission)
+}
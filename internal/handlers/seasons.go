@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SeasonHandler struct {
+	seasonService *services.SeasonService
+}
+
+func NewSeasonHandler(seasonService *services.SeasonService) *SeasonHandler {
+	return &SeasonHandler{
+		seasonService: seasonService,
+	}
+}
+
+func (h *SeasonHandler) GetSeasons(c *gin.Context) {
+	seasons, err := h.seasonService.WithContext(c.Request.Context()).GetSeasons()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seasons"})
+		return
+	}
+
+	response := make([]models.SeasonResponse, len(seasons))
+	for i, season := range seasons {
+		response[i] = season.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SeasonHandler) GetSeasonByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID format"})
+		return
+	}
+
+	season, err := h.seasonService.WithContext(c.Request.Context()).GetSeasonByID(id)
+	if err != nil {
+		if err.Error() == "season not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, season.ToResponse())
+}
+
+func (h *SeasonHandler) CreateSeason(c *gin.Context) {
+	var req models.SeasonCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season, err := h.seasonService.WithContext(c.Request.Context()).CreateSeason(&req)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") || strings.HasPrefix(err.Error(), "season must") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create season"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, season.ToResponse())
+}
+
+func (h *SeasonHandler) UpdateSeason(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID format"})
+		return
+	}
+
+	var req models.SeasonUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season, err := h.seasonService.WithContext(c.Request.Context()).UpdateSeason(id, &req)
+	if err != nil {
+		if err.Error() == "season not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+			return
+		}
+		if strings.HasSuffix(err.Error(), "already exists") || strings.HasPrefix(err.Error(), "season must cover") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") || strings.HasPrefix(err.Error(), "season must") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, season.ToResponse())
+}
+
+func (h *SeasonHandler) DeleteSeason(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID format"})
+		return
+	}
+
+	if err := h.seasonService.WithContext(c.Request.Context()).DeleteSeason(id); err != nil {
+		if err.Error() == "season not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "cannot delete season") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Season deleted successfully"})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StandingsHandler struct {
+	standingsService *services.StandingsService
+}
+
+func NewStandingsHandler(standingsService *services.StandingsService) *StandingsHandler {
+	return &StandingsHandler{
+		standingsService: standingsService,
+	}
+}
+
+func (h *StandingsHandler) GetStandings(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	standings, err := h.standingsService.WithContext(c.Request.Context()).GetStandings(id)
+	if err != nil {
+		if err.Error() == "training session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate standings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"darts-training-app/internal/models"
 	"darts-training-app/internal/services"
@@ -21,7 +22,7 @@ func NewTeamHandler(teamService *services.TeamService) *TeamHandler {
 }
 
 func (h *TeamHandler) GetAllTeams(c *gin.Context) {
-	teams, err := h.teamService.GetAllTeams()
+	teams, err := h.teamService.WithContext(c.Request.Context()).GetAllTeams()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
 		return
@@ -44,7 +45,7 @@ func (h *TeamHandler) GetTeamByID(c *gin.Context) {
 		return
 	}
 
-	team, err := h.teamService.GetTeamByID(id)
+	team, err := h.teamService.WithContext(c.Request.Context()).GetTeamByID(id)
 	if err != nil {
 		if err.Error() == "team not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
@@ -64,7 +65,7 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.teamService.CreateTeam(&req)
+	team, err := h.teamService.WithContext(c.Request.Context()).CreateTeam(&req)
 	if err != nil {
 		if err.Error() == "team with name '"+req.Name+"' already exists" {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
@@ -91,7 +92,7 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.teamService.UpdateTeam(id, &req)
+	team, err := h.teamService.WithContext(c.Request.Context()).UpdateTeam(id, &req)
 	if err != nil {
 		if err.Error() == "team not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
@@ -116,7 +117,7 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 		return
 	}
 
-	err = h.teamService.DeleteTeam(id)
+	err = h.teamService.WithContext(c.Request.Context()).DeleteTeam(id)
 	if err != nil {
 		if err.Error() == "team not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
@@ -129,6 +130,8 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Team deleted successfully"})
 }
 
+// GetTeamPlayers returns the roster of the team today, on the day given as
+// date or during the season given as season_id
 func (h *TeamHandler) GetTeamPlayers(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
@@ -137,20 +140,39 @@ func (h *TeamHandler) GetTeamPlayers(c *gin.Context) {
 		return
 	}
 
-	players, err := h.teamService.GetTeamPlayers(id)
+	var seasonID *uuid.UUID
+	if seasonIDParam := c.Query("season_id"); seasonIDParam != "" {
+		if parsedID, err := uuid.Parse(seasonIDParam); err == nil {
+			seasonID = &parsedID
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID format"})
+			return
+		}
+	}
+
+	memberships, err := h.teamService.WithContext(c.Request.Context()).GetTeamPlayers(id, c.Query("date"), seasonID)
 	if err != nil {
 		if err.Error() == "team not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
 			return
 		}
+		if err.Error() == "season not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid date") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team players"})
 		return
 	}
 
 	// Convert to response format
-	response := make([]models.PlayerResponse, len(players))
-	for i, player := range players {
-		response[i] = player.ToResponse()
+	viewer := playerViewer(c)
+	response := make([]models.RosterEntryResponse, len(memberships))
+	for i, membership := range memberships {
+		response[i] = membership.ToRosterEntry(viewer)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TournamentHandler struct {
+	tournamentService *services.TournamentService
+}
+
+func NewTournamentHandler(tournamentService *services.TournamentService) *TournamentHandler {
+	return &TournamentHandler{
+		tournamentService: tournamentService,
+	}
+}
+
+func (h *TournamentHandler) CreateTournament(c *gin.Context) {
+	idParam := c.Param("id")
+	trainingSessionID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	var req models.TournamentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tournament, err := h.tournamentService.WithContext(c.Request.Context()).CreateTournament(trainingSessionID, &req)
+	if err != nil {
+		if err.Error() == "training session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
+			return
+		}
+		if err.Error() == "game mode not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Game mode not found"})
+			return
+		}
+		if err.Error() == "training session already has a tournament" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "cannot create tournament for completed or cancelled training" ||
+			err.Error() == "need at least 2 attending players to create a tournament" ||
+			err.Error() == "invalid group configuration" ||
+			strings.HasPrefix(err.Error(), "invalid tournament format") ||
+			strings.HasPrefix(err.Error(), "invalid seeding") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tournament"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tournament.ToResponse())
+}
+
+// GetBracket returns the tournament of a training session grouped by bracket and round for display
+func (h *TournamentHandler) GetBracket(c *gin.Context) {
+	idParam := c.Param("id")
+	trainingSessionID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	tournament, err := h.tournamentService.WithContext(c.Request.Context()).GetTournamentByTrainingSession(trainingSessionID)
+	if err != nil {
+		if err.Error() == "tournament not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tournament"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tournament.ToResponse())
+}
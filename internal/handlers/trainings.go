@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
+	"strings"
 
 	"darts-training-app/internal/models"
 	"darts-training-app/internal/services"
@@ -21,7 +23,7 @@ func NewTrainingHandler(trainingService *services.TrainingService) *TrainingHand
 }
 
 func (h *TrainingHandler) GetAllTrainingSessions(c *gin.Context) {
-	sessions, err := h.trainingService.GetAllTrainingSessions()
+	sessions, err := h.trainingService.WithContext(c.Request.Context()).GetAllTrainingSessions()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch training sessions"})
 		return
@@ -44,7 +46,7 @@ func (h *TrainingHandler) GetTrainingSessionByID(c *gin.Context) {
 		return
 	}
 
-	session, err := h.trainingService.GetTrainingSessionByID(id)
+	session, err := h.trainingService.WithContext(c.Request.Context()).GetTrainingSessionByID(id)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
@@ -64,22 +66,22 @@ func (h *TrainingHandler) CreateTrainingSession(c *gin.Context) {
 		return
 	}
 
-	// Get creator ID from context (for now, we'll use a placeholder)
-	_, exists := c.Get("user_id")
-	if !exists {
+	principal := currentPrincipal(c)
+	if principal == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
-	// For now, we'll use a UUID placeholder until Auth0 integration is complete
-	creatorID, err := uuid.Parse("00000000-0000-0000-0000-000000000000")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse creator ID"})
-		return
-	}
-
-	session, err := h.trainingService.CreateTrainingSession(&req, creatorID)
+	session, err := h.trainingService.WithContext(c.Request.Context()).CreateTrainingSession(&req, principal.PlayerID)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid tiebreaker") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "invalid pricing policy ID" || err.Error() == "pricing policy not found" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create training session"})
 		return
 	}
@@ -101,13 +103,21 @@ func (h *TrainingHandler) UpdateTrainingSession(c *gin.Context) {
 		return
 	}
 
-	session, err := h.trainingService.UpdateTrainingSession(id, &req)
+	session, err := h.trainingService.WithContext(c.Request.Context()).UpdateTrainingSession(id, &req)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
 			return
 		}
-		if err.Error() == "invalid status" {
+		if err.Error() == "status cannot be changed via update" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status cannot be changed via update, use the start, finish, cancel, postpone, reschedule or reopen actions"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid tiebreaker") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "invalid pricing policy ID" || err.Error() == "pricing policy not found" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -126,7 +136,7 @@ func (h *TrainingHandler) DeleteTrainingSession(c *gin.Context) {
 		return
 	}
 
-	err = h.trainingService.DeleteTrainingSession(id)
+	err = h.trainingService.WithContext(c.Request.Context()).DeleteTrainingSession(id)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
@@ -144,6 +154,15 @@ func (h *TrainingHandler) DeleteTrainingSession(c *gin.Context) {
 }
 
 func (h *TrainingHandler) StartTraining(c *gin.Context) {
+	h.transitionTraining(c, "start", "Failed to start training")
+}
+
+func (h *TrainingHandler) FinishTraining(c *gin.Context) {
+	h.transitionTraining(c, "finish", "Failed to finish training")
+}
+
+// CancelTraining cancels the training with a mandatory reason and notifies the invited players
+func (h *TrainingHandler) CancelTraining(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -151,24 +170,48 @@ func (h *TrainingHandler) StartTraining(c *gin.Context) {
 		return
 	}
 
-	session, err := h.trainingService.StartTraining(id)
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.trainingService.WithContext(c.Request.Context()).CancelTrainingSession(id, currentSubject(c), req.Reason)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
 			return
 		}
-		if err.Error() == "training session is not in planned status" {
+		if err.Error() == "cancellation reason is required" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start training"})
+		if strings.HasPrefix(err.Error(), "cannot cancel training session") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel training"})
 		return
 	}
 
 	c.JSON(http.StatusOK, session.ToResponse())
 }
 
-func (h *TrainingHandler) FinishTraining(c *gin.Context) {
+func (h *TrainingHandler) PostponeTraining(c *gin.Context) {
+	h.transitionTraining(c, "postpone", "Failed to postpone training")
+}
+
+func (h *TrainingHandler) RescheduleTraining(c *gin.Context) {
+	h.transitionTraining(c, "reschedule", "Failed to reschedule training")
+}
+
+func (h *TrainingHandler) ReopenTraining(c *gin.Context) {
+	h.transitionTraining(c, "reopen", "Failed to reopen training")
+}
+
+func (h *TrainingHandler) GetTrainingTransitions(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -176,23 +219,77 @@ func (h *TrainingHandler) FinishTraining(c *gin.Context) {
 		return
 	}
 
-	session, err := h.trainingService.FinishTraining(id)
+	transitions, err := h.trainingService.WithContext(c.Request.Context()).GetTrainingSessionTransitions(id)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
 			return
 		}
-		if err.Error() == "training session is not active" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch training session history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
+
+// transitionTraining applies a state machine action; the request body (reason, new date) is optional
+func (h *TrainingHandler) transitionTraining(c *gin.Context, action string, failureMessage string) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training session ID format"})
+		return
+	}
+
+	var req models.TrainingSessionTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.trainingService.WithContext(c.Request.Context()).TransitionTrainingSession(id, action, currentSubject(c), &req)
+	if err != nil {
+		if err.Error() == "training session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finish training"})
+		if strings.HasPrefix(err.Error(), "cannot "+action+" training session") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failureMessage})
 		return
 	}
 
 	c.JSON(http.StatusOK, session.ToResponse())
 }
 
+// currentPrincipal returns the caller resolved by middleware.ResolveIdentity
+func currentPrincipal(c *gin.Context) *models.Principal {
+	principal, exists := c.Get("Principal")
+	if !exists {
+		return nil
+	}
+	resolved, ok := principal.(*models.Principal)
+	if !ok {
+		return nil
+	}
+	return resolved
+}
+
+// currentSubject returns the subject of the authenticated user's token, if any
+func currentSubject(c *gin.Context) *string {
+	user, exists := c.Get("User")
+	if !exists {
+		return nil
+	}
+	userToken, ok := user.(models.UserToken)
+	if !ok || userToken.Subject == "" {
+		return nil
+	}
+	return &userToken.Subject
+}
+
 func (h *TrainingHandler) GetTrainingCosts(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
@@ -201,7 +298,7 @@ func (h *TrainingHandler) GetTrainingCosts(c *gin.Context) {
 		return
 	}
 
-	costs, err := h.trainingService.GetTrainingCosts(id)
+	costs, err := h.trainingService.WithContext(c.Request.Context()).GetTrainingCosts(id)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
@@ -222,24 +319,30 @@ func (h *TrainingHandler) AddTrainingPlayer(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		GuestName *string `json:"guest_name"`
-	}
+	var req models.TrainingGuestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	trainingPlayer, err := h.trainingService.AddTrainingPlayer(trainingID, req.GuestName)
+	trainingPlayer, err := h.trainingService.WithContext(c.Request.Context()).AddTrainingPlayer(trainingID, &req)
 	if err != nil {
 		if err.Error() == "training session not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training session not found"})
 			return
 		}
-		if err.Error() == "cannot add players to completed or cancelled training" {
+		if err.Error() == "guest not found" || err.Error() == "inviting player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "cannot add players to completed or cancelled training" || strings.HasPrefix(err.Error(), "invalid") {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if err.Error() == "guest is already part of the training" || err.Error() == "guest has become a member" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		if err.Error() == "automatic player assignment is handled during training creation" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Regular players are automatically assigned during training creation"})
 			return
@@ -259,7 +362,7 @@ func (h *TrainingHandler) RemoveTrainingPlayer(c *gin.Context) {
 		return
 	}
 
-	err = h.trainingService.RemoveTrainingPlayer(playerID)
+	err = h.trainingService.WithContext(c.Request.Context()).RemoveTrainingPlayer(playerID)
 	if err != nil {
 		if err.Error() == "training player not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Training player not found"})
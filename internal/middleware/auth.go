@@ -3,7 +3,6 @@ package middleware
 import (
 	"net/http"
 	"strings"
-	"time"
 
 	"darts-training-app/internal/models"
 	"darts-training-app/internal/services"
@@ -12,48 +11,103 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func CheckAuth(authManager *services.AuthManager) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.Request.Header.Get("Authorization")
-		token := strings.Split(authHeader, "Bearer ")
+// authFailure is the response for a rejected token
+type authFailure struct {
+	status  int
+	message string
+	code    string
+}
 
-		if len(token) < 2 || token[1] == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token format",
-				"code":  "INVALID_TOKEN",
-			})
+// authFailures gives every authentication error its own machine-readable code
+var authFailures = map[error]authFailure{
+	services.ErrKeySetUnavailable:     {http.StatusServiceUnavailable, "Signing keys are unavailable", "JWKS_ERROR"},
+	services.ErrInvalidToken:          {http.StatusUnauthorized, "Invalid token", "INVALID_TOKEN"},
+	services.ErrTokenMalformed:        {http.StatusUnauthorized, "Token is malformed", "TOKEN_MALFORMED"},
+	services.ErrTokenAlgorithm:        {http.StatusUnauthorized, "Token signing algorithm is not allowed", "TOKEN_ALGORITHM_NOT_ALLOWED"},
+	services.ErrTokenSignatureInvalid: {http.StatusUnauthorized, "Token signature is invalid", "TOKEN_SIGNATURE_INVALID"},
+	services.ErrTokenExpired:          {http.StatusUnauthorized, "Token has expired", "TOKEN_EXPIRED"},
+	services.ErrTokenNotYetValid:      {http.StatusUnauthorized, "Token is not valid yet", "TOKEN_NOT_YET_VALID"},
+	services.ErrTokenIssuer:           {http.StatusUnauthorized, "Token issuer is not accepted", "TOKEN_ISSUER_INVALID"},
+	services.ErrTokenAudience:         {http.StatusUnauthorized, "Token audience is not accepted", "TOKEN_AUDIENCE_INVALID"},
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer <token>" header,
+// the scheme is case-insensitive
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// CheckAuth accepts a Bearer token or, when apiKeyService is set, an API key
+// in the X-API-Key header. API keys are stored as "APIKey" and get a synthetic
+// "User" whose permissions are the scopes of the key.
+func CheckAuth(authenticator services.Authenticator, apiKeyService *services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.Request.Header.Get("X-API-Key"); key != "" && apiKeyService != nil {
+			checkAPIKey(c, apiKeyService, key)
 			return
 		}
 
-		jwks, err := authManager.GetJWKS()
-		if err != nil {
+		tokenString, ok := bearerToken(c.Request.Header.Get("Authorization"))
+		if !ok {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Failed to get JWKS",
-				"code":  "JWKS_ERROR",
+				"error": "Missing or malformed Authorization header",
+				"code":  "MISSING_TOKEN",
 			})
 			return
 		}
 
-		userToken := models.UserToken{}
-		_, err = jwt.ParseWithClaims(token[1], &userToken, jwks.Keyfunc)
+		userToken, err := authenticator.Authenticate(tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token",
-				"code":  "JWKS_ERROR",
+			failure, known := authFailures[err]
+			if !known {
+				failure = authFailures[services.ErrInvalidToken]
+			}
+			c.AbortWithStatusJSON(failure.status, gin.H{
+				"error": failure.message,
+				"code":  failure.code,
 			})
 			return
 		}
+		c.Set("User", *userToken)
+		c.Next()
+	}
+}
 
-		if !userToken.VerifyExpiresAt(time.Now(), true) {
+func checkAPIKey(c *gin.Context, apiKeyService *services.APIKeyService, key string) {
+	apiKey, err := apiKeyService.Authenticate(key)
+	if err != nil {
+		switch err {
+		case services.ErrAPIKeyInvalid:
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Token has expired",
-				"code":  "Auth Error",
+				"error": "Invalid API key",
+				"code":  "INVALID_API_KEY",
+			})
+		case services.ErrAPIKeyExpired:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "API key has expired",
+				"code":  "API_KEY_EXPIRED",
+			})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to verify API key",
+				"code":  "API_KEY_ERROR",
 			})
-			return
 		}
-		c.Set("User", userToken)
-		c.Next()
+		return
 	}
+
+	c.Set("APIKey", apiKey)
+	c.Set("User", models.UserToken{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: apiKey.Subject()},
+		Name:             apiKey.Name,
+		Permissions:      apiKey.ScopeList(),
+	})
+	c.Next()
 }
 
 //func OptionalAuthMiddleware(authManager *services.AuthManager) gin.HandlerFunc {
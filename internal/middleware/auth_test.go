@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// stubAuthenticator accepts the token "good" and fails everything else with err
+type stubAuthenticator struct {
+	err error
+}
+
+func (a stubAuthenticator) Authenticate(tokenString string) (*models.UserToken, error) {
+	if tokenString == "good" {
+		return &models.UserToken{RegisteredClaims: jwt.RegisteredClaims{Subject: "auth0|123"}}, nil
+	}
+	return nil, a.err
+}
+
+func TestCheckAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		header     string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "valid token", header: "Bearer good", wantStatus: http.StatusOK},
+		{name: "lower case scheme", header: "bearer good", wantStatus: http.StatusOK},
+		{name: "missing header", header: "", wantStatus: http.StatusUnauthorized, wantCode: "MISSING_TOKEN"},
+		{name: "other scheme", header: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized, wantCode: "MISSING_TOKEN"},
+		{name: "empty bearer", header: "Bearer ", wantStatus: http.StatusUnauthorized, wantCode: "MISSING_TOKEN"},
+		{name: "key set unavailable", header: "Bearer bad", err: services.ErrKeySetUnavailable, wantStatus: http.StatusServiceUnavailable, wantCode: "JWKS_ERROR"},
+		{name: "malformed", header: "Bearer bad", err: services.ErrTokenMalformed, wantStatus: http.StatusUnauthorized, wantCode: "TOKEN_MALFORMED"},
+		{name: "algorithm", header: "Bearer bad", err: services.ErrTokenAlgorithm, wantStatus: http.StatusUnauthorized, wantCode: "TOKEN_ALGORITHM_NOT_ALLOWED"},
+		{name: "signature", header: "Bearer bad", err: services.ErrTokenSignatureInvalid, wantStatus: http.StatusUnauthorized, wantCode: "TOKEN_SIGNATURE_INVALID"},
+		{name: "expired", header: "Bearer bad", err: services.ErrTokenExpired, wantStatus: http.StatusUnauthorized, wantCode: "TOKEN_EXPIRED"},
+		{name: "not yet valid", header: "Bearer bad", err: services.ErrTokenNotYetValid, wantStatus: http.StatusUnauthorized, wantCode: "TOKEN_NOT_YET_VALID"},
+		{name: "issuer", header: "Bearer bad", err: services.ErrTokenIssuer, wantStatus: http.StatusUnauthorized, wantCode: "TOKEN_ISSUER_INVALID"},
+		{name: "audience", header: "Bearer bad", err: services.ErrTokenAudience, wantStatus: http.StatusUnauthorized, wantCode: "TOKEN_AUDIENCE_INVALID"},
+		{name: "unknown error", header: "Bearer bad", err: http.ErrHandlerTimeout, wantStatus: http.StatusUnauthorized, wantCode: "INVALID_TOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", CheckAuth(stubAuthenticator{err: tt.err}, nil), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var body map[string]string
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body["code"] != tt.wantCode {
+				t.Fatalf("code = %q, want %q", body["code"], tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LoadAccess resolves the roles and permissions of the authenticated user and
+// stores them as "Access". It must run after ResolveIdentity.
+func LoadAccess(authorizationService *services.AuthorizationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("User")
+		userToken, ok := user.(models.UserToken)
+		if !exists || !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "User not authenticated",
+				"code":  "UNAUTHENTICATED",
+			})
+			return
+		}
+
+		// API keys get exactly their scopes, neither roles nor the default role
+		if apiKey, ok := c.Get("APIKey"); ok {
+			c.Set("Access", &models.AccessResponse{
+				Subject:     userToken.Subject,
+				Roles:       []string{},
+				Permissions: apiKey.(*models.APIKey).ScopeList(),
+			})
+			c.Next()
+			return
+		}
+
+		principal, _ := c.Get("Principal")
+		resolvedPrincipal, _ := principal.(*models.Principal)
+
+		access, err := authorizationService.WithContext(c.Request.Context()).ResolveAccess(userToken, resolvedPrincipal)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to resolve permissions",
+				"code":  "ACCESS_ERROR",
+			})
+			return
+		}
+
+		c.Set("Access", access)
+		c.Next()
+	}
+}
+
+// RequirePermission lets the request pass if the user has any of the permissions
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access := currentAccess(c)
+		for _, permission := range permissions {
+			if services.HasPermission(access.Permissions, permission) {
+				c.Next()
+				return
+			}
+		}
+		forbidden(c)
+	}
+}
+
+// RequireOwnPlayerOr lets the request pass if the user has the permission, or
+// has ownPermission and the player in the :id parameter is their own
+func RequireOwnPlayerOr(permission string, ownPermission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access := currentAccess(c)
+		if services.HasPermission(access.Permissions, permission) {
+			c.Next()
+			return
+		}
+
+		if services.HasPermission(access.Permissions, ownPermission) {
+			// Malformed IDs are left to the handler to report
+			playerID, err := uuid.Parse(c.Param("id"))
+			if err != nil {
+				c.Next()
+				return
+			}
+
+			principal, _ := c.Get("Principal")
+			if resolved, ok := principal.(*models.Principal); ok && resolved.PlayerID != nil && *resolved.PlayerID == playerID {
+				c.Next()
+				return
+			}
+		}
+
+		forbidden(c)
+	}
+}
+
+// RequireTokenPermission lets the request pass if the permissions claim of the
+// token grants the permission. Roles and API key scopes are not considered, so
+// club administrators cannot grant it to themselves.
+func RequireTokenPermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("APIKey"); ok {
+			forbidden(c)
+			return
+		}
+
+		user, _ := c.Get("User")
+		if token, ok := user.(models.UserToken); ok && services.HasPermission(token.Permissions, permission) {
+			c.Next()
+			return
+		}
+		forbidden(c)
+	}
+}
+
+func currentAccess(c *gin.Context) *models.AccessResponse {
+	if access, ok := c.Get("Access"); ok {
+		if resolved, ok := access.(*models.AccessResponse); ok {
+			return resolved
+		}
+	}
+	return &models.AccessResponse{}
+}
+
+func forbidden(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error": "Insufficient permissions",
+		"code":  "FORBIDDEN",
+	})
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"darts-training-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireTokenPermissionIgnoresRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		token      models.UserToken
+		access     *models.AccessResponse
+		apiKey     bool
+		wantStatus int
+	}{
+		{
+			name:       "token claim",
+			token:      models.UserToken{Permissions: []string{models.PermissionClubsManage}},
+			access:     &models.AccessResponse{Permissions: []string{models.PermissionClubsManage}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			// An admin role assignment resolves to "*" but is scoped to a club
+			name:       "club admin",
+			token:      models.UserToken{},
+			access:     &models.AccessResponse{Roles: []string{models.RoleAdmin}, Permissions: []string{models.PermissionAll}},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "admin role claim",
+			token:      models.UserToken{Roles: []string{models.RoleAdmin}},
+			access:     &models.AccessResponse{Roles: []string{models.RoleAdmin}, Permissions: []string{models.PermissionAll}},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "api key",
+			token:      models.UserToken{Permissions: []string{models.PermissionClubsManage}},
+			access:     &models.AccessResponse{Permissions: []string{models.PermissionClubsManage}},
+			apiKey:     true,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("User", tt.token)
+				c.Set("Access", tt.access)
+				if tt.apiKey {
+					c.Set("APIKey", &models.APIKey{})
+				}
+			})
+			router.GET("/clubs", RequireTokenPermission(models.PermissionClubsManage), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/clubs", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
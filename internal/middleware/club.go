@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+
+	"darts-training-app/internal/database"
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResolveClub determines the club of the request from the API key, the token's
+// clubs claim and the X-Club header. It stores the club as "Club" and binds it
+// to the request context, which scopes all queries of the services to the
+// club. It must run after CheckAuth.
+func ResolveClub(clubService *services.ClubService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("User")
+		userToken, ok := user.(models.UserToken)
+		if !exists || !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "User not authenticated",
+				"code":  "UNAUTHENTICATED",
+			})
+			return
+		}
+
+		var apiKey *models.APIKey
+		if key, ok := c.Get("APIKey"); ok {
+			apiKey = key.(*models.APIKey)
+		}
+
+		club, err := clubService.ResolveClub(userToken, c.GetHeader("X-Club"), apiKey)
+		if err != nil {
+			switch err.Error() {
+			case "club selection required":
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": "Select a club with the X-Club header",
+					"code":  "CLUB_REQUIRED",
+				})
+			case "club not permitted":
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error": "No access to this club",
+					"code":  "CLUB_FORBIDDEN",
+				})
+			case "club not found":
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+					"error": "Club not found",
+					"code":  "CLUB_NOT_FOUND",
+				})
+			default:
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to resolve club",
+					"code":  "CLUB_ERROR",
+				})
+			}
+			return
+		}
+
+		c.Set("Club", club)
+		c.Request = c.Request.WithContext(database.WithClub(c.Request.Context(), club.ID))
+		c.Next()
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResolveIdentity maps the authenticated token to a principal and its player.
+// It stores the principal as "Principal" and sets "user_id", "user_email",
+// "user_name" and "user_nickname" for the handlers. Routes in manualLinkRoutes,
+// like accepting an invitation, link the player themselves, so no player is
+// linked or created by email for them. It must run after CheckAuth.
+func ResolveIdentity(resolver services.PrincipalResolver, manualLinkRoutes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("User")
+		userToken, ok := user.(models.UserToken)
+		if !exists || !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "User not authenticated",
+				"code":  "UNAUTHENTICATED",
+			})
+			return
+		}
+
+		// API keys act on their own behalf and are never linked to a player
+		if apiKey, ok := c.Get("APIKey"); ok {
+			key := apiKey.(*models.APIKey)
+			c.Set("Principal", &models.Principal{Subject: key.Subject(), Name: key.Name})
+			c.Set("user_id", key.Subject())
+			c.Set("user_name", key.Name)
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		for _, route := range manualLinkRoutes {
+			if c.FullPath() == route {
+				ctx = services.SkipPlayerLinking(ctx)
+			}
+		}
+
+		accessToken, _ := bearerToken(c.Request.Header.Get("Authorization"))
+		principal, err := resolver.ResolvePrincipal(ctx, userToken, accessToken)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to resolve user identity",
+				"code":  "IDENTITY_ERROR",
+			})
+			return
+		}
+
+		c.Set("Principal", principal)
+		c.Set("user_id", principal.Subject)
+		c.Set("user_email", principal.Email)
+		c.Set("user_name", principal.Name)
+		c.Set("user_nickname", principal.Nickname)
+		c.Next()
+	}
+}
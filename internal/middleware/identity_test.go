@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"darts-training-app/internal/models"
+	"darts-training-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// stubResolver behaves like IdentityService with AUTO_CREATE_PLAYERS: a
+// caller without a linked player gets a new player for their email unless
+// linking is skipped
+type stubResolver struct {
+	created int
+}
+
+func (r *stubResolver) ResolvePrincipal(ctx context.Context, token models.UserToken, accessToken string) (*models.Principal, error) {
+	principal := &models.Principal{Subject: token.Subject, Email: token.Email}
+	if services.PlayerLinkingSkipped(ctx) {
+		return principal, nil
+	}
+	r.created++
+	playerID := uuid.New()
+	principal.PlayerID = &playerID
+	return principal, nil
+}
+
+func TestResolveIdentitySkipsPlayerCreationOnInvitationAccept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The invitation went to another address than the account's email, so
+	// auto-creation would link the account to a new player before accepting
+	token := models.UserToken{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "auth0|123"},
+		Email:            "private@example.com",
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		wantPlayer  bool
+		wantCreated int
+	}{
+		{name: "accept invitation", path: "/api/invitations/accept", wantPlayer: false, wantCreated: 0},
+		{name: "other route", path: "/api/players", wantPlayer: true, wantCreated: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &stubResolver{}
+			var principal *models.Principal
+
+			router := gin.New()
+			api := router.Group("/api")
+			api.Use(func(c *gin.Context) {
+				c.Set("User", token)
+			})
+			api.Use(ResolveIdentity(resolver, "/api/invitations/accept"))
+			handler := func(c *gin.Context) {
+				value, _ := c.Get("Principal")
+				principal, _ = value.(*models.Principal)
+				c.Status(http.StatusOK)
+			}
+			api.POST("/invitations/accept", handler)
+			api.POST("/players", handler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+			}
+			if principal == nil || principal.Subject != "auth0|123" {
+				t.Fatalf("principal = %+v, want subject auth0|123", principal)
+			}
+			if (principal.PlayerID != nil) != tt.wantPlayer {
+				t.Fatalf("player linked = %v, want %v", principal.PlayerID != nil, tt.wantPlayer)
+			}
+			if resolver.created != tt.wantCreated {
+				t.Fatalf("players created = %d, want %d", resolver.created, tt.wantCreated)
+			}
+		})
+	}
+}
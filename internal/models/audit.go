@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions on personal data and player records
+const (
+	AuditPlayerExported   = "player_exported"
+	AuditPlayerAnonymized = "player_anonymized"
+	AuditPlayerMerged     = "player_merged"
+	AuditGuestConverted   = "guest_converted"
+)
+
+// AuditLog records who exported, erased or merged personal data and when. Details
+// describe what was done without repeating the personal data itself.
+type AuditLog struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID     uuid.UUID `gorm:"type:uuid;not null;index" json:"club_id"`
+	Action     string    `gorm:"not null;index" json:"action"` // player_exported, player_anonymized, player_merged, guest_converted
+	EntityType string    `gorm:"not null" json:"entity_type"`  // player
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index" json:"entity_id"`
+	Actor      *string   `json:"actor"` // subject of the authenticated user
+	Details    string    `json:"details"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
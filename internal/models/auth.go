@@ -1,20 +1,45 @@
 package models
 
 import (
+	"time"
+
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 // UserToken represents the claims in a JWT token
 type UserToken struct {
 	jwt.RegisteredClaims
-	RealmAccess RealmAccess `json:"realm_access"`
-	Roles       []string    `json:"https://gotoitcareer.com/roles"`
-	Permissions []string    `json:"permissions"`
-	Aud         []string    `json:"aud"`
-	Email       string      `json:"email"`
-	ClientID    string      `json:"azp"`
-	UserID      string      `json:"sub"`
-	Scopes      string      `json:"scope"`
+	RealmAccess   RealmAccess `json:"realm_access"`
+	Roles         []string    `json:"https://gotoitcareer.com/roles"`
+	Clubs         []string    `json:"https://gotoitcareer.com/clubs"` // slugs or IDs of the clubs the user may act for
+	Permissions   []string    `json:"permissions"`
+	Email         string      `json:"email"`
+	EmailVerified *bool       `json:"email_verified"`
+	Name          string      `json:"name"`
+	Nickname      string      `json:"nickname"`
+	ClientID      string      `json:"azp"`
+	Scopes        string      `json:"scope"`
+}
+
+// Principal is the authenticated caller as seen by the handlers. Player is nil
+// for machine clients and users whose player profile could not be resolved.
+type Principal struct {
+	Subject       string     `json:"subject"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified"`
+	Name          string     `json:"name"`
+	Nickname      string     `json:"nickname"`
+	PlayerID      *uuid.UUID `json:"player_id"`
+	Player        *Player    `json:"-"`
+}
+
+// AuthStatus reports whether the server can verify tokens
+type AuthStatus struct {
+	Mode        string     `json:"mode"`
+	Ready       bool       `json:"ready"`
+	LastRefresh *time.Time `json:"last_refresh,omitempty"`
+	Error       string     `json:"error,omitempty"`
 }
 
 type RealmAccess struct {
@@ -98,9 +123,10 @@ type TokenEndpointResponse struct {
 
 // Auth0User represents the user information from Auth0
 type Auth0User struct {
-	Sub      string `json:"sub"`
-	Email    string `json:"email"`
-	Name     string `json:"name"`
-	Nickname string `json:"nickname"`
-	Picture  string `json:"picture"`
+	Sub           string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Nickname      string `json:"nickname"`
+	Picture       string `json:"picture"`
 }
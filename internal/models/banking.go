@@ -0,0 +1,243 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SepaMandate is a SEPA direct-debit mandate a member signed for the club.
+// Revoked mandates are deleted, a new mandate needs a new reference.
+type SepaMandate struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID            uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_sepa_mandate_club_reference" json:"club_id"`
+	PlayerID          uuid.UUID  `gorm:"uniqueIndex;not null" json:"player_id"`
+	MandateReference  string     `gorm:"uniqueIndex:idx_sepa_mandate_club_reference;not null" json:"mandate_reference"` // unique per club
+	AccountHolder     string     `gorm:"not null" json:"account_holder"`
+	IBAN              string     `gorm:"column:iban;not null" json:"iban"`
+	BIC               *string    `gorm:"column:bic" json:"bic"`
+	SignedAt          time.Time  `gorm:"not null" json:"signed_at"`
+	FirstCollectionAt *time.Time `json:"first_collection_at"` // set once the first collection is confirmed, later collections are recurring
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+type SepaMandateRequest struct {
+	MandateReference string  `json:"mandate_reference" binding:"required,min=1,max=35"`
+	AccountHolder    string  `json:"account_holder" binding:"required,min=1,max=70"`
+	IBAN             string  `json:"iban" binding:"required"`
+	BIC              *string `json:"bic"`
+	SignedAt         string  `json:"signed_at" binding:"required"` // YYYY-MM-DD
+}
+
+type SepaMandateResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	PlayerID          uuid.UUID  `json:"player_id"`
+	MandateReference  string     `json:"mandate_reference"`
+	AccountHolder     string     `json:"account_holder"`
+	IBAN              string     `json:"iban"`
+	BIC               *string    `json:"bic"`
+	SignedAt          time.Time  `json:"signed_at"`
+	FirstCollectionAt *time.Time `json:"first_collection_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type SepaExportRequest struct {
+	CollectionDate *string `json:"collection_date"` // YYYY-MM-DD, defaults to five days from now
+}
+
+// SepaCollection is an exported direct-debit file. Its amounts stay pending,
+// and are left out of the next export, until the bank statement shows them or
+// the treasurer confirms the collection. A rejected collection releases them.
+type SepaCollection struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	ClubID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	MessageID      string     `gorm:"not null" json:"message_id"`
+	CollectionDate time.Time  `gorm:"type:date;not null" json:"collection_date"`
+	Total          Money      `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	Status         string     `gorm:"index;default:'pending'" json:"status"` // pending, confirmed, rejected
+	CreatedBy      *string    `json:"created_by"`
+	ConfirmedAt    *time.Time `json:"confirmed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationships
+	Items []SepaCollectionItem `gorm:"foreignKey:CollectionID" json:"items,omitempty"`
+}
+
+// SepaCollectionItem is the debit of one member in a collection. The payment
+// is booked on the ledger once the debit is collected.
+type SepaCollectionItem struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CollectionID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"collection_id"`
+	PlayerID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"player_id"`
+	MandateID     uuid.UUID  `gorm:"type:uuid;not null" json:"mandate_id"`
+	EndToEndID    string     `gorm:"uniqueIndex;not null" json:"end_to_end_id"`
+	Amount        Money      `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	SequenceType  string     `gorm:"not null" json:"sequence_type"` // FRST, RCUR
+	LedgerEntryID *uuid.UUID `json:"ledger_entry_id"`               // payment booked when collected
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+type SepaCollectionResponse struct {
+	ID             uuid.UUID                    `json:"id"`
+	MessageID      string                       `json:"message_id"`
+	CollectionDate string                       `json:"collection_date"`
+	Total          Money                        `json:"total"`
+	Status         string                       `json:"status"`
+	CreatedBy      *string                      `json:"created_by"`
+	ConfirmedAt    *time.Time                   `json:"confirmed_at"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	Items          []SepaCollectionItemResponse `json:"items,omitempty"`
+}
+
+type SepaCollectionItemResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	PlayerID      uuid.UUID  `json:"player_id"`
+	PlayerName    *string    `json:"player_name,omitempty"`
+	EndToEndID    string     `json:"end_to_end_id"`
+	Amount        Money      `json:"amount"`
+	SequenceType  string     `json:"sequence_type"`
+	Collected     bool       `json:"collected"`
+	LedgerEntryID *uuid.UUID `json:"ledger_entry_id"`
+}
+
+// BankTransaction is an incoming transfer read from a bank statement. Transfers
+// that could not be matched to a player stay unmatched for manual review.
+type BankTransaction struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID           uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_bank_transaction_club_reference" json:"club_id"`
+	BankReference    string     `gorm:"uniqueIndex:idx_bank_transaction_club_reference;not null" json:"bank_reference"` // used to skip transactions imported twice
+	BookingDate      time.Time  `gorm:"not null" json:"booking_date"`
+	Amount           Money      `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	Counterparty     string     `json:"counterparty"`
+	CounterpartyIBAN string     `gorm:"column:counterparty_iban" json:"counterparty_iban"`
+	Reference        string     `json:"reference"`
+	Status           string     `gorm:"index;default:'unmatched'" json:"status"` // matched, unmatched, ignored
+	PlayerID         *uuid.UUID `json:"player_id"`
+	LedgerEntryID    *uuid.UUID `json:"ledger_entry_id"`
+	ImportedBy       *string    `json:"imported_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+type BankTransactionAssignRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+}
+
+type BankTransactionResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	BankReference    string     `json:"bank_reference"`
+	BookingDate      time.Time  `json:"booking_date"`
+	Amount           Money      `json:"amount"`
+	Counterparty     string     `json:"counterparty"`
+	CounterpartyIBAN string     `json:"counterparty_iban"`
+	Reference        string     `json:"reference"`
+	Status           string     `json:"status"`
+	PlayerID         *uuid.UUID `json:"player_id"`
+	PlayerName       *string    `json:"player_name,omitempty"`
+	LedgerEntryID    *uuid.UUID `json:"ledger_entry_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type BankImportResponse struct {
+	Imported      int                       `json:"imported"`
+	Matched       int                       `json:"matched"`
+	Unmatched     int                       `json:"unmatched"`
+	Duplicates    int                       `json:"duplicates"`
+	SkippedDebits int                       `json:"skipped_debits"`
+	Transactions  []BankTransactionResponse `json:"transactions"`
+}
+
+// PaymentReference is the reference members put on their transfers so the
+// bank statement import can match them, e.g. "DARTS-1A2B3C4D"
+func PaymentReference(playerID uuid.UUID) string {
+	return fmt.Sprintf("DARTS-%s", strings.ToUpper(strings.ReplaceAll(playerID.String(), "-", "")[:8]))
+}
+
+func (m *SepaMandate) ToResponse() SepaMandateResponse {
+	return SepaMandateResponse{
+		ID:                m.ID,
+		PlayerID:          m.PlayerID,
+		MandateReference:  m.MandateReference,
+		AccountHolder:     m.AccountHolder,
+		IBAN:              m.IBAN,
+		BIC:               m.BIC,
+		SignedAt:          m.SignedAt,
+		FirstCollectionAt: m.FirstCollectionAt,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+func (c *SepaCollection) ToResponse() SepaCollectionResponse {
+	response := SepaCollectionResponse{
+		ID:             c.ID,
+		MessageID:      c.MessageID,
+		CollectionDate: c.CollectionDate.Format("2006-01-02"),
+		Total:          c.Total,
+		Status:         c.Status,
+		CreatedBy:      c.CreatedBy,
+		ConfirmedAt:    c.ConfirmedAt,
+		CreatedAt:      c.CreatedAt,
+	}
+	if len(c.Items) > 0 {
+		response.Items = make([]SepaCollectionItemResponse, len(c.Items))
+		for i, item := range c.Items {
+			response.Items[i] = item.ToResponse()
+		}
+	}
+	return response
+}
+
+func (i *SepaCollectionItem) ToResponse() SepaCollectionItemResponse {
+	var playerName *string
+	if i.Player != nil {
+		playerName = &i.Player.Name
+	}
+
+	return SepaCollectionItemResponse{
+		ID:            i.ID,
+		PlayerID:      i.PlayerID,
+		PlayerName:    playerName,
+		EndToEndID:    i.EndToEndID,
+		Amount:        i.Amount,
+		SequenceType:  i.SequenceType,
+		Collected:     i.LedgerEntryID != nil,
+		LedgerEntryID: i.LedgerEntryID,
+	}
+}
+
+func (t *BankTransaction) ToResponse() BankTransactionResponse {
+	var playerName *string
+	if t.Player != nil {
+		playerName = &t.Player.Name
+	}
+
+	return BankTransactionResponse{
+		ID:               t.ID,
+		BankReference:    t.BankReference,
+		BookingDate:      t.BookingDate,
+		Amount:           t.Amount,
+		Counterparty:     t.Counterparty,
+		CounterpartyIBAN: t.CounterpartyIBAN,
+		Reference:        t.Reference,
+		Status:           t.Status,
+		PlayerID:         t.PlayerID,
+		PlayerName:       playerName,
+		LedgerEntryID:    t.LedgerEntryID,
+		CreatedAt:        t.CreatedAt,
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultClubSlug is the club existing data is migrated to and that users
+// without a club claim belong to
+const DefaultClubSlug = "default"
+
+// Club is a tenant. Teams, players, training sessions and game modes belong
+// to exactly one club and are only visible to requests for that club. The
+// creditor is the club account direct debits are collected to and invoices
+// name as payee.
+type Club struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name         string    `gorm:"not null" json:"name"`
+	Slug         string    `gorm:"uniqueIndex;not null" json:"slug"`
+	CreditorName string    `gorm:"not null;default:''" json:"creditor_name"`
+	CreditorIBAN string    `gorm:"column:creditor_iban;not null;default:''" json:"creditor_iban"`
+	CreditorBIC  string    `gorm:"column:creditor_bic;not null;default:''" json:"creditor_bic"`
+	CreditorID   string    `gorm:"not null;default:''" json:"creditor_id"` // Gläubiger-Identifikationsnummer
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ClubCreditorRequest sets the account the club collects direct debits to
+type ClubCreditorRequest struct {
+	Name       string `json:"name" binding:"required,min=1,max=70"`
+	IBAN       string `json:"iban" binding:"required"`
+	BIC        string `json:"bic" binding:"omitempty,max=11"`
+	CreditorID string `json:"creditor_id" binding:"required,min=1,max=35"`
+}
+
+type ClubCreateRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	Slug string `json:"slug" binding:"required,min=2,max=50"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionExpense is a shared cost of a training evening (board rent, darts,
+// drinks) that is split across the attending players on top of their fee.
+type SessionExpense struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TrainingSessionID uuid.UUID  `gorm:"index;not null" json:"training_session_id"`
+	Description       string     `gorm:"not null" json:"description"`
+	Amount            Money      `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	PaidBy            *uuid.UUID `json:"paid_by"`                           // player who advanced the money, nil for the club cash box
+	SplitMode         string     `gorm:"default:'equal'" json:"split_mode"` // equal, games_played, members_only
+	CreatedBy         *string    `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relationships
+	TrainingSession *TrainingSession `gorm:"foreignKey:TrainingSessionID" json:"training_session,omitempty"`
+	Payer           *Player          `gorm:"foreignKey:PaidBy" json:"payer,omitempty"`
+}
+
+type SessionExpenseCreateRequest struct {
+	Description string  `json:"description" binding:"required,min=1,max=200"`
+	Amount      Money   `json:"amount"`
+	PaidBy      *string `json:"paid_by"`
+	SplitMode   *string `json:"split_mode"`
+}
+
+type SessionExpenseUpdateRequest struct {
+	Description *string `json:"description"`
+	Amount      *Money  `json:"amount"`
+	PaidBy      *string `json:"paid_by"` // an empty string books the expense on the club cash box
+	SplitMode   *string `json:"split_mode"`
+}
+
+type SessionExpenseResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	TrainingSessionID uuid.UUID  `json:"training_session_id"`
+	Description       string     `json:"description"`
+	Amount            Money      `json:"amount"`
+	PaidBy            *uuid.UUID `json:"paid_by"`
+	PayerName         *string    `json:"payer_name,omitempty"`
+	SplitMode         string     `json:"split_mode"`
+	CreatedBy         *string    `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// ExpenseShare is the part of an expense allocated to one attending player or guest
+type ExpenseShare struct {
+	PlayerID  *uuid.UUID `json:"player_id,omitempty"`
+	GuestID   *uuid.UUID `json:"guest_id,omitempty"`
+	GuestName *string    `json:"guest_name,omitempty"`
+	Amount    Money      `json:"amount"`
+}
+
+// ExpenseAllocation shows how a single expense was split in the cost calculation
+type ExpenseAllocation struct {
+	ExpenseID   uuid.UUID      `json:"expense_id"`
+	Description string         `json:"description"`
+	Amount      Money          `json:"amount"`
+	PaidBy      *uuid.UUID     `json:"paid_by"`
+	PayerName   *string        `json:"payer_name,omitempty"`
+	SplitMode   string         `json:"split_mode"`
+	Shares      []ExpenseShare `json:"shares"`
+	ClubPays    bool           `json:"club_pays,omitempty"` // nobody to split among, the club carries the expense
+}
+
+func (e *SessionExpense) ToResponse() SessionExpenseResponse {
+	var payerName *string
+	if e.Payer != nil {
+		payerName = &e.Payer.Name
+	}
+
+	return SessionExpenseResponse{
+		ID:                e.ID,
+		TrainingSessionID: e.TrainingSessionID,
+		Description:       e.Description,
+		Amount:            e.Amount,
+		PaidBy:            e.PaidBy,
+		PayerName:         payerName,
+		SplitMode:         e.SplitMode,
+		CreatedBy:         e.CreatedBy,
+		CreatedAt:         e.CreatedAt,
+		UpdatedAt:         e.UpdatedAt,
+	}
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fixture statuses, derived from the games of the lineup
+const (
+	FixtureStatusScheduled  = "scheduled"
+	FixtureStatusInProgress = "in_progress"
+	FixtureStatusCompleted  = "completed"
+)
+
+// Fixture results from the view of the club's team
+const (
+	FixtureResultWin  = "win"
+	FixtureResultDraw = "draw"
+	FixtureResultLoss = "loss"
+)
+
+// Fixture is a league match of a club team against an external opponent. The
+// match format is a number of singles followed by a number of doubles, each
+// played as a game like in training. Side 1 of every game is the club's team,
+// side 2 the opponent.
+type Fixture struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	TeamID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"team_id"`
+	SeasonID      *uuid.UUID `gorm:"type:uuid;index" json:"season_id"`
+	Opponent      string     `gorm:"not null" json:"opponent"`
+	IsHome        bool       `gorm:"default:true" json:"is_home"`
+	Location      *string    `json:"location"`
+	ScheduledAt   time.Time  `gorm:"not null" json:"scheduled_at"`
+	GameModeID    uuid.UUID  `gorm:"type:uuid;not null" json:"game_mode_id"`
+	Singles       int        `gorm:"not null" json:"singles"`
+	Doubles       int        `gorm:"not null" json:"doubles"`
+	Status        string     `gorm:"default:'scheduled'" json:"status"` // scheduled, in_progress, completed
+	TeamScore     int        `gorm:"default:0" json:"team_score"`       // games won by the club's team
+	OpponentScore int        `gorm:"default:0" json:"opponent_score"`   // games won by the opponent
+	TeamLegs      int        `gorm:"default:0" json:"team_legs"`
+	OpponentLegs  int        `gorm:"default:0" json:"opponent_legs"`
+	Result        *string    `json:"result"` // win, draw, loss once completed
+	LineupSetBy   *string    `json:"lineup_set_by"`
+	LineupSetAt   *time.Time `json:"lineup_set_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relationships
+	Team     *Team          `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Season   *Season        `gorm:"foreignKey:SeasonID" json:"season,omitempty"`
+	GameMode *GameMode      `gorm:"foreignKey:GameModeID" json:"game_mode,omitempty"`
+	Games    []TrainingGame `gorm:"foreignKey:FixtureID" json:"games,omitempty"`
+}
+
+// GameCount is the number of games of the match format
+func (f *Fixture) GameCount() int {
+	return f.Singles + f.Doubles
+}
+
+// FixtureCreateRequest schedules a fixture. Without a format the configured
+// default is used, without a season the season containing the date.
+type FixtureCreateRequest struct {
+	TeamID      string    `json:"team_id" binding:"required"`
+	SeasonID    *string   `json:"season_id"`
+	Opponent    string    `json:"opponent" binding:"required,min=1,max=200"`
+	IsHome      *bool     `json:"is_home"`
+	Location    *string   `json:"location" binding:"omitempty,max=200"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	GameModeID  string    `json:"game_mode_id" binding:"required"`
+	Singles     *int      `json:"singles" binding:"omitempty,min=0,max=30"`
+	Doubles     *int      `json:"doubles" binding:"omitempty,min=0,max=30"`
+}
+
+// FixtureUpdateRequest changes the given fields. Game mode and format can only
+// be changed before the lineup is set.
+type FixtureUpdateRequest struct {
+	SeasonID    *string    `json:"season_id"`
+	Opponent    *string    `json:"opponent" binding:"omitempty,min=1,max=200"`
+	IsHome      *bool      `json:"is_home"`
+	Location    *string    `json:"location" binding:"omitempty,max=200"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	GameModeID  *string    `json:"game_mode_id"`
+	Singles     *int       `json:"singles" binding:"omitempty,min=0,max=30"`
+	Doubles     *int       `json:"doubles" binding:"omitempty,min=0,max=30"`
+}
+
+// FixtureLineupRequest lists the games of the match format in order: first
+// the singles with one player each, then the doubles with two players each
+type FixtureLineupRequest struct {
+	Games []FixtureLineupGame `json:"games" binding:"required,dive"`
+}
+
+type FixtureLineupGame struct {
+	PlayerIDs []string `json:"player_ids" binding:"required,min=1,max=2"`
+	Opponent  *string  `json:"opponent" binding:"omitempty,max=200"` // opponent player names, optional
+}
+
+// FixtureGameResultRequest records the result of a fixture game
+type FixtureGameResultRequest struct {
+	Player1Score *int    `json:"player1_score"`
+	Player2Score *int    `json:"player2_score"`
+	Status       *string `json:"status"`
+	Winner       *string `json:"winner"`
+}
+
+type FixtureResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	TeamID        uuid.UUID              `json:"team_id"`
+	TeamName      *string                `json:"team_name,omitempty"`
+	SeasonID      *uuid.UUID             `json:"season_id"`
+	SeasonName    *string                `json:"season_name,omitempty"`
+	Opponent      string                 `json:"opponent"`
+	IsHome        bool                   `json:"is_home"`
+	Location      *string                `json:"location"`
+	ScheduledAt   time.Time              `json:"scheduled_at"`
+	GameModeID    uuid.UUID              `json:"game_mode_id"`
+	GameModeName  *string                `json:"game_mode_name,omitempty"`
+	Singles       int                    `json:"singles"`
+	Doubles       int                    `json:"doubles"`
+	Status        string                 `json:"status"`
+	TeamScore     int                    `json:"team_score"`
+	OpponentScore int                    `json:"opponent_score"`
+	HomeScore     int                    `json:"home_score"`
+	AwayScore     int                    `json:"away_score"`
+	TeamLegs      int                    `json:"team_legs"`
+	OpponentLegs  int                    `json:"opponent_legs"`
+	Result        *string                `json:"result"`
+	LineupSetAt   *time.Time             `json:"lineup_set_at"`
+	CompletedAt   *time.Time             `json:"completed_at"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	Games         []TrainingGameResponse `json:"games,omitempty"`
+}
+
+func (f *Fixture) ToResponse() FixtureResponse {
+	response := FixtureResponse{
+		ID:            f.ID,
+		TeamID:        f.TeamID,
+		SeasonID:      f.SeasonID,
+		Opponent:      f.Opponent,
+		IsHome:        f.IsHome,
+		Location:      f.Location,
+		ScheduledAt:   f.ScheduledAt,
+		GameModeID:    f.GameModeID,
+		Singles:       f.Singles,
+		Doubles:       f.Doubles,
+		Status:        f.Status,
+		TeamScore:     f.TeamScore,
+		OpponentScore: f.OpponentScore,
+		HomeScore:     f.OpponentScore,
+		AwayScore:     f.TeamScore,
+		TeamLegs:      f.TeamLegs,
+		OpponentLegs:  f.OpponentLegs,
+		Result:        f.Result,
+		LineupSetAt:   f.LineupSetAt,
+		CompletedAt:   f.CompletedAt,
+		CreatedAt:     f.CreatedAt,
+		UpdatedAt:     f.UpdatedAt,
+	}
+	if f.IsHome {
+		response.HomeScore, response.AwayScore = f.TeamScore, f.OpponentScore
+	}
+	if f.Team != nil {
+		response.TeamName = &f.Team.Name
+	}
+	if f.Season != nil {
+		response.SeasonName = &f.Season.Name
+	}
+	if f.GameMode != nil {
+		response.GameModeName = &f.GameMode.Name
+	}
+	if len(f.Games) > 0 {
+		response.Games = make([]TrainingGameResponse, len(f.Games))
+		for i, game := range f.Games {
+			response.Games[i] = game.ToResponse()
+		}
+	}
+	return response
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Guest is a person who takes part in trainings without being a member. The
+// same guest is reused across sessions, so visits can be counted and two
+// guests with the same name stay apart.
+type Guest struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	Name              string     `gorm:"not null;index" json:"name"`
+	Email             *string    `json:"email"`
+	Phone             *string    `json:"phone"`
+	InvitedBy         *uuid.UUID `json:"invited_by"`          // member who brought the guest
+	ConvertedPlayerID *uuid.UUID `json:"converted_player_id"` // player the guest became as a member
+	ConvertedAt       *time.Time `json:"converted_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relationships
+	Inviter *Player `gorm:"foreignKey:InvitedBy" json:"inviter,omitempty"`
+}
+
+type GuestCreateRequest struct {
+	Name      string  `json:"name" binding:"required,min=1,max=100"`
+	Email     *string `json:"email" binding:"omitempty,email"`
+	Phone     *string `json:"phone" binding:"omitempty,max=50"`
+	InvitedBy *string `json:"invited_by"`
+}
+
+// GuestUpdateRequest changes the given fields, an empty string clears email,
+// phone and the inviting member
+type GuestUpdateRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Email     *string `json:"email"`
+	Phone     *string `json:"phone" binding:"omitempty,max=50"`
+	InvitedBy *string `json:"invited_by"`
+}
+
+// GuestConvertRequest creates a member from a guest. Email defaults to the
+// guest's email.
+type GuestConvertRequest struct {
+	Email    *string `json:"email" binding:"omitempty,email"`
+	Category *string `json:"category"`
+	TeamID   *string `json:"team_id"`
+}
+
+// TrainingGuestRequest adds a guest to a training, either a known guest by ID
+// or a new guest by name
+type TrainingGuestRequest struct {
+	GuestID   *string `json:"guest_id"`
+	GuestName *string `json:"guest_name"`
+	InvitedBy *string `json:"invited_by"` // member who brought a new guest
+}
+
+// GuestStats summarises the attended, completed trainings of a guest
+type GuestStats struct {
+	Visits            int        `json:"visits"`
+	LastVisitAt       *time.Time `json:"last_visit_at"`
+	SuggestMembership bool       `json:"suggest_membership"` // visited often enough to be asked to join
+}
+
+type GuestResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	Name              string     `json:"name"`
+	Email             *string    `json:"email"`
+	Phone             *string    `json:"phone"`
+	InvitedBy         *uuid.UUID `json:"invited_by"`
+	InviterName       *string    `json:"inviter_name,omitempty"`
+	ConvertedPlayerID *uuid.UUID `json:"converted_player_id"`
+	ConvertedAt       *time.Time `json:"converted_at"`
+	GuestStats
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (g *Guest) ToResponse(stats GuestStats) GuestResponse {
+	var inviterName *string
+	if g.Inviter != nil {
+		inviterName = &g.Inviter.Name
+	}
+
+	return GuestResponse{
+		ID:                g.ID,
+		Name:              g.Name,
+		Email:             g.Email,
+		Phone:             g.Phone,
+		InvitedBy:         g.InvitedBy,
+		InviterName:       inviterName,
+		ConvertedPlayerID: g.ConvertedPlayerID,
+		ConvertedAt:       g.ConvertedAt,
+		GuestStats:        stats,
+		CreatedAt:         g.CreatedAt,
+		UpdatedAt:         g.UpdatedAt,
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation states, derived from the timestamps of an invitation
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation asks a person to link their login to an existing player. The
+// token sent by mail is single-use, only its hash is stored.
+type Invitation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	PlayerID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"player_id"`
+	Email      string     `gorm:"not null" json:"email"` // recipient, may differ from the player's email
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	SentAt     *time.Time `json:"sent_at"`
+	SendCount  int        `gorm:"default:0" json:"send_count"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	AcceptedBy *string    `json:"accepted_by"` // subject of the login linked to the player
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedBy  *string    `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+type InvitationCreateRequest struct {
+	PlayerID string  `json:"player_id" binding:"required"`
+	Email    *string `json:"email" binding:"omitempty,email"` // defaults to the player's email
+}
+
+type InvitationAcceptRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type InvitationResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	PlayerID   uuid.UUID  `json:"player_id"`
+	PlayerName *string    `json:"player_name,omitempty"`
+	Email      string     `json:"email"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	SentAt     *time.Time `json:"sent_at"`
+	SendCount  int        `json:"send_count"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedBy  *string    `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Status reports whether the invitation can still be accepted
+func (i *Invitation) Status() string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !time.Now().Before(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+func (i *Invitation) ToResponse() InvitationResponse {
+	var playerName *string
+	if i.Player != nil {
+		playerName = &i.Player.Name
+	}
+
+	return InvitationResponse{
+		ID:         i.ID,
+		PlayerID:   i.PlayerID,
+		PlayerName: playerName,
+		Email:      i.Email,
+		Status:     i.Status(),
+		ExpiresAt:  i.ExpiresAt,
+		SentAt:     i.SentAt,
+		SendCount:  i.SendCount,
+		AcceptedAt: i.AcceptedAt,
+		RevokedAt:  i.RevokedAt,
+		CreatedBy:  i.CreatedBy,
+		CreatedAt:  i.CreatedAt,
+	}
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice bills a player for the trainings of one month. A session is only
+// ever invoiced once; sessions finished after the monthly run end up on an
+// additional invoice for the same month.
+type Invoice struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_invoice_club_number" json:"club_id"`
+	Number      string    `gorm:"uniqueIndex:idx_invoice_club_number;not null" json:"number"` // e.g. 2024-000042
+	PlayerID    uuid.UUID `gorm:"index;not null" json:"player_id"`
+	PeriodYear  int       `gorm:"index:idx_invoice_period;not null" json:"period_year"`
+	PeriodMonth int       `gorm:"index:idx_invoice_period;not null" json:"period_month"`
+	Total       Money     `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	IssuedAt    time.Time `gorm:"not null" json:"issued_at"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relationships
+	Player *Player       `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+	Lines  []InvoiceLine `gorm:"foreignKey:InvoiceID" json:"lines,omitempty"`
+}
+
+// InvoiceLine is the amount booked for one training session. Names and dates
+// are copied so the invoice does not change when the session is edited.
+type InvoiceLine struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	InvoiceID         uuid.UUID `gorm:"index;not null" json:"invoice_id"`
+	TrainingSessionID uuid.UUID `gorm:"index;not null" json:"training_session_id"`
+	SessionName       string    `gorm:"not null" json:"session_name"`
+	TrainingDate      time.Time `gorm:"not null" json:"training_date"`
+	Amount            Money     `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	Position          int       `gorm:"not null" json:"position"`
+}
+
+// InvoiceCounter holds the last invoice number issued per club and year
+type InvoiceCounter struct {
+	ClubID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Year       int       `gorm:"primaryKey;autoIncrement:false"`
+	LastNumber int       `gorm:"not null"`
+}
+
+type InvoiceGenerateRequest struct {
+	Year  int `json:"year" binding:"required,min=2000,max=9999"`
+	Month int `json:"month" binding:"required,min=1,max=12"`
+}
+
+type InvoiceLineResponse struct {
+	TrainingSessionID uuid.UUID `json:"training_session_id"`
+	SessionName       string    `json:"session_name"`
+	TrainingDate      time.Time `json:"training_date"`
+	Amount            Money     `json:"amount"`
+}
+
+type InvoiceResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	Number      string                `json:"number"`
+	PlayerID    uuid.UUID             `json:"player_id"`
+	PlayerName  *string               `json:"player_name,omitempty"`
+	PeriodYear  int                   `json:"period_year"`
+	PeriodMonth int                   `json:"period_month"`
+	Total       Money                 `json:"total"`
+	IssuedAt    time.Time             `json:"issued_at"`
+	Lines       []InvoiceLineResponse `json:"lines"`
+}
+
+type InvoiceGenerateResponse struct {
+	PeriodYear  int               `json:"period_year"`
+	PeriodMonth int               `json:"period_month"`
+	Invoices    []InvoiceResponse `json:"invoices"`
+}
+
+// Period returns the billing month in the form "01/2024"
+func (i *Invoice) Period() string {
+	return fmt.Sprintf("%02d/%d", i.PeriodMonth, i.PeriodYear)
+}
+
+func (i *Invoice) ToResponse() InvoiceResponse {
+	var playerName *string
+	if i.Player != nil {
+		playerName = &i.Player.Name
+	}
+
+	lines := make([]InvoiceLineResponse, len(i.Lines))
+	for j, line := range i.Lines {
+		lines[j] = InvoiceLineResponse{
+			TrainingSessionID: line.TrainingSessionID,
+			SessionName:       line.SessionName,
+			TrainingDate:      line.TrainingDate,
+			Amount:            line.Amount,
+		}
+	}
+
+	return InvoiceResponse{
+		ID:          i.ID,
+		Number:      i.Number,
+		PlayerID:    i.PlayerID,
+		PlayerName:  playerName,
+		PeriodYear:  i.PeriodYear,
+		PeriodMonth: i.PeriodMonth,
+		Total:       i.Total,
+		IssuedAt:    i.IssuedAt,
+		Lines:       lines,
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerEntry is a single booking on a player's account. Charges and expense
+// shares are positive, payments and reimbursements negative, so the sum of all
+// entries is the amount owed.
+type LedgerEntry struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	PlayerID          uuid.UUID  `gorm:"index;not null" json:"player_id"`
+	TrainingSessionID *uuid.UUID `gorm:"index" json:"training_session_id"`
+	Type              string     `gorm:"not null" json:"type"` // charge, expense, reimbursement, payment
+	Amount            Money      `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	Description       string     `json:"description"`
+	Reference         *string    `json:"reference"`
+	RecordedBy        *string    `json:"recorded_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	// Relationships
+	Player          *Player          `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+	TrainingSession *TrainingSession `gorm:"foreignKey:TrainingSessionID" json:"training_session,omitempty"`
+}
+
+type PaymentCreateRequest struct {
+	Amount      Money   `json:"amount"`
+	Description *string `json:"description"`
+	Reference   *string `json:"reference"`
+}
+
+type LedgerEntryResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	PlayerID          uuid.UUID  `json:"player_id"`
+	TrainingSessionID *uuid.UUID `json:"training_session_id"`
+	Type              string     `json:"type"`
+	Amount            Money      `json:"amount"`
+	Description       string     `json:"description"`
+	Reference         *string    `json:"reference"`
+	RecordedBy        *string    `json:"recorded_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type PlayerBalanceResponse struct {
+	PlayerID     uuid.UUID             `json:"player_id"`
+	PlayerName   string                `json:"player_name"`
+	TotalCharged Money                 `json:"total_charged"`
+	TotalPaid    Money                 `json:"total_paid"`
+	Balance      Money                 `json:"balance"` // positive means the player owes money
+	Entries      []LedgerEntryResponse `json:"entries"`
+}
+
+type OutstandingDebt struct {
+	PlayerID   uuid.UUID `json:"player_id"`
+	PlayerName string    `json:"player_name"`
+	Email      string    `json:"email"`
+	Balance    Money     `json:"balance"`
+}
+
+type OutstandingDebtsResponse struct {
+	Debts            []OutstandingDebt `json:"debts"`
+	TotalOutstanding Money             `json:"total_outstanding"`
+}
+
+func (e *LedgerEntry) ToResponse() LedgerEntryResponse {
+	return LedgerEntryResponse{
+		ID:                e.ID,
+		PlayerID:          e.PlayerID,
+		TrainingSessionID: e.TrainingSessionID,
+		Type:              e.Type,
+		Amount:            e.Amount,
+		Description:       e.Description,
+		Reference:         e.Reference,
+		RecordedBy:        e.RecordedBy,
+		CreatedAt:         e.CreatedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import "github.com/google/uuid"
+
+// PlayerMergeRequest names the duplicate that is merged into a player: either
+// another player or the guest profile of a person who joined the club
+type PlayerMergeRequest struct {
+	SourcePlayerID *string `json:"source_player_id"`
+	GuestID        *string `json:"guest_id"`
+	DryRun         bool    `json:"dry_run"` // only report what would change
+}
+
+// PlayerMergeResult counts the rows moved to the target player. For a dry run
+// nothing is changed, the counts show what a merge would do.
+type PlayerMergeResult struct {
+	TargetPlayerID   uuid.UUID  `json:"target_player_id"`
+	SourcePlayerID   *uuid.UUID `json:"source_player_id,omitempty"`
+	GuestID          *uuid.UUID `json:"guest_id,omitempty"`
+	DryRun           bool       `json:"dry_run"`
+	TrainingPlayers  int64      `json:"training_players"`
+	Games            int64      `json:"games"`
+	CreatedSessions  int64      `json:"created_sessions"`
+	LedgerEntries    int64      `json:"ledger_entries"`
+	Expenses         int64      `json:"expenses"`
+	Invoices         int64      `json:"invoices"`
+	BankTransactions int64      `json:"bank_transactions"`
+	Mandates         int64      `json:"mandates"`
+	SepaDebits       int64      `json:"sepa_debits"`
+	Invitations      int64      `json:"invitations"`
+	TeamMemberships  int64      `json:"team_memberships"`
+	Guests           int64      `json:"guests"` // guests invited by or converted into the source
+}
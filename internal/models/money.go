@@ -0,0 +1,255 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used when an amount is given without a currency
+const DefaultCurrency = "EUR"
+
+// Money is an exact amount in minor units (cents) of a currency. It is stored
+// as two columns (<prefix>cents, <prefix>currency) via gorm's embedded structs.
+// Arithmetic assumes both operands use the same currency.
+type Money struct {
+	Cents    int64  `gorm:"column:cents" json:"cents"`
+	Currency string `gorm:"column:currency;size:3" json:"currency"`
+}
+
+func NewMoney(cents int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Cents: cents, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "5", "5.5", "-12.34" or "7,50"
+// without going through float64. More than two decimal places are rejected.
+func ParseMoney(value string, currency string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Money{}, fmt.Errorf("invalid money amount: %q", value)
+	}
+
+	negative := false
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(strings.Replace(value, ",", ".", 1), ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(fraction) > 2 {
+		return Money{}, fmt.Errorf("invalid money amount: %q has more than two decimal places", value)
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return Money{}, fmt.Errorf("invalid money amount: %q", value)
+		}
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return Money{}, fmt.Errorf("invalid money amount: %q", value)
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+
+	total := units*100 + cents
+	if negative {
+		total = -total
+	}
+	return NewMoney(total, currency), nil
+}
+
+func (m Money) IsZero() bool     { return m.Cents == 0 }
+func (m Money) IsPositive() bool { return m.Cents > 0 }
+func (m Money) IsNegative() bool { return m.Cents < 0 }
+
+func (m Money) Add(other Money) Money {
+	return NewMoney(m.Cents+other.Cents, m.currencyOr(other))
+}
+
+func (m Money) Sub(other Money) Money {
+	return NewMoney(m.Cents-other.Cents, m.currencyOr(other))
+}
+
+func (m Money) Neg() Money {
+	return NewMoney(-m.Cents, m.Currency)
+}
+
+func (m Money) Mul(factor int64) Money {
+	return NewMoney(m.Cents*factor, m.Currency)
+}
+
+// Percent returns percent % of the amount, rounded half away from zero to whole cents
+func (m Money) Percent(percent float64) Money {
+	return NewMoney(int64(math.Round(float64(m.Cents)*percent/100)), m.Currency)
+}
+
+// Min returns the smaller of both amounts
+func (m Money) Min(other Money) Money {
+	if other.Cents < m.Cents {
+		return NewMoney(other.Cents, m.currencyOr(other))
+	}
+	return m
+}
+
+// Allocate splits the amount according to weights without losing a cent. Each
+// share is rounded down and the remaining cents go one by one to the shares
+// with the largest remainder, ties resolved by position. With all weights zero
+// the amount is split equally.
+func (m Money) Allocate(weights []int64) []Money {
+	shares := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	var totalWeight int64
+	for _, w := range weights {
+		totalWeight += w
+	}
+	if totalWeight <= 0 {
+		weights = make([]int64, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		totalWeight = int64(len(weights))
+	}
+
+	sign := int64(1)
+	cents := m.Cents
+	if cents < 0 {
+		sign, cents = -1, -cents
+	}
+
+	remainders := make([]int64, len(weights))
+	allocated := int64(0)
+	for i, w := range weights {
+		share := cents * w / totalWeight
+		remainders[i] = cents * w % totalWeight
+		shares[i] = NewMoney(share, m.Currency)
+		allocated += share
+	}
+
+	for left := cents - allocated; left > 0; left-- {
+		best := 0
+		for i := range remainders {
+			if remainders[i] > remainders[best] {
+				best = i
+			}
+		}
+		shares[best].Cents++
+		remainders[best] = -1
+	}
+
+	if sign < 0 {
+		for i := range shares {
+			shares[i].Cents = -shares[i].Cents
+		}
+	}
+	return shares
+}
+
+// Decimal formats the amount with two decimal places, e.g. "-12.30"
+func (m Money) Decimal() string {
+	cents := m.Cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.currencyOr(Money{})
+}
+
+func (m Money) currencyOr(other Money) string {
+	if m.Currency != "" {
+		return m.Currency
+	}
+	if other.Currency != "" {
+		return other.Currency
+	}
+	return DefaultCurrency
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Cents    int64  `json:"cents"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON writes the amount as cents plus a decimal string for display
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{
+		Amount:   m.Decimal(),
+		Cents:    m.Cents,
+		Currency: m.currencyOr(Money{}),
+	})
+}
+
+// UnmarshalJSON accepts a decimal number (5.00), a decimal string ("5.00") or
+// an object with cents and currency. Numbers are parsed from their literal text,
+// never via float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	switch data[0] {
+	case '{':
+		var raw struct {
+			Amount   *string `json:"amount"`
+			Cents    *int64  `json:"cents"`
+			Currency string  `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		switch {
+		case raw.Cents != nil:
+			*m = NewMoney(*raw.Cents, strings.ToUpper(raw.Currency))
+		case raw.Amount != nil:
+			parsed, err := ParseMoney(*raw.Amount, strings.ToUpper(raw.Currency))
+			if err != nil {
+				return err
+			}
+			*m = parsed
+		default:
+			return fmt.Errorf("invalid money amount: cents or amount required")
+		}
+	case '"':
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		parsed, err := ParseMoney(value, DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	default:
+		parsed, err := ParseMoney(string(data), DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	}
+	return nil
+}
@@ -7,101 +7,198 @@ import (
 	"gorm.io/gorm"
 )
 
+// Throwing hands a player can set on their profile
+const (
+	ThrowingHandLeft  = "left"
+	ThrowingHandRight = "right"
+)
+
 type Player struct {
-	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Name         string     `gorm:"not null" json:"name"`
-	Email        string     `gorm:"uniqueIndex;not null" json:"email"`
-	Nickname     *string    `json:"nickname"`
-	IsCaptain    bool       `gorm:"default:false" json:"is_captain"`
-	IsActive     bool       `gorm:"default:true" json:"is_active"`
-	Auth0UserID  *string    `gorm:"uniqueIndex" json:"auth0_user_id"`
-	TeamID       *uuid.UUID `json:"team_id"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID           uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID       uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_player_club_email;uniqueIndex:idx_player_club_auth0" json:"club_id"`
+	Name         string         `gorm:"not null" json:"name"`
+	Email        string         `gorm:"uniqueIndex:idx_player_club_email;not null" json:"email"` // unique per club
+	Nickname     *string        `json:"nickname"`
+	IsActive     bool           `gorm:"default:true" json:"is_active"`
+	Category     string         `gorm:"default:'regular'" json:"category"` // regular, youth, student
+	Auth0UserID  *string        `gorm:"uniqueIndex:idx_player_club_auth0" json:"auth0_user_id"`
+	TeamID       *uuid.UUID     `json:"team_id"` // current team, kept in sync with the team memberships
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	AnonymizedAt *time.Time     `json:"anonymized_at,omitempty"` // personal data replaced by a pseudonym
+
+	// Self-service profile, maintained by the player
+	ThrowingHand          *string  `json:"throwing_hand"` // left, right
+	DartWeight            *float64 `json:"dart_weight"`   // grams
+	AvatarURL             *string  `json:"avatar_url"`
+	NotifyTrainingUpdates bool     `gorm:"default:true" json:"notify_training_updates"` // e.g. cancelled trainings
+	EmailVisible          bool     `gorm:"default:false" json:"email_visible"`          // other members may see the email
+	StatsVisible          bool     `gorm:"default:true" json:"stats_visible"`           // other members may see the game statistics
 
 	Team *Team `gorm:"foreignKey:TeamID" json:"team,omitempty"`
 
 	// Relationships
 	CreatedTrainings []TrainingSession `gorm:"foreignKey:CreatedBy" json:"-"`
-	TrainingPlayers  []TrainingPlayer `gorm:"foreignKey:PlayerID" json:"-"`
-	Player1Games     []TrainingGame   `gorm:"foreignKey:Player1ID" json:"-"`
-	Player2Games     []TrainingGame   `gorm:"foreignKey:Player2ID" json:"-"`
+	TrainingPlayers  []TrainingPlayer  `gorm:"foreignKey:PlayerID" json:"-"`
+	Player1Games     []TrainingGame    `gorm:"foreignKey:Player1ID" json:"-"`
+	Player2Games     []TrainingGame    `gorm:"foreignKey:Player2ID" json:"-"`
 }
 
 type PlayerCreateRequest struct {
-	Name      string  `json:"name" binding:"required,min=1,max=100"`
-	Email     string  `json:"email" binding:"required,email"`
-	Nickname  *string `json:"nickname"`
-	IsCaptain bool    `json:"is_captain"`
-	IsActive  bool    `json:"is_active"`
-	TeamID    *string `json:"team_id"`
+	Name     string  `json:"name" binding:"required,min=1,max=100"`
+	Email    string  `json:"email" binding:"required,email"`
+	Nickname *string `json:"nickname"`
+	IsActive bool    `json:"is_active"`
+	Category *string `json:"category"`
+	TeamID   *string `json:"team_id"`
 }
 
 type PlayerUpdateRequest struct {
-	Name      *string `json:"name"`
-	Email     *string `json:"email"`
-	Nickname  *string `json:"nickname"`
-	IsCaptain *bool   `json:"is_captain"`
-	IsActive  *bool   `json:"is_active"`
-	TeamID    *string `json:"team_id"`
+	Name     *string `json:"name"`
+	Email    *string `json:"email"`
+	Nickname *string `json:"nickname"`
+	IsActive *bool   `json:"is_active"`
+	Category *string `json:"category"`
+	TeamID   *string `json:"team_id"`
+}
+
+// PlayerProfileUpdateRequest holds the fields players may change on their own
+// profile. An empty string clears nickname, throwing hand and avatar.
+type PlayerProfileUpdateRequest struct {
+	Nickname              *string  `json:"nickname" binding:"omitempty,max=50"`
+	ThrowingHand          *string  `json:"throwing_hand"`
+	DartWeight            *float64 `json:"dart_weight"`
+	AvatarURL             *string  `json:"avatar_url" binding:"omitempty,url,max=500"`
+	NotifyTrainingUpdates *bool    `json:"notify_training_updates"`
+	EmailVisible          *bool    `json:"email_visible"`
+	StatsVisible          *bool    `json:"stats_visible"`
+}
+
+// PlayerSettings are the notification and privacy settings of a player. They
+// are only shown to the player and to managers.
+type PlayerSettings struct {
+	NotifyTrainingUpdates bool `json:"notify_training_updates"`
+	EmailVisible          bool `json:"email_visible"`
+	StatsVisible          bool `json:"stats_visible"`
+}
+
+// PlayerStats summarises the completed games and attended trainings of a player
+type PlayerStats struct {
+	GamesPlayed      int `json:"games_played"`
+	Wins             int `json:"wins"`
+	Draws            int `json:"draws"`
+	Losses           int `json:"losses"`
+	SessionsAttended int `json:"sessions_attended"`
+}
+
+// PlayerViewer is the caller a player is shown to. The player and managers see
+// the full profile, other members only what the privacy settings allow.
+type PlayerViewer struct {
+	PlayerID  *uuid.UUID
+	CanManage bool
+}
+
+func (v PlayerViewer) seesAll(p *Player) bool {
+	return v.CanManage || (v.PlayerID != nil && *v.PlayerID == p.ID)
 }
 
 type PlayerResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	Nickname    *string   `json:"nickname"`
-	IsCaptain   bool      `json:"is_captain"`
-	IsActive    bool      `json:"is_active"`
-	TeamID      *uuid.UUID `json:"team_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	TeamName    *string   `json:"team_name,omitempty"`
+	ID           uuid.UUID       `json:"id"`
+	Name         string          `json:"name"`
+	Email        string          `json:"email,omitempty"` // omitted unless visible to the viewer
+	Nickname     *string         `json:"nickname"`
+	IsActive     bool            `json:"is_active"`
+	Category     string          `json:"category"`
+	TeamID       *uuid.UUID      `json:"team_id"`
+	ThrowingHand *string         `json:"throwing_hand"`
+	DartWeight   *float64        `json:"dart_weight"`
+	AvatarURL    *string         `json:"avatar_url"`
+	Settings     *PlayerSettings `json:"settings,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	TeamName     *string         `json:"team_name,omitempty"`
 }
 
 type PlayerWithTeamResponse struct {
-	ID          uuid.UUID  `json:"id"`
-	Name        string     `json:"name"`
-	Email       string     `json:"email"`
-	Nickname    *string    `json:"nickname"`
-	IsCaptain   bool       `json:"is_captain"`
-	IsActive    bool       `json:"is_active"`
-	Team        *Team      `json:"team,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID           uuid.UUID       `json:"id"`
+	Name         string          `json:"name"`
+	Email        string          `json:"email,omitempty"` // omitted unless visible to the viewer
+	Nickname     *string         `json:"nickname"`
+	IsActive     bool            `json:"is_active"`
+	Category     string          `json:"category"`
+	ThrowingHand *string         `json:"throwing_hand"`
+	DartWeight   *float64        `json:"dart_weight"`
+	AvatarURL    *string         `json:"avatar_url"`
+	Settings     *PlayerSettings `json:"settings,omitempty"`
+	Stats        *PlayerStats    `json:"stats,omitempty"` // omitted unless visible to the viewer
+	Team         *Team           `json:"team,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
 }
 
-func (p *Player) ToResponse() PlayerResponse {
+// ToResponse returns the player as shown to viewer, honouring the privacy settings
+func (p *Player) ToResponse(viewer PlayerViewer) PlayerResponse {
 	var teamName *string
 	if p.Team != nil {
 		teamName = &p.Team.Name
 	}
 
-	return PlayerResponse{
-		ID:        p.ID,
-		Name:      p.Name,
-		Email:     p.Email,
-		Nickname:  p.Nickname,
-		IsCaptain: p.IsCaptain,
-		IsActive:  p.IsActive,
-		TeamID:    p.TeamID,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
-		TeamName:  teamName,
+	response := PlayerResponse{
+		ID:           p.ID,
+		Name:         p.Name,
+		Nickname:     p.Nickname,
+		IsActive:     p.IsActive,
+		Category:     p.Category,
+		TeamID:       p.TeamID,
+		ThrowingHand: p.ThrowingHand,
+		DartWeight:   p.DartWeight,
+		AvatarURL:    p.AvatarURL,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+		TeamName:     teamName,
+	}
+	if viewer.seesAll(p) {
+		response.Settings = p.settings()
 	}
+	if viewer.seesAll(p) || p.EmailVisible {
+		response.Email = p.Email
+	}
+	return response
 }
 
-func (p *Player) ToResponseWithTeam() PlayerWithTeamResponse {
-	return PlayerWithTeamResponse{
-		ID:        p.ID,
-		Name:      p.Name,
-		Email:     p.Email,
-		Nickname:  p.Nickname,
-		IsCaptain: p.IsCaptain,
-		IsActive:  p.IsActive,
-		Team:      p.Team,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
+// ToResponseWithTeam returns the player with team and statistics as shown to
+// viewer. stats may be nil if they were not loaded.
+func (p *Player) ToResponseWithTeam(viewer PlayerViewer, stats *PlayerStats) PlayerWithTeamResponse {
+	response := PlayerWithTeamResponse{
+		ID:           p.ID,
+		Name:         p.Name,
+		Nickname:     p.Nickname,
+		IsActive:     p.IsActive,
+		Category:     p.Category,
+		ThrowingHand: p.ThrowingHand,
+		DartWeight:   p.DartWeight,
+		AvatarURL:    p.AvatarURL,
+		Team:         p.Team,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+	if viewer.seesAll(p) {
+		response.Settings = p.settings()
+	}
+	if viewer.seesAll(p) || p.EmailVisible {
+		response.Email = p.Email
 	}
-}
\ No newline at end of file
+	if viewer.seesAll(p) || p.StatsVisible {
+		response.Stats = stats
+	}
+	return response
+}
+
+func (p *Player) settings() *PlayerSettings {
+	return &PlayerSettings{
+		NotifyTrainingUpdates: p.NotifyTrainingUpdates,
+		EmailVisible:          p.EmailVisible,
+		StatsVisible:          p.StatsVisible,
+	}
+}
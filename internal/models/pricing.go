@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PricingPolicy describes how the costs of a training session are calculated.
+// Sessions without a policy charge the flat CostPerPlayer. Policies are
+// attached to single sessions, the app has no training series to attach them to.
+type PricingPolicy struct {
+	ID                     uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID                 uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_pricing_policy_club_active_name,where:deleted_at IS NULL" json:"club_id"`
+	Name                   string         `gorm:"uniqueIndex:idx_pricing_policy_club_active_name,where:deleted_at IS NULL;not null" json:"name"` // deleted policies free their name
+	Description            *string        `json:"description"`
+	Mode                   string         `gorm:"default:'per_session'" json:"mode"` // per_session, per_game
+	MemberRate             Money          `gorm:"embedded;embeddedPrefix:member_rate_" json:"member_rate"`
+	GuestRate              Money          `gorm:"embedded;embeddedPrefix:guest_rate_" json:"guest_rate"`
+	YouthDiscountPercent   float64        `gorm:"default:0" json:"youth_discount_percent"`
+	StudentDiscountPercent float64        `gorm:"default:0" json:"student_discount_percent"`
+	MonthlyCap             *Money         `gorm:"embedded;embeddedPrefix:monthly_cap_" json:"monthly_cap"` // maximum charged to a member per calendar month
+	FreeFirstGuestSession  bool           `gorm:"default:false" json:"free_first_guest_session"`
+	RequireGamePlayed      bool           `gorm:"default:true" json:"require_game_played"`
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
+	DeletedAt              gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type PricingPolicyCreateRequest struct {
+	Name                   string  `json:"name" binding:"required,min=1,max=100"`
+	Description            *string `json:"description"`
+	Mode                   *string `json:"mode"`
+	MemberRate             Money   `json:"member_rate"`
+	GuestRate              Money   `json:"guest_rate"`
+	YouthDiscountPercent   float64 `json:"youth_discount_percent" binding:"gte=0,lte=100"`
+	StudentDiscountPercent float64 `json:"student_discount_percent" binding:"gte=0,lte=100"`
+	MonthlyCap             *Money  `json:"monthly_cap"`
+	FreeFirstGuestSession  bool    `json:"free_first_guest_session"`
+	RequireGamePlayed      *bool   `json:"require_game_played"`
+}
+
+type PricingPolicyUpdateRequest struct {
+	Name                   *string  `json:"name"`
+	Description            *string  `json:"description"`
+	Mode                   *string  `json:"mode"`
+	MemberRate             *Money   `json:"member_rate"`
+	GuestRate              *Money   `json:"guest_rate"`
+	YouthDiscountPercent   *float64 `json:"youth_discount_percent" binding:"omitempty,gte=0,lte=100"`
+	StudentDiscountPercent *float64 `json:"student_discount_percent" binding:"omitempty,gte=0,lte=100"`
+	MonthlyCap             *Money   `json:"monthly_cap"` // a negative value removes the cap
+	FreeFirstGuestSession  *bool    `json:"free_first_guest_session"`
+	RequireGamePlayed      *bool    `json:"require_game_played"`
+}
+
+type PricingPolicyResponse struct {
+	ID                     uuid.UUID `json:"id"`
+	Name                   string    `json:"name"`
+	Description            *string   `json:"description"`
+	Mode                   string    `json:"mode"`
+	MemberRate             Money     `json:"member_rate"`
+	GuestRate              Money     `json:"guest_rate"`
+	YouthDiscountPercent   float64   `json:"youth_discount_percent"`
+	StudentDiscountPercent float64   `json:"student_discount_percent"`
+	MonthlyCap             *Money    `json:"monthly_cap"`
+	FreeFirstGuestSession  bool      `json:"free_first_guest_session"`
+	RequireGamePlayed      bool      `json:"require_game_played"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+func (p *PricingPolicy) ToResponse() PricingPolicyResponse {
+	return PricingPolicyResponse{
+		ID:                     p.ID,
+		Name:                   p.Name,
+		Description:            p.Description,
+		Mode:                   p.Mode,
+		MemberRate:             p.MemberRate,
+		GuestRate:              p.GuestRate,
+		YouthDiscountPercent:   p.YouthDiscountPercent,
+		StudentDiscountPercent: p.StudentDiscountPercent,
+		MonthlyCap:             p.MonthlyCap,
+		FreeFirstGuestSession:  p.FreeFirstGuestSession,
+		RequireGamePlayed:      p.RequireGamePlayed,
+		CreatedAt:              p.CreatedAt,
+		UpdatedAt:              p.UpdatedAt,
+	}
+}
+
+// AfterFind drops the monthly cap when its columns are NULL, gorm allocates
+// embedded pointers even if there is nothing to scan into them
+func (p *PricingPolicy) AfterFind(tx *gorm.DB) error {
+	if p.MonthlyCap != nil && p.MonthlyCap.Currency == "" {
+		p.MonthlyCap = nil
+	}
+	return nil
+}
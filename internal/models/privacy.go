@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlayerDataExport is everything stored about a player, as handed out for a
+// data subject access request
+type PlayerDataExport struct {
+	ExportedAt       time.Time                 `json:"exported_at"`
+	Player           Player                    `json:"player"`
+	Attendance       []PlayerAttendance        `json:"attendance"`
+	TeamMemberships  []TeamMembershipResponse  `json:"team_memberships"`
+	Games            []TrainingGameResponse    `json:"games"`
+	LedgerEntries    []LedgerEntryResponse     `json:"ledger_entries"`
+	Invoices         []InvoiceResponse         `json:"invoices"`
+	SepaMandate      *SepaMandateResponse      `json:"sepa_mandate"`
+	BankTransactions []BankTransactionResponse `json:"bank_transactions"`
+	PaidExpenses     []SessionExpenseResponse  `json:"paid_expenses"`
+	GuestProfiles    []GuestResponse           `json:"guest_profiles"` // guest profiles the player was converted from
+	RoleAssignments  []RoleAssignment          `json:"role_assignments"`
+}
+
+// PlayerAttendance is a training the player was registered for
+type PlayerAttendance struct {
+	TrainingSessionID uuid.UUID `json:"training_session_id"`
+	SessionName       string    `json:"session_name"`
+	TrainingDate      time.Time `json:"training_date"`
+	Attended          bool      `json:"attended"`
+}
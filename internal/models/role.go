@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles known to the application, from most to least privileged
+const (
+	RoleAdmin     = "admin"
+	RoleTreasurer = "treasurer"
+	RoleCaptain   = "captain"
+	RoleMember    = "member"
+	RoleViewer    = "viewer"
+)
+
+// Permissions checked by the routes. A permission ending in ":own" only grants
+// access to resources of the caller's own player.
+const (
+	PermissionTeamsRead     = "teams:read"
+	PermissionTeamsWrite    = "teams:write"
+	PermissionPlayersRead   = "players:read"
+	PermissionPlayersWrite  = "players:write"
+	PermissionPlayersOwn    = "players:write:own"
+	PermissionPlayersInvite = "players:invite"
+	PermissionSessionsRead  = "sessions:read"
+	PermissionSessionsWrite = "sessions:write"
+	PermissionGamesScore    = "games:score"
+	PermissionLineupsWrite  = "lineups:write" // limited to fixtures of teams the caller captains
+	PermissionLedgerRead    = "ledger:read"
+	PermissionLedgerOwn     = "ledger:read:own"
+	PermissionLedgerWrite   = "ledger:write"
+	PermissionPricingWrite  = "pricing:write"
+	PermissionRolesManage   = "roles:manage"
+	PermissionAll           = "*"
+)
+
+// PermissionClubsManage manages the clubs of the deployment. Only the
+// permissions claim of a token grants it, never a club's role assignment.
+const PermissionClubsManage = "clubs:manage"
+
+// RoleAssignment grants a role to a user within a club in addition to the
+// roles of the token
+type RoleAssignment struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_role_assignment_club" json:"club_id"`
+	Subject   string    `gorm:"uniqueIndex:idx_role_assignment_club;not null" json:"subject"` // token subject, e.g. auth0|123
+	Role      string    `gorm:"uniqueIndex:idx_role_assignment_club;not null" json:"role"`
+	CreatedBy *string   `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RoleAssignmentRequest struct {
+	Subject string `json:"subject" binding:"required"`
+	Role    string `json:"role" binding:"required"`
+}
+
+// AccessResponse lists the effective roles and permissions of the caller
+type AccessResponse struct {
+	Subject     string   `json:"subject"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
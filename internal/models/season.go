@@ -0,0 +1,181 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles of a player within a team
+const (
+	TeamRolePlayer      = "player"
+	TeamRoleCaptain     = "captain"
+	TeamRoleViceCaptain = "vice_captain"
+	TeamRoleReserve     = "reserve"
+)
+
+// IsValidTeamRole reports whether role is one of the team roles
+func IsValidTeamRole(role string) bool {
+	switch role {
+	case TeamRolePlayer, TeamRoleCaptain, TeamRoleViceCaptain, TeamRoleReserve:
+		return true
+	}
+	return false
+}
+
+// Season is a league season of the club. Memberships may belong to a season,
+// the season then bounds their validity.
+type Season struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_season_club_name" json:"club_id"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_season_club_name" json:"name"` // unique per club, e.g. 2026/27
+	StartsOn  time.Time `gorm:"type:date;not null" json:"starts_on"`
+	EndsOn    time.Time `gorm:"type:date;not null" json:"ends_on"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Contains reports whether day lies within the season
+func (s *Season) Contains(day time.Time) bool {
+	return !day.Before(s.StartsOn) && !day.After(s.EndsOn)
+}
+
+type SeasonCreateRequest struct {
+	Name     string `json:"name" binding:"required,min=1,max=100"`
+	StartsOn string `json:"starts_on" binding:"required"` // YYYY-MM-DD
+	EndsOn   string `json:"ends_on" binding:"required"`   // YYYY-MM-DD
+}
+
+type SeasonUpdateRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	StartsOn *string `json:"starts_on"`
+	EndsOn   *string `json:"ends_on"`
+}
+
+type SeasonResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	StartsOn  string    `json:"starts_on"`
+	EndsOn    string    `json:"ends_on"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s *Season) ToResponse() SeasonResponse {
+	return SeasonResponse{
+		ID:        s.ID,
+		Name:      s.Name,
+		StartsOn:  s.StartsOn.Format("2006-01-02"),
+		EndsOn:    s.EndsOn.Format("2006-01-02"),
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// TeamMembership records that a player belonged to a team in a role from
+// ValidFrom until ValidUntil, both inclusive. An open membership has no end.
+// Moving a player closes the old membership instead of overwriting it, so the
+// roster of any past date can be answered.
+type TeamMembership struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	TeamID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"team_id"`
+	PlayerID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"player_id"`
+	SeasonID   *uuid.UUID `gorm:"type:uuid;index" json:"season_id"`
+	Role       string     `gorm:"not null;default:'player'" json:"role"` // player, captain, vice_captain, reserve
+	ValidFrom  time.Time  `gorm:"type:date;not null" json:"valid_from"`
+	ValidUntil *time.Time `gorm:"type:date" json:"valid_until"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relationships
+	Team   *Team   `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+	Season *Season `gorm:"foreignKey:SeasonID" json:"season,omitempty"`
+}
+
+// ActiveOn reports whether the membership is valid on day
+func (m *TeamMembership) ActiveOn(day time.Time) bool {
+	return !day.Before(m.ValidFrom) && (m.ValidUntil == nil || !day.After(*m.ValidUntil))
+}
+
+// TeamMembershipCreateRequest adds a player to a team. Without dates the
+// membership covers the season, without season it starts today and stays open.
+type TeamMembershipCreateRequest struct {
+	PlayerID   string  `json:"player_id" binding:"required"`
+	Role       *string `json:"role"`
+	SeasonID   *string `json:"season_id"`
+	ValidFrom  *string `json:"valid_from"`  // YYYY-MM-DD
+	ValidUntil *string `json:"valid_until"` // YYYY-MM-DD
+}
+
+// TeamMembershipUpdateRequest changes role or period, an empty valid_until
+// reopens the membership
+type TeamMembershipUpdateRequest struct {
+	Role       *string `json:"role"`
+	ValidFrom  *string `json:"valid_from"`
+	ValidUntil *string `json:"valid_until"`
+}
+
+type TeamMembershipResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	TeamID     uuid.UUID  `json:"team_id"`
+	TeamName   *string    `json:"team_name,omitempty"`
+	PlayerID   uuid.UUID  `json:"player_id"`
+	PlayerName *string    `json:"player_name,omitempty"`
+	SeasonID   *uuid.UUID `json:"season_id"`
+	SeasonName *string    `json:"season_name,omitempty"`
+	Role       string     `json:"role"`
+	ValidFrom  string     `json:"valid_from"`
+	ValidUntil *string    `json:"valid_until"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (m *TeamMembership) ToResponse() TeamMembershipResponse {
+	response := TeamMembershipResponse{
+		ID:        m.ID,
+		TeamID:    m.TeamID,
+		PlayerID:  m.PlayerID,
+		SeasonID:  m.SeasonID,
+		Role:      m.Role,
+		ValidFrom: m.ValidFrom.Format("2006-01-02"),
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	if m.ValidUntil != nil {
+		validUntil := m.ValidUntil.Format("2006-01-02")
+		response.ValidUntil = &validUntil
+	}
+	if m.Team != nil {
+		response.TeamName = &m.Team.Name
+	}
+	if m.Player != nil {
+		response.PlayerName = &m.Player.Name
+	}
+	if m.Season != nil {
+		response.SeasonName = &m.Season.Name
+	}
+	return response
+}
+
+// RosterEntryResponse is a player of a team roster with the team role
+type RosterEntryResponse struct {
+	PlayerResponse
+	MembershipID uuid.UUID `json:"membership_id"`
+	Role         string    `json:"role"`
+	ValidFrom    string    `json:"valid_from"`
+	ValidUntil   *string   `json:"valid_until"`
+}
+
+// ToRosterEntry returns the member of a loaded membership as shown to viewer
+func (m *TeamMembership) ToRosterEntry(viewer PlayerViewer) RosterEntryResponse {
+	membership := m.ToResponse()
+	return RosterEntryResponse{
+		PlayerResponse: m.Player.ToResponse(viewer),
+		MembershipID:   m.ID,
+		Role:           m.Role,
+		ValidFrom:      membership.ValidFrom,
+		ValidUntil:     membership.ValidUntil,
+	}
+}
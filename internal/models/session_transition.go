@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrainingSessionTransition is an entry in the status history of a training session
+type TrainingSessionTransition struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TrainingSessionID uuid.UUID `gorm:"index;not null" json:"training_session_id"`
+	Action            string    `gorm:"not null" json:"action"` // start, finish, cancel, postpone, reschedule, reopen
+	FromStatus        string    `gorm:"not null" json:"from_status"`
+	ToStatus          string    `gorm:"not null" json:"to_status"`
+	ChangedBy         *string   `json:"changed_by"` // subject of the authenticated user
+	Reason            *string   `json:"reason"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type TrainingSessionTransitionRequest struct {
+	Reason       *string    `json:"reason"`
+	TrainingDate *time.Time `json:"training_date"` // new date when rescheduling
+}
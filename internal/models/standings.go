@@ -0,0 +1,33 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+type StandingsResponse struct {
+	TrainingSessionID uuid.UUID     `json:"training_session_id"`
+	PointsWin         int           `json:"points_win"`
+	PointsDraw        int           `json:"points_draw"`
+	PointsLoss        int           `json:"points_loss"`
+	Tiebreakers       []string      `json:"tiebreakers"`
+	Rows              []StandingRow `json:"rows"`
+}
+
+type StandingRow struct {
+	Rank             int        `json:"rank"`
+	TrainingPlayerID uuid.UUID  `json:"training_player_id"`
+	PlayerID         *uuid.UUID `json:"player_id"`
+	Name             *string    `json:"name"`
+	IsGuest          bool       `json:"is_guest"`
+	Played           int        `json:"played"`
+	Won              int        `json:"won"`
+	Drawn            int        `json:"drawn"`
+	Lost             int        `json:"lost"`
+	Points           int        `json:"points"`
+	LegsFor          int        `json:"legs_for"`
+	LegsAgainst      int        `json:"legs_against"`
+	LegDifference    int        `json:"leg_difference"`
+	HeadToHead       int        `json:"head_to_head"`
+	Average          float64    `json:"average"`
+	Buchholz         int        `json:"buchholz"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SwissBye records a player who sat out a Swiss round and was awarded a win
+type SwissBye struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TrainingSessionID uuid.UUID `gorm:"index;not null" json:"training_session_id"`
+	TrainingPlayerID  uuid.UUID `gorm:"not null" json:"training_player_id"`
+	Round             int       `gorm:"not null" json:"round"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// Relationships
+	TrainingPlayer *TrainingPlayer `gorm:"foreignKey:TrainingPlayerID" json:"training_player,omitempty"`
+}
+
+type SwissStandingResponse struct {
+	Rank             int       `json:"rank"`
+	TrainingPlayerID uuid.UUID `json:"training_player_id"`
+	Name             *string   `json:"name"`
+	Score            float64   `json:"score"`
+	Buchholz         float64   `json:"buchholz"`
+	GamesPlayed      int       `json:"games_played"`
+	Byes             int       `json:"byes"`
+}
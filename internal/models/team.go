@@ -9,7 +9,8 @@ import (
 
 type Team struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Name      string    `gorm:"uniqueIndex;not null" json:"name"`
+	ClubID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_team_club_name" json:"club_id"`
+	Name      string    `gorm:"uniqueIndex:idx_team_club_name;not null" json:"name"` // unique per club
 	LogoURL   *string   `json:"logo_url"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tournament struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	TrainingSessionID uuid.UUID  `gorm:"uniqueIndex;not null" json:"training_session_id"`
+	GameModeID        uuid.UUID  `json:"game_mode_id"`
+	Format            string     `gorm:"not null" json:"format"`          // single_elimination, double_elimination, group_knockout
+	Seeding           string     `gorm:"default:'random'" json:"seeding"` // win_rate, random
+	Status            string     `gorm:"default:'active'" json:"status"`  // group_stage, active, completed
+	GroupCount        int        `gorm:"default:0" json:"group_count"`
+	AdvancePerGroup   int        `gorm:"default:0" json:"advance_per_group"`
+	WinnerID          *uuid.UUID `json:"winner_id"` // TrainingPlayer ID of the champion
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relationships
+	TrainingSession *TrainingSession    `gorm:"foreignKey:TrainingSessionID" json:"training_session,omitempty"`
+	GameMode        *GameMode           `gorm:"foreignKey:GameModeID" json:"game_mode,omitempty"`
+	Winner          *TrainingPlayer     `gorm:"foreignKey:WinnerID" json:"winner,omitempty"`
+	Entrants        []TournamentEntrant `gorm:"foreignKey:TournamentID" json:"entrants,omitempty"`
+	Matches         []TournamentMatch   `gorm:"foreignKey:TournamentID" json:"matches,omitempty"`
+}
+
+type TournamentEntrant struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TournamentID     uuid.UUID `gorm:"index;not null" json:"tournament_id"`
+	TrainingPlayerID uuid.UUID `gorm:"not null" json:"training_player_id"`
+	Seed             int       `gorm:"not null" json:"seed"`
+	GroupIndex       *int      `json:"group_index"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	// Relationships
+	TrainingPlayer *TrainingPlayer `gorm:"foreignKey:TrainingPlayerID" json:"training_player,omitempty"`
+}
+
+// TournamentMatch is a single slot in the bracket. Participants reference
+// TrainingPlayer rows so that guests can take part as well. A slot is "ready"
+// once its source has been decided; a ready slot without a participant is a bye.
+type TournamentMatch struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TournamentID     uuid.UUID  `gorm:"index;not null" json:"tournament_id"`
+	Bracket          string     `gorm:"not null" json:"bracket"` // group, winners, losers, grand_final
+	Round            int        `gorm:"not null" json:"round"`
+	Position         int        `gorm:"not null" json:"position"`
+	GroupIndex       *int       `json:"group_index"`
+	Participant1ID   *uuid.UUID `json:"participant1_id"`
+	Participant2ID   *uuid.UUID `json:"participant2_id"`
+	Slot1Ready       bool       `gorm:"default:false" json:"slot1_ready"`
+	Slot2Ready       bool       `gorm:"default:false" json:"slot2_ready"`
+	NextMatchID      *uuid.UUID `json:"next_match_id"`
+	NextSlot         int        `json:"next_slot"`
+	LoserNextMatchID *uuid.UUID `json:"loser_next_match_id"`
+	LoserNextSlot    int        `json:"loser_next_slot"`
+	TrainingGameID   *uuid.UUID `json:"training_game_id"`
+	WinnerID         *uuid.UUID `json:"winner_id"`
+	Status           string     `gorm:"default:'waiting'" json:"status"` // waiting, pending, completed, bye
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	// Relationships
+	Participant1 *TrainingPlayer `gorm:"foreignKey:Participant1ID" json:"participant1,omitempty"`
+	Participant2 *TrainingPlayer `gorm:"foreignKey:Participant2ID" json:"participant2,omitempty"`
+	TrainingGame *TrainingGame   `gorm:"foreignKey:TrainingGameID" json:"training_game,omitempty"`
+}
+
+// DTOs and Request/Response structures
+type TournamentCreateRequest struct {
+	GameModeID      uuid.UUID `json:"game_mode_id" binding:"required"`
+	Format          string    `json:"format" binding:"required"`
+	Seeding         *string   `json:"seeding"`
+	GroupCount      *int      `json:"group_count"`
+	AdvancePerGroup *int      `json:"advance_per_group"`
+}
+
+type TournamentResponse struct {
+	ID                uuid.UUID                   `json:"id"`
+	TrainingSessionID uuid.UUID                   `json:"training_session_id"`
+	GameModeID        uuid.UUID                   `json:"game_mode_id"`
+	Format            string                      `json:"format"`
+	Seeding           string                      `json:"seeding"`
+	Status            string                      `json:"status"`
+	GroupCount        int                         `json:"group_count"`
+	AdvancePerGroup   int                         `json:"advance_per_group"`
+	WinnerID          *uuid.UUID                  `json:"winner_id"`
+	WinnerName        *string                     `json:"winner_name,omitempty"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	UpdatedAt         time.Time                   `json:"updated_at"`
+	Entrants          []TournamentEntrantResponse `json:"entrants"`
+	Rounds            []TournamentRoundResponse   `json:"rounds"`
+}
+
+type TournamentEntrantResponse struct {
+	TrainingPlayerID uuid.UUID `json:"training_player_id"`
+	Name             *string   `json:"name"`
+	Seed             int       `json:"seed"`
+	GroupIndex       *int      `json:"group_index"`
+}
+
+type TournamentRoundResponse struct {
+	Bracket    string                    `json:"bracket"`
+	Round      int                       `json:"round"`
+	GroupIndex *int                      `json:"group_index,omitempty"`
+	Matches    []TournamentMatchResponse `json:"matches"`
+}
+
+type TournamentMatchResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	Position         int        `json:"position"`
+	Participant1ID   *uuid.UUID `json:"participant1_id"`
+	Participant1Name *string    `json:"participant1_name,omitempty"`
+	Participant2ID   *uuid.UUID `json:"participant2_id"`
+	Participant2Name *string    `json:"participant2_name,omitempty"`
+	Player1Score     *int       `json:"player1_score,omitempty"`
+	Player2Score     *int       `json:"player2_score,omitempty"`
+	WinnerID         *uuid.UUID `json:"winner_id"`
+	NextMatchID      *uuid.UUID `json:"next_match_id"`
+	LoserNextMatchID *uuid.UUID `json:"loser_next_match_id"`
+	TrainingGameID   *uuid.UUID `json:"training_game_id"`
+	Status           string     `json:"status"`
+}
+
+func (t *Tournament) ToResponse() TournamentResponse {
+	var winnerName *string
+	if t.Winner != nil {
+		winnerName = t.Winner.DisplayName()
+	}
+
+	entrants := make([]TournamentEntrantResponse, len(t.Entrants))
+	for i, e := range t.Entrants {
+		var name *string
+		if e.TrainingPlayer != nil {
+			name = e.TrainingPlayer.DisplayName()
+		}
+		entrants[i] = TournamentEntrantResponse{
+			TrainingPlayerID: e.TrainingPlayerID,
+			Name:             name,
+			Seed:             e.Seed,
+			GroupIndex:       e.GroupIndex,
+		}
+	}
+
+	// Matches are expected to be ordered by bracket, group, round and position
+	var rounds []TournamentRoundResponse
+	for _, m := range t.Matches {
+		last := len(rounds) - 1
+		if last < 0 || rounds[last].Bracket != m.Bracket || rounds[last].Round != m.Round || !sameGroup(rounds[last].GroupIndex, m.GroupIndex) {
+			rounds = append(rounds, TournamentRoundResponse{
+				Bracket:    m.Bracket,
+				Round:      m.Round,
+				GroupIndex: m.GroupIndex,
+			})
+			last++
+		}
+		rounds[last].Matches = append(rounds[last].Matches, m.ToResponse())
+	}
+
+	return TournamentResponse{
+		ID:                t.ID,
+		TrainingSessionID: t.TrainingSessionID,
+		GameModeID:        t.GameModeID,
+		Format:            t.Format,
+		Seeding:           t.Seeding,
+		Status:            t.Status,
+		GroupCount:        t.GroupCount,
+		AdvancePerGroup:   t.AdvancePerGroup,
+		WinnerID:          t.WinnerID,
+		WinnerName:        winnerName,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
+		Entrants:          entrants,
+		Rounds:            rounds,
+	}
+}
+
+func (m *TournamentMatch) ToResponse() TournamentMatchResponse {
+	var participant1Name, participant2Name *string
+	var player1Score, player2Score *int
+
+	if m.Participant1 != nil {
+		participant1Name = m.Participant1.DisplayName()
+	}
+	if m.Participant2 != nil {
+		participant2Name = m.Participant2.DisplayName()
+	}
+	if m.TrainingGame != nil {
+		player1Score = &m.TrainingGame.Player1Score
+		player2Score = &m.TrainingGame.Player2Score
+	}
+
+	return TournamentMatchResponse{
+		ID:               m.ID,
+		Position:         m.Position,
+		Participant1ID:   m.Participant1ID,
+		Participant1Name: participant1Name,
+		Participant2ID:   m.Participant2ID,
+		Participant2Name: participant2Name,
+		Player1Score:     player1Score,
+		Player2Score:     player2Score,
+		WinnerID:         m.WinnerID,
+		NextMatchID:      m.NextMatchID,
+		LoserNextMatchID: m.LoserNextMatchID,
+		TrainingGameID:   m.TrainingGameID,
+		Status:           m.Status,
+	}
+}
+
+func sameGroup(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,11 +10,19 @@ import (
 
 type TrainingSession struct {
 	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
 	Name          string     `gorm:"not null" json:"name"`
 	Description   *string    `json:"description"`
 	TrainingDate  time.Time  `gorm:"not null" json:"training_date"`
-	CostPerPlayer float64    `gorm:"default:5.00" json:"cost_per_player"`
-	Status        string     `gorm:"default:'planned'" json:"status"` // planned, active, completed, cancelled
+	CostPerPlayer Money      `gorm:"embedded;embeddedPrefix:cost_per_player_" json:"cost_per_player"`
+	PricingPolicyID *uuid.UUID `json:"pricing_policy_id"`
+	Status        string     `gorm:"default:'planned'" json:"status"` // planned, active, completed, cancelled, postponed
+	PointsWin     int        `gorm:"default:2" json:"points_win"`
+	PointsDraw    int        `gorm:"default:1" json:"points_draw"`
+	PointsLoss    int        `gorm:"default:0" json:"points_loss"`
+	Tiebreakers   string     `gorm:"default:'leg_difference,head_to_head,average'" json:"tiebreakers"` // comma separated, applied in order
+	CancellationReason *string    `json:"cancellation_reason"`
+	CancelledAt   *time.Time `json:"cancelled_at"`
 	CreatedBy     *uuid.UUID `json:"created_by"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
@@ -21,6 +30,7 @@ type TrainingSession struct {
 
 	// Relationships
 	Creator          *Player          `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	PricingPolicy    *PricingPolicy   `gorm:"foreignKey:PricingPolicyID" json:"pricing_policy,omitempty"`
 	TrainingPlayers  []TrainingPlayer `gorm:"foreignKey:TrainingSessionID" json:"training_players,omitempty"`
 	Games            []TrainingGame   `gorm:"foreignKey:TrainingSessionID" json:"games,omitempty"`
 }
@@ -29,7 +39,8 @@ type TrainingPlayer struct {
 	ID                 uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	TrainingSessionID  uuid.UUID `json:"training_session_id"`
 	PlayerID           *uuid.UUID `json:"player_id"`
-	GuestName          *string   `json:"guest_name"`
+	GuestID            *uuid.UUID `gorm:"type:uuid;index" json:"guest_id"`
+	GuestName          *string   `json:"guest_name"` // copy of the guest's name
 	IsGuest            bool      `gorm:"default:false" json:"is_guest"`
 	Attended           bool      `gorm:"default:true" json:"attended"`
 	CreatedAt          time.Time `json:"created_at"`
@@ -37,10 +48,12 @@ type TrainingPlayer struct {
 	// Relationships
 	TrainingSession *TrainingSession `gorm:"foreignKey:TrainingSessionID" json:"training_session,omitempty"`
 	Player          *Player          `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+	Guest           *Guest           `gorm:"foreignKey:GuestID" json:"guest,omitempty"`
 }
 
 type GameMode struct {
 	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClubID      uuid.UUID `gorm:"type:uuid;not null;index" json:"club_id"`
 	Name        string    `gorm:"not null" json:"name"`
 	Description *string   `json:"description"`
 	Rules       string    `gorm:"type:jsonb" json:"rules"` // JSONB for flexible rules
@@ -54,16 +67,23 @@ type GameMode struct {
 
 type TrainingGame struct {
 	ID                 uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	TrainingSessionID  uuid.UUID  `json:"training_session_id"`
+	ClubID             uuid.UUID  `gorm:"type:uuid;not null;index" json:"club_id"`
+	TrainingSessionID  *uuid.UUID `json:"training_session_id"` // nil for league fixture games
+	FixtureID          *uuid.UUID `gorm:"type:uuid;index" json:"fixture_id"`
 	GameModeID         uuid.UUID  `json:"game_mode_id"`
 	Player1ID          *uuid.UUID `json:"player1_id"`
 	Player2ID          *uuid.UUID `json:"player2_id"`
+	Guest1ID           *uuid.UUID `gorm:"type:uuid;index" json:"guest1_id"`
+	Guest2ID           *uuid.UUID `gorm:"type:uuid;index" json:"guest2_id"`
 	Guest1Name         *string    `json:"guest1_name"`
 	Guest2Name         *string    `json:"guest2_name"`
 	Player1Score       int        `gorm:"default:0" json:"player1_score"`
 	Player2Score       int        `gorm:"default:0" json:"player2_score"`
 	Status             string     `gorm:"default:'pending'" json:"status"` // pending, playing, completed, cancelled
 	Winner             *string    `json:"winner"` // 'player1', 'player2', 'draw'
+	Round              *int       `json:"round"` // Swiss round, nil for other games
+	GameNumber         *int       `json:"game_number"` // position in the match format of a fixture
+	Partner1ID         *uuid.UUID `gorm:"type:uuid" json:"partner1_id"` // doubles partner of player 1 in fixtures
 	CompletedAt        *time.Time `json:"completed_at"`
 	CreatedAt          time.Time  `json:"created_at"`
 
@@ -72,6 +92,7 @@ type TrainingGame struct {
 	GameMode        *GameMode        `gorm:"foreignKey:GameModeID" json:"game_mode,omitempty"`
 	Player1         *Player          `gorm:"foreignKey:Player1ID" json:"player1,omitempty"`
 	Player2         *Player          `gorm:"foreignKey:Player2ID" json:"player2,omitempty"`
+	Partner1        *Player          `gorm:"foreignKey:Partner1ID" json:"partner1,omitempty"`
 }
 
 // DTOs and Request/Response structures
@@ -79,15 +100,25 @@ type TrainingSessionCreateRequest struct {
 	Name          string    `json:"name" binding:"required,min=1,max=200"`
 	Description   *string   `json:"description"`
 	TrainingDate  time.Time `json:"training_date" binding:"required"`
-	CostPerPlayer *float64  `json:"cost_per_player"`
+	CostPerPlayer *Money    `json:"cost_per_player"`
+	PricingPolicyID *string   `json:"pricing_policy_id"`
+	PointsWin     *int      `json:"points_win"`
+	PointsDraw    *int      `json:"points_draw"`
+	PointsLoss    *int      `json:"points_loss"`
+	Tiebreakers   []string  `json:"tiebreakers"`
 }
 
 type TrainingSessionUpdateRequest struct {
 	Name          *string    `json:"name"`
 	Description   *string    `json:"description"`
 	TrainingDate  *time.Time `json:"training_date"`
-	CostPerPlayer *float64  `json:"cost_per_player"`
+	CostPerPlayer *Money     `json:"cost_per_player"`
+	PricingPolicyID *string    `json:"pricing_policy_id"` // an empty string removes the policy
 	Status        *string    `json:"status"`
+	PointsWin     *int       `json:"points_win"`
+	PointsDraw    *int       `json:"points_draw"`
+	PointsLoss    *int       `json:"points_loss"`
+	Tiebreakers   []string   `json:"tiebreakers"`
 }
 
 type TrainingSessionResponse struct {
@@ -95,8 +126,16 @@ type TrainingSessionResponse struct {
 	Name             string                  `json:"name"`
 	Description      *string                 `json:"description"`
 	TrainingDate     time.Time               `json:"training_date"`
-	CostPerPlayer    float64                 `json:"cost_per_player"`
+	CostPerPlayer    Money                   `json:"cost_per_player"`
+	PricingPolicyID  *uuid.UUID              `json:"pricing_policy_id"`
+	PricingPolicyName *string                 `json:"pricing_policy_name,omitempty"`
 	Status           string                  `json:"status"`
+	PointsWin        int                     `json:"points_win"`
+	PointsDraw       int                     `json:"points_draw"`
+	PointsLoss       int                     `json:"points_loss"`
+	Tiebreakers      []string                `json:"tiebreakers"`
+	CancellationReason *string                 `json:"cancellation_reason,omitempty"`
+	CancelledAt      *time.Time              `json:"cancelled_at,omitempty"`
 	CreatedBy        *uuid.UUID              `json:"created_by"`
 	CreatedAt        time.Time               `json:"created_at"`
 	UpdatedAt        time.Time               `json:"updated_at"`
@@ -111,6 +150,7 @@ type TrainingPlayerResponse struct {
 	ID                uuid.UUID  `json:"id"`
 	TrainingSessionID uuid.UUID  `json:"training_session_id"`
 	PlayerID          *uuid.UUID `json:"player_id"`
+	GuestID           *uuid.UUID `json:"guest_id"`
 	GuestName         *string    `json:"guest_name"`
 	IsGuest           bool       `json:"is_guest"`
 	Attended          bool       `json:"attended"`
@@ -120,21 +160,28 @@ type TrainingPlayerResponse struct {
 
 type TrainingGameResponse struct {
 	ID                uuid.UUID  `json:"id"`
-	TrainingSessionID uuid.UUID  `json:"training_session_id"`
+	TrainingSessionID *uuid.UUID `json:"training_session_id"`
+	FixtureID         *uuid.UUID `json:"fixture_id,omitempty"`
 	GameModeID        uuid.UUID  `json:"game_mode_id"`
 	Player1ID         *uuid.UUID `json:"player1_id"`
 	Player2ID         *uuid.UUID `json:"player2_id"`
+	Partner1ID        *uuid.UUID `json:"partner1_id,omitempty"`
+	Guest1ID          *uuid.UUID `json:"guest1_id"`
+	Guest2ID          *uuid.UUID `json:"guest2_id"`
 	Guest1Name        *string    `json:"guest1_name"`
 	Guest2Name        *string    `json:"guest2_name"`
 	Player1Score      int        `json:"player1_score"`
 	Player2Score      int        `json:"player2_score"`
 	Status            string     `json:"status"`
 	Winner            *string    `json:"winner"`
+	Round             *int       `json:"round,omitempty"`
+	GameNumber        *int       `json:"game_number,omitempty"`
 	CompletedAt       *time.Time `json:"completed_at"`
 	CreatedAt         time.Time  `json:"created_at"`
 	GameModeName      *string    `json:"game_mode_name,omitempty"`
 	Player1Name       *string    `json:"player1_name,omitempty"`
 	Player2Name       *string    `json:"player2_name,omitempty"`
+	Partner1Name      *string    `json:"partner1_name,omitempty"`
 }
 
 type GameModeResponse struct {
@@ -150,16 +197,23 @@ type GameModeResponse struct {
 type PlayerCost struct {
 	PlayerID   uuid.UUID  `json:"player_id"`
 	PlayerName *string    `json:"player_name"`
+	GuestID    *uuid.UUID `json:"guest_id,omitempty"`
 	GuestName  *string    `json:"guest_name"`
 	IsGuest    bool       `json:"is_guest"`
-	TotalCost  float64    `json:"total_cost"`
+	Fee        Money      `json:"fee"` // training fee from the pricing policy or cost per player
+	ExpenseShare Money      `json:"expense_share"` // share of the session expenses
+	TotalCost  Money      `json:"total_cost"` // fee plus expense share
 	GamesPlayed int       `json:"games_played"`
+	Adjustments []string   `json:"adjustments,omitempty"` // discounts, caps and free sessions applied by the pricing policy
 }
 
 type TrainingCostsResponse struct {
 	TrainingSessionID uuid.UUID   `json:"training_session_id"`
+	PricingPolicy     *string     `json:"pricing_policy,omitempty"`
 	PlayerCosts       []PlayerCost `json:"player_costs"`
-	TotalCollected    float64     `json:"total_collected"`
+	Expenses          []ExpenseAllocation `json:"expenses"`
+	TotalExpenses     Money       `json:"total_expenses"`
+	TotalCollected    Money       `json:"total_collected"`
 }
 
 func (t *TrainingSession) ToResponse() TrainingSessionResponse {
@@ -171,6 +225,11 @@ func (t *TrainingSession) ToResponse() TrainingSessionResponse {
 		creatorName = &t.Creator.Name
 	}
 
+	var pricingPolicyName *string
+	if t.PricingPolicy != nil {
+		pricingPolicyName = &t.PricingPolicy.Name
+	}
+
 	trainingPlayers := make([]TrainingPlayerResponse, len(t.TrainingPlayers))
 	for i, tp := range t.TrainingPlayers {
 		trainingPlayers[i] = tp.ToResponse()
@@ -187,7 +246,15 @@ func (t *TrainingSession) ToResponse() TrainingSessionResponse {
 		Description:      t.Description,
 		TrainingDate:     t.TrainingDate,
 		CostPerPlayer:    t.CostPerPlayer,
+		PricingPolicyID:  t.PricingPolicyID,
+		PricingPolicyName: pricingPolicyName,
 		Status:           t.Status,
+		PointsWin:        t.PointsWin,
+		PointsDraw:       t.PointsDraw,
+		PointsLoss:       t.PointsLoss,
+		Tiebreakers:      t.TiebreakerList(),
+		CancellationReason: t.CancellationReason,
+		CancelledAt:      t.CancelledAt,
 		CreatedBy:        t.CreatedBy,
 		CreatedAt:        t.CreatedAt,
 		UpdatedAt:        t.UpdatedAt,
@@ -209,6 +276,7 @@ func (tp *TrainingPlayer) ToResponse() TrainingPlayerResponse {
 		ID:                tp.ID,
 		TrainingSessionID: tp.TrainingSessionID,
 		PlayerID:          tp.PlayerID,
+		GuestID:           tp.GuestID,
 		GuestName:         tp.GuestName,
 		IsGuest:           tp.IsGuest,
 		Attended:          tp.Attended,
@@ -218,7 +286,7 @@ func (tp *TrainingPlayer) ToResponse() TrainingPlayerResponse {
 }
 
 func (g *TrainingGame) ToResponse() TrainingGameResponse {
-	var gameModeName, player1Name, player2Name *string
+	var gameModeName, player1Name, player2Name, partner1Name *string
 
 	if g.GameMode != nil {
 		gameModeName = &g.GameMode.Name
@@ -229,24 +297,34 @@ func (g *TrainingGame) ToResponse() TrainingGameResponse {
 	if g.Player2 != nil {
 		player2Name = &g.Player2.Name
 	}
+	if g.Partner1 != nil {
+		partner1Name = &g.Partner1.Name
+	}
 
 	return TrainingGameResponse{
 		ID:                g.ID,
 		TrainingSessionID: g.TrainingSessionID,
+		FixtureID:         g.FixtureID,
 		GameModeID:        g.GameModeID,
 		Player1ID:         g.Player1ID,
 		Player2ID:         g.Player2ID,
+		Partner1ID:        g.Partner1ID,
+		Guest1ID:          g.Guest1ID,
+		Guest2ID:          g.Guest2ID,
 		Guest1Name:        g.Guest1Name,
 		Guest2Name:        g.Guest2Name,
 		Player1Score:      g.Player1Score,
 		Player2Score:      g.Player2Score,
 		Status:            g.Status,
 		Winner:            g.Winner,
+		Round:             g.Round,
+		GameNumber:        g.GameNumber,
 		CompletedAt:       g.CompletedAt,
 		CreatedAt:         g.CreatedAt,
 		GameModeName:      gameModeName,
 		Player1Name:       player1Name,
 		Player2Name:       player2Name,
+		Partner1Name:      partner1Name,
 	}
 }
 
@@ -260,4 +338,35 @@ func (gm *GameMode) ToResponse() GameModeResponse {
 		CreatedAt:   gm.CreatedAt,
 		UpdatedAt:   gm.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
+
+// TiebreakerList returns the configured standings tiebreakers in the order they are applied
+func (t *TrainingSession) TiebreakerList() []string {
+	if t.Tiebreakers == "" {
+		return []string{}
+	}
+	return strings.Split(t.Tiebreakers, ",")
+}
+
+// DisplayName returns the player's name for regular players and the guest name for guests
+func (tp *TrainingPlayer) DisplayName() *string {
+	if tp.Player != nil {
+		return &tp.Player.Name
+	}
+	return tp.GuestName
+}
+
+// Matches reports whether the training player is the game participant with
+// the given player or guest. Guests without a profile are matched by name.
+func (tp *TrainingPlayer) Matches(playerID, guestID *uuid.UUID, guestName *string) bool {
+	if tp.PlayerID != nil {
+		return playerID != nil && *playerID == *tp.PlayerID
+	}
+	if !tp.IsGuest || playerID != nil {
+		return false
+	}
+	if tp.GuestID != nil && guestID != nil {
+		return *tp.GuestID == *guestID
+	}
+	return tp.GuestName != nil && guestName != nil && *tp.GuestName == *guestName
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefix makes keys recognisable, e.g. for secret scanners
+const apiKeyPrefix = "dtk_"
+
+// apiKeyDisplayLength is the number of leading characters kept in clear text
+const apiKeyDisplayLength = 12
+
+// apiKeyScopes are the permissions an API key can be granted. Managing roles
+// and the "*" permission stay reserved for people.
+var apiKeyScopes = map[string]bool{
+	models.PermissionTeamsRead:     true,
+	models.PermissionTeamsWrite:    true,
+	models.PermissionPlayersRead:   true,
+	models.PermissionPlayersWrite:  true,
+	models.PermissionSessionsRead:  true,
+	models.PermissionSessionsWrite: true,
+	models.PermissionGamesScore:    true,
+	models.PermissionLedgerRead:    true,
+	models.PermissionLedgerWrite:   true,
+	models.PermissionPricingWrite:  true,
+}
+
+// Authentication failures reported for API keys
+var (
+	ErrAPIKeyInvalid = errors.New("invalid api key")
+	ErrAPIKeyExpired = errors.New("api key has expired")
+)
+
+type APIKeyService struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyService(db *gorm.DB) *APIKeyService {
+	return &APIKeyService{db: db}
+}
+
+// WithContext returns the service bound to ctx
+func (s *APIKeyService) WithContext(ctx context.Context) *APIKeyService {
+	return &APIKeyService{db: s.db.WithContext(ctx)}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *APIKeyService) GetAPIKeys() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := s.db.Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// CreateAPIKey generates a new key and returns it in plain text together with
+// the stored record. Only the hash is persisted.
+func (s *APIKeyService) CreateAPIKey(req *models.APIKeyCreateRequest, createdBy *string) (*models.APIKey, string, error) {
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := map[string]bool{}
+	for _, scope := range req.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !apiKeyScopes[scope] {
+			return nil, "", fmt.Errorf("invalid scope: %s", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("expiry must be in the future")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey := &models.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.Create(apiKey).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return apiKey, key, nil
+}
+
+// RevokeAPIKey disables a key, the record is kept for the audit trail
+func (s *APIKeyService) RevokeAPIKey(id uuid.UUID) error {
+	var apiKey models.APIKey
+	if err := s.db.First(&apiKey, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("api key not found")
+		}
+		return fmt.Errorf("failed to fetch api key: %w", err)
+	}
+	if apiKey.RevokedAt != nil {
+		return fmt.Errorf("api key is already revoked")
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&apiKey).Update("revoked_at", now).Error; err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// Authenticate looks up an active key and records when it was last used
+func (s *APIKeyService) Authenticate(key string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	var apiKey models.APIKey
+	if err := s.db.First(&apiKey, "key_hash = ?", hashAPIKey(key)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAPIKeyInvalid
+		}
+		return nil, fmt.Errorf("failed to fetch api key: %w", err)
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	// UpdateColumn leaves updated_at alone, usage is not a change of the key
+	if err := s.db.Model(&apiKey).UpdateColumn("last_used_at", now).Error; err != nil {
+		return nil, fmt.Errorf("failed to record api key usage: %w", err)
+	}
+	apiKey.LastUsedAt = &now
+
+	return &apiKey, nil
+}
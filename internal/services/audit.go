@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditService struct {
+	db *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// WithContext returns the service bound to ctx
+func (s *AuditService) WithContext(ctx context.Context) *AuditService {
+	return &AuditService{db: s.db.WithContext(ctx)}
+}
+
+// GetAuditLogs returns the newest entries first, optionally for one entity only
+func (s *AuditService) GetAuditLogs(entityID *uuid.UUID) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	query := s.db.Order("created_at DESC")
+	if entityID != nil {
+		query = query.Where("entity_id = ?", *entityID)
+	}
+	if err := query.Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch audit logs: %w", err)
+	}
+	return logs, nil
+}
+
+// recordAudit writes an audit entry with db, usually the transaction of the
+// audited change so both are stored or neither
+func recordAudit(db *gorm.DB, entry *models.AuditLog) error {
+	if err := db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/MicahParks/keyfunc"
 	"github.com/rs/zerolog/log"
@@ -14,61 +15,206 @@ import (
 
 const oidcURLPart = "/.well-known/openid-configuration"
 
+const (
+	// authRequestTimeout bounds every request to the identity provider
+	authRequestTimeout = 10 * time.Second
+	// authRetryInterval is used instead of the refresh interval while the
+	// identity provider cannot be reached
+	authRetryInterval = 30 * time.Second
+	// jwksRefreshRateLimit limits refreshes triggered by unknown key IDs
+	jwksRefreshRateLimit = 5 * time.Minute
+	// clientCredentialMargin renews the client credential before it expires
+	clientCredentialMargin = time.Minute
+	// defaultClientCredentialLifetime is used when the provider sends no expires_in
+	defaultClientCredentialLifetime = 5 * time.Minute
+)
+
+// AuthManager talks to the OIDC provider. Discovery document and JWKS are
+// loaded on first use, so the server starts while the provider is down, and
+// are refreshed in the background once StartRefresh is called. All fields
+// behind mutex may be replaced at any time and are only read under the lock.
 type AuthManager struct {
-	configuration         *config.Config
-	restClient            *resty.Client
-	jwks                  *keyfunc.JWKS
-	oidc                  *models.OpenIDConfiguration
-	oidcMutex             sync.Mutex
-	tokenEndpointResponse *models.TokenEndpointResponse
+	configuration *config.Config
+	restClient    *resty.Client
+	validator     TokenValidator
+	flights       flightGroup
+
+	mutex                   sync.RWMutex
+	jwks                    *keyfunc.JWKS
+	oidc                    *models.OpenIDConfiguration
+	tokenEndpointResponse   *models.TokenEndpointResponse
+	clientCredentialExpires time.Time
+	lastRefresh             *time.Time
+	lastAttempt             time.Time
+	lastError               error
 }
 
 func NewAuthManager(configuration *config.Config) *AuthManager {
-	authManager := &AuthManager{
+	return &AuthManager{
 		configuration: configuration,
-		restClient:    resty.New(),
+		restClient:    resty.New().SetTimeout(authRequestTimeout),
+		validator:     NewTokenValidator(configuration, configuration.JWTIssuer),
 	}
+}
 
-	err := authManager.loadJWKS()
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to load OIDC from the authentication provider!")
-		return nil
+// StartRefresh reloads discovery document and JWKS now and then every
+// interval, retrying sooner while the provider is unreachable
+func (m *AuthManager) StartRefresh(interval time.Duration) {
+	go func() {
+		for {
+			wait := interval
+			if err := m.Refresh(); err != nil {
+				log.Error().Err(err).Msg("Failed to refresh keys of the authentication provider")
+				if authRetryInterval < interval {
+					wait = authRetryInterval
+				}
+			}
+			time.Sleep(wait)
+		}
+	}()
+}
+
+// Refresh reloads discovery document and JWKS. The previous keys stay in use
+// if the provider cannot be reached.
+func (m *AuthManager) Refresh() error {
+	err := m.flights.Do("oidc", m.loadOIDC)
+	if err == nil {
+		err = m.flights.Do("jwks", m.loadJWKS)
+	}
+	m.recordRefresh(err)
+	return err
+}
+
+// Status reports whether tokens can be verified. The manager stays ready with
+// the last known keys when a refresh fails, the error is reported nonetheless.
+func (m *AuthManager) Status() models.AuthStatus {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	status := models.AuthStatus{
+		Mode:        "auth0",
+		Ready:       m.jwks != nil,
+		LastRefresh: m.lastRefresh,
+	}
+	if m.lastError != nil {
+		status.Error = m.lastError.Error()
 	}
+	return status
+}
 
-	return authManager
+func (m *AuthManager) recordRefresh(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.lastError = err
+	m.lastAttempt = time.Now()
+	if err == nil {
+		now := time.Now()
+		m.lastRefresh = &now
+	}
 }
 
 func (m *AuthManager) GetJWKS() (*keyfunc.JWKS, error) {
+	m.mutex.RLock()
+	jwks := m.jwks
+	m.mutex.RUnlock()
+	if jwks != nil {
+		return jwks, nil
+	}
+
+	// Requests arriving while the provider is down fail fast instead of
+	// waiting for another timeout each
+	if err := m.recentFailure(); err != nil {
+		return nil, err
+	}
+	if err := m.Refresh(); err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	if m.jwks == nil {
-		if err := m.loadJWKS(); err != nil {
-			return nil, err
-		}
+		return nil, errors.New("no JWKS")
 	}
 	return m.jwks, nil
 }
 
+func (m *AuthManager) recentFailure() error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.lastError != nil && time.Since(m.lastAttempt) < authRetryInterval {
+		return m.lastError
+	}
+	return nil
+}
+
+func (m *AuthManager) getOIDC() (*models.OpenIDConfiguration, error) {
+	m.mutex.RLock()
+	oidc := m.oidc
+	m.mutex.RUnlock()
+	if oidc != nil {
+		return oidc, nil
+	}
+
+	if err := m.flights.Do("oidc", m.loadOIDC); err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.oidc == nil {
+		return nil, errors.New("no OIDC configuration")
+	}
+	return m.oidc, nil
+}
+
 func (m *AuthManager) InvalidateClientCredential() {
+	m.mutex.Lock()
 	m.tokenEndpointResponse = nil
+	m.mutex.Unlock()
 }
 
+// GetClientCredential returns the cached client credential token and fetches
+// a new one shortly before it expires
 func (m *AuthManager) GetClientCredential() (string, error) {
-	if m.tokenEndpointResponse == nil {
-		err := m.refreshClientCredential()
-		if err != nil || m.tokenEndpointResponse == nil {
-			return "", errors.New("no client credential")
-		}
+	if token, ok := m.cachedClientCredential(); ok {
+		return token, nil
+	}
+
+	if err := m.flights.Do("client-credential", m.refreshClientCredential); err != nil {
+		return "", errors.New("no client credential")
+	}
+
+	token, ok := m.cachedClientCredential()
+	if !ok {
+		return "", errors.New("no client credential")
+	}
+	return token, nil
+}
+
+func (m *AuthManager) cachedClientCredential() (string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.tokenEndpointResponse == nil || !time.Now().Before(m.clientCredentialExpires) {
+		return "", false
 	}
-	return m.tokenEndpointResponse.AccessToken, nil
+	return m.tokenEndpointResponse.AccessToken, true
 }
 
 func (m *AuthManager) refreshClientCredential() error {
-	if err := m.ensureOIDC(); err != nil {
+	oidc, err := m.getOIDC()
+	if err != nil {
 		return err
 	}
 
-	tokenEndpointResponse, err := m.callAuthProviderTokenEndpoint()
+	tokenEndpointResponse, err := m.callAuthProviderTokenEndpoint(oidc)
 	if tokenEndpointResponse == nil || err != nil {
 		log.Error().Err(err).Msg("Failed to load JWT token from the authentication provider")
+		if err == nil {
+			err = errors.New("empty token response")
+		}
 		return err
 	}
 
@@ -77,20 +223,54 @@ func (m *AuthManager) refreshClientCredential() error {
 		return errors.New("invalid token type")
 	}
 
+	m.mutex.Lock()
 	m.tokenEndpointResponse = tokenEndpointResponse
+	m.clientCredentialExpires = time.Now().Add(clientCredentialLifetime(tokenEndpointResponse.ExpiresIn))
+	m.mutex.Unlock()
+
+	return nil
+}
+
+// clientCredentialLifetime is the time a token is reused, short-lived tokens
+// are renewed after half their lifetime instead of a fixed margin
+func clientCredentialLifetime(expiresIn int) time.Duration {
+	lifetime := time.Duration(expiresIn) * time.Second
+	switch {
+	case lifetime <= 0:
+		return defaultClientCredentialLifetime
+	case lifetime <= 2*clientCredentialMargin:
+		return lifetime / 2
+	default:
+		return lifetime - clientCredentialMargin
+	}
+}
 
+func (m *AuthManager) loadOIDC() error {
+	oidc, err := m.callAuthProviderOIDCEndpoint()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load OIDC")
+		return err
+	}
+
+	m.mutex.Lock()
+	m.oidc = oidc
+	m.mutex.Unlock()
 	return nil
 }
 
+// loadJWKS fetches the key set into a new JWKS and swaps it in, so requests
+// keep using the old keys until the new ones are complete
 func (m *AuthManager) loadJWKS() error {
-	err := m.ensureOIDC()
+	oidc, err := m.getOIDC()
 	if err != nil {
 		return err
 	}
 
-	jwks, err := keyfunc.Get(m.oidc.JwksURI, keyfunc.Options{
+	jwks, err := keyfunc.Get(oidc.JwksURI, keyfunc.Options{
 		Client:              m.restClient.GetClient(),
 		RefreshErrorHandler: m.refreshErrorHandler,
+		RefreshRateLimit:    jwksRefreshRateLimit,
+		RefreshTimeout:      authRequestTimeout,
 		RefreshUnknownKID:   true,
 	})
 	if err != nil {
@@ -98,7 +278,14 @@ func (m *AuthManager) loadJWKS() error {
 		return err
 	}
 
+	m.mutex.Lock()
+	previous := m.jwks
 	m.jwks = jwks
+	m.mutex.Unlock()
+
+	if previous != nil {
+		previous.EndBackground()
+	}
 	return nil
 }
 
@@ -118,8 +305,8 @@ func (m *AuthManager) callAuthProviderOIDCEndpoint() (*models.OpenIDConfiguratio
 	}
 
 	if !response.IsSuccess() {
-		log.Error().Err(err).Msgf("Failed to get OIDC from the authentication provider: %v", response.Error())
-		return nil, err
+		log.Error().Msgf("Failed to get OIDC from the authentication provider: %v", response.Status())
+		return nil, errors.New("OIDC discovery request failed")
 	}
 
 	oidc := response.Result().(*models.OpenIDConfiguration)
@@ -127,7 +314,7 @@ func (m *AuthManager) callAuthProviderOIDCEndpoint() (*models.OpenIDConfiguratio
 	return oidc, nil
 }
 
-func (m *AuthManager) callAuthProviderTokenEndpoint() (*models.TokenEndpointResponse, error) {
+func (m *AuthManager) callAuthProviderTokenEndpoint(oidc *models.OpenIDConfiguration) (*models.TokenEndpointResponse, error) {
 	response, err := m.restClient.R().
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
 		SetHeader("Cache-Control", "no-cache").
@@ -135,7 +322,7 @@ func (m *AuthManager) callAuthProviderTokenEndpoint() (*models.TokenEndpointResp
 		SetAuthToken(m.configuration.ClientCredentialAuthHeaderValue).
 		SetResult(&models.TokenEndpointResponse{}).
 		SetFormData(map[string]string{"grant_type": "client_credentials"}).
-		Post(m.oidc.TokenEndpoint)
+		Post(oidc.TokenEndpoint)
 
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get JWT token from the authentication provider's token endpoint")
@@ -143,8 +330,8 @@ func (m *AuthManager) callAuthProviderTokenEndpoint() (*models.TokenEndpointResp
 	}
 
 	if !response.IsSuccess() {
-		log.Error().Err(err).Msgf("Failed to get JWT token from the authentication provider's token endpoint: %v", response.Status())
-		return nil, err
+		log.Error().Msgf("Failed to get JWT token from the authentication provider's token endpoint: %v", response.Status())
+		return nil, errors.New("token request failed")
 	}
 
 	tokenEndpointResponse := response.Result().(*models.TokenEndpointResponse)
@@ -152,22 +339,30 @@ func (m *AuthManager) callAuthProviderTokenEndpoint() (*models.TokenEndpointResp
 	return tokenEndpointResponse, nil
 }
 
-func (m *AuthManager) ensureOIDC() error {
-	if m.oidc == nil {
-		oidc, err := m.callAuthProviderOIDCEndpoint()
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to load OIDC")
-			return err
-		}
+// GetUserInfo calls the OIDC userinfo endpoint with the user's access token
+func (m *AuthManager) GetUserInfo(accessToken string) (*models.Auth0User, error) {
+	oidc, err := m.getOIDC()
+	if err != nil {
+		return nil, err
+	}
+	if oidc.UserinfoEndpoint == "" {
+		return nil, errors.New("no userinfo endpoint")
+	}
 
-		m.updateOIDC(oidc)
+	response, err := m.restClient.R().
+		SetAuthToken(accessToken).
+		SetResult(&models.Auth0User{}).
+		Get(oidc.UserinfoEndpoint)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user info from the authentication provider")
+		return nil, err
 	}
 
-	return nil
-}
+	if !response.IsSuccess() {
+		log.Error().Msgf("Failed to get user info from the authentication provider: %v", response.Status())
+		return nil, errors.New("userinfo request failed")
+	}
 
-func (m *AuthManager) updateOIDC(oidc *models.OpenIDConfiguration) {
-	m.oidcMutex.Lock()
-	m.oidc = oidc
-	m.oidcMutex.Unlock()
+	return response.Result().(*models.Auth0User), nil
 }
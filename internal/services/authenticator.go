@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Authentication failures reported by an Authenticator. Each failure has its
+// own error so clients can tell an expired token from a misconfigured one.
+var (
+	ErrKeySetUnavailable     = errors.New("signing keys unavailable")
+	ErrInvalidToken          = errors.New("invalid token")
+	ErrTokenMalformed        = errors.New("token is malformed")
+	ErrTokenAlgorithm        = errors.New("token signing algorithm is not allowed")
+	ErrTokenSignatureInvalid = errors.New("token signature is invalid")
+	ErrTokenExpired          = errors.New("token has expired")
+	ErrTokenNotYetValid      = errors.New("token is not valid yet")
+	ErrTokenIssuer           = errors.New("token issuer is not accepted")
+	ErrTokenAudience         = errors.New("token audience is not accepted")
+)
+
+// Authenticator validates bearer tokens. AuthManager verifies tokens issued by
+// the OIDC provider, DevIssuer tokens signed with a local development key.
+type Authenticator interface {
+	Authenticate(tokenString string) (*models.UserToken, error)
+}
+
+// TokenValidator checks the signature and the registered claims of a token.
+// An empty Issuer or Audiences list skips that check.
+type TokenValidator struct {
+	Issuer     string
+	Audiences  []string
+	Algorithms []string
+	Leeway     time.Duration
+}
+
+// NewTokenValidator builds a validator from the JWT_* settings
+func NewTokenValidator(cfg *config.Config, issuer string) TokenValidator {
+	return TokenValidator{
+		Issuer:     issuer,
+		Audiences:  cfg.JWTAudiences,
+		Algorithms: cfg.JWTAlgorithms,
+		Leeway:     cfg.JWTLeeway,
+	}
+}
+
+// Authenticate verifies a token against the provider's JWKS
+func (m *AuthManager) Authenticate(tokenString string) (*models.UserToken, error) {
+	jwks, err := m.GetJWKS()
+	if err != nil {
+		return nil, ErrKeySetUnavailable
+	}
+
+	// Without JWT_ISSUER the issuer announced by the discovery document is expected
+	validator := m.validator
+	if validator.Issuer == "" {
+		oidc, err := m.getOIDC()
+		if err != nil {
+			return nil, ErrKeySetUnavailable
+		}
+		validator.Issuer = oidc.Issuer
+	}
+
+	return validator.Validate(tokenString, jwks.Keyfunc)
+}
+
+// Validate parses the token and returns its claims if the signature, the
+// algorithm, the validity period, the issuer and the audience are accepted
+func (v TokenValidator) Validate(tokenString string, keyFunc jwt.Keyfunc) (*models.UserToken, error) {
+	userToken := models.UserToken{}
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+	_, err := parser.ParseWithClaims(tokenString, &userToken, func(token *jwt.Token) (interface{}, error) {
+		// Checked before the key is looked up, so a public key can never be
+		// used as HMAC secret
+		if !v.algorithmAllowed(token.Method.Alg()) {
+			return nil, ErrTokenAlgorithm
+		}
+		return keyFunc(token)
+	})
+	if err != nil {
+		return nil, classifyParseError(err)
+	}
+
+	if err := v.validateClaims(&userToken, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &userToken, nil
+}
+
+func (v TokenValidator) algorithmAllowed(algorithm string) bool {
+	for _, allowed := range v.Algorithms {
+		if allowed == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+func (v TokenValidator) validateClaims(userToken *models.UserToken, now time.Time) error {
+	if userToken.ExpiresAt == nil {
+		return ErrTokenMalformed
+	}
+	if now.After(userToken.ExpiresAt.Add(v.Leeway)) {
+		return ErrTokenExpired
+	}
+	if userToken.NotBefore != nil && now.Add(v.Leeway).Before(userToken.NotBefore.Time) {
+		return ErrTokenNotYetValid
+	}
+	if userToken.IssuedAt != nil && now.Add(v.Leeway).Before(userToken.IssuedAt.Time) {
+		return ErrTokenNotYetValid
+	}
+
+	if v.Issuer != "" && userToken.Issuer != v.Issuer {
+		return ErrTokenIssuer
+	}
+
+	if len(v.Audiences) > 0 {
+		for _, audience := range v.Audiences {
+			for _, tokenAudience := range userToken.Audience {
+				if audience == tokenAudience {
+					return nil
+				}
+			}
+		}
+		return ErrTokenAudience
+	}
+
+	return nil
+}
+
+func classifyParseError(err error) error {
+	if errors.Is(err, ErrTokenAlgorithm) {
+		return ErrTokenAlgorithm
+	}
+
+	var validationErr *jwt.ValidationError
+	if !errors.As(err, &validationErr) {
+		return ErrInvalidToken
+	}
+	switch {
+	case validationErr.Errors&jwt.ValidationErrorMalformed != 0:
+		return ErrTokenMalformed
+	case validationErr.Errors&(jwt.ValidationErrorSignatureInvalid|jwt.ValidationErrorUnverifiable) != 0:
+		// Also reached for unknown key IDs, the key set has no key to verify with
+		return ErrTokenSignatureInvalid
+	default:
+		return ErrInvalidToken
+	}
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testIssuer   = "https://auth.example.com/"
+	testAudience = "https://api.darts.example.com"
+)
+
+type testKeys struct {
+	rsa      *rsa.PrivateKey
+	otherRSA *rsa.PrivateKey
+	ecdsa    *ecdsa.PrivateKey
+}
+
+func generateTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+	return testKeys{rsa: rsaKey, otherRSA: otherRSAKey, ecdsa: ecdsaKey}
+}
+
+// validClaims returns claims the default validator accepts
+func validClaims() models.UserToken {
+	now := time.Now()
+	return models.UserToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    testIssuer,
+			Subject:   "auth0|123",
+			Audience:  jwt.ClaimStrings{testAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Email: "jane@darts.example.com",
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.Claims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestTokenValidatorValidate(t *testing.T) {
+	keys := generateTestKeys(t)
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to encode public key: %v", err)
+	}
+	hmacSecret := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	validator := TokenValidator{
+		Issuer:     testIssuer,
+		Audiences:  []string{testAudience},
+		Algorithms: []string{"RS256"},
+		Leeway:     30 * time.Second,
+	}
+
+	// The key set knows the RSA key only. HMAC tokens get the PEM encoded
+	// public key as secret, which is what an algorithm confusion attack uses.
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return hmacSecret, nil
+		case *jwt.SigningMethodECDSA:
+			return &keys.ecdsa.PublicKey, nil
+		default:
+			return &keys.rsa.PublicKey, nil
+		}
+	}
+
+	tests := []struct {
+		name      string
+		validator func(v TokenValidator) TokenValidator
+		token     func() string
+		wantErr   error
+	}{
+		{
+			name:  "valid token",
+			token: func() string { return sign(t, jwt.SigningMethodRS256, keys.rsa, validClaims()) },
+		},
+		{
+			name: "expired token",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "expired within leeway",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+		},
+		{
+			name:      "expired without leeway",
+			validator: func(v TokenValidator) TokenValidator { v.Leeway = 0; return v },
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "missing expiry",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenMalformed,
+		},
+		{
+			name: "not valid yet",
+			token: func() string {
+				claims := validClaims()
+				claims.NotBefore = jwt.NewNumericDate(time.Now().Add(5 * time.Minute))
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenNotYetValid,
+		},
+		{
+			name: "not before within leeway",
+			token: func() string {
+				claims := validClaims()
+				claims.NotBefore = jwt.NewNumericDate(time.Now().Add(10 * time.Second))
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+		},
+		{
+			name: "issued in the future",
+			token: func() string {
+				claims := validClaims()
+				claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(5 * time.Minute))
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenNotYetValid,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "https://evil.example.com/"
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenIssuer,
+		},
+		{
+			name:      "issuer not checked",
+			validator: func(v TokenValidator) TokenValidator { v.Issuer = ""; return v },
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "https://other.example.com/"
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"https://other-api.example.com"}
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenAudience,
+		},
+		{
+			name: "missing audience",
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = nil
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+			wantErr: ErrTokenAudience,
+		},
+		{
+			name: "one of several audiences",
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{testIssuer + "userinfo", testAudience}
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+		},
+		{
+			name: "one of several accepted audiences",
+			validator: func(v TokenValidator) TokenValidator {
+				v.Audiences = []string{"https://legacy", testAudience}
+				return v
+			},
+			token: func() string { return sign(t, jwt.SigningMethodRS256, keys.rsa, validClaims()) },
+		},
+		{
+			name:      "audience not checked",
+			validator: func(v TokenValidator) TokenValidator { v.Audiences = nil; return v },
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = nil
+				return sign(t, jwt.SigningMethodRS256, keys.rsa, claims)
+			},
+		},
+		{
+			name:    "algorithm not allowed",
+			token:   func() string { return sign(t, jwt.SigningMethodRS384, keys.rsa, validClaims()) },
+			wantErr: ErrTokenAlgorithm,
+		},
+		{
+			name:      "additional algorithm allowed",
+			validator: func(v TokenValidator) TokenValidator { v.Algorithms = []string{"RS256", "ES256"}; return v },
+			token:     func() string { return sign(t, jwt.SigningMethodES256, keys.ecdsa, validClaims()) },
+		},
+		{
+			name:    "HMAC signed with the public key",
+			token:   func() string { return sign(t, jwt.SigningMethodHS256, hmacSecret, validClaims()) },
+			wantErr: ErrTokenAlgorithm,
+		},
+		{
+			name: "unsigned token",
+			token: func() string {
+				return sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, validClaims())
+			},
+			wantErr: ErrTokenAlgorithm,
+		},
+		{
+			name:    "signed with another key",
+			token:   func() string { return sign(t, jwt.SigningMethodRS256, keys.otherRSA, validClaims()) },
+			wantErr: ErrTokenSignatureInvalid,
+		},
+		{
+			name: "tampered payload",
+			token: func() string {
+				signed := sign(t, jwt.SigningMethodRS256, keys.rsa, validClaims())
+				claims := validClaims()
+				claims.Subject = "auth0|admin"
+				forged := sign(t, jwt.SigningMethodRS256, keys.otherRSA, claims)
+				return forged[:strings.LastIndex(forged, ".")] + signed[strings.LastIndex(signed, "."):]
+			},
+			wantErr: ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "not a JWT",
+			token:   func() string { return "not-a-token" },
+			wantErr: ErrTokenMalformed,
+		},
+		{
+			name:    "empty token",
+			token:   func() string { return "" },
+			wantErr: ErrTokenMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator
+			if tt.validator != nil {
+				v = tt.validator(v)
+			}
+
+			userToken, err := v.Validate(tt.token(), keyFunc)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && userToken.Subject == "" {
+				t.Fatalf("Validate() returned no subject")
+			}
+		})
+	}
+}
+
+func TestTokenValidatorUnknownKey(t *testing.T) {
+	keys := generateTestKeys(t)
+	validator := TokenValidator{Algorithms: []string{"RS256"}}
+	token := sign(t, jwt.SigningMethodRS256, keys.rsa, validClaims())
+
+	_, err := validator.Validate(token, func(token *jwt.Token) (interface{}, error) {
+		return nil, errors.New("the given key ID was not found in the JWKS")
+	})
+	if !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Fatalf("Validate() error = %v, want %v", err, ErrTokenSignatureInvalid)
+	}
+}
+
+func TestUserTokenAudience(t *testing.T) {
+	keys := generateTestKeys(t)
+	validator := TokenValidator{Audiences: []string{testAudience}, Algorithms: []string{"RS256"}}
+	keyFunc := func(token *jwt.Token) (interface{}, error) { return &keys.rsa.PublicKey, nil }
+
+	tests := []struct {
+		name     string
+		audience interface{}
+		wantErr  error
+	}{
+		{name: "single string", audience: testAudience},
+		{name: "array", audience: []string{"https://other", testAudience}},
+		{name: "other string", audience: "https://other", wantErr: ErrTokenAudience},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := sign(t, jwt.SigningMethodRS256, keys.rsa, jwt.MapClaims{
+				"sub": "auth0|123",
+				"aud": tt.audience,
+				"exp": time.Now().Add(time.Hour).Unix(),
+			})
+
+			userToken, err := validator.Validate(token, keyFunc)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && userToken.Subject != "auth0|123" {
+				t.Fatalf("Validate() subject = %q, want auth0|123", userToken.Subject)
+			}
+		})
+	}
+}
+
+func TestDevIssuerRoundTrip(t *testing.T) {
+	cfg := &config.Config{
+		DevAuthIssuer: "http://localhost:8080/dev/",
+		JWTAudiences:  []string{testAudience},
+		JWTAlgorithms: []string{"RS256"},
+		JWTLeeway:     30 * time.Second,
+	}
+	issuer, err := NewDevIssuer(cfg)
+	if err != nil {
+		t.Fatalf("NewDevIssuer() error = %v", err)
+	}
+
+	response, err := issuer.MintToken(&DevTokenRequest{Subject: "admin"})
+	if err != nil {
+		t.Fatalf("MintToken() error = %v", err)
+	}
+
+	userToken, err := issuer.Authenticate(response.AccessToken)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if userToken.Subject != "dev|admin" || userToken.Issuer != "http://localhost:8080/dev" {
+		t.Fatalf("Authenticate() = %q from %q, want dev|admin from the dev issuer", userToken.Subject, userToken.Issuer)
+	}
+
+	// A token of another dev issuer instance has a different key
+	other, err := NewDevIssuer(cfg)
+	if err != nil {
+		t.Fatalf("NewDevIssuer() error = %v", err)
+	}
+	if _, err := other.Authenticate(response.AccessToken); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Fatalf("Authenticate() error = %v, want %v", err, ErrTokenSignatureInvalid)
+	}
+}
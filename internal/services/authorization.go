@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// rolePermissions maps each role to the permissions it grants
+var rolePermissions = map[string][]string{
+	models.RoleAdmin: {models.PermissionAll},
+	models.RoleTreasurer: {
+		models.PermissionTeamsRead, models.PermissionPlayersRead, models.PermissionSessionsRead,
+		models.PermissionPlayersOwn, models.PermissionGamesScore,
+		models.PermissionLedgerRead, models.PermissionLedgerOwn, models.PermissionLedgerWrite, models.PermissionPricingWrite,
+	},
+	models.RoleCaptain: {
+		models.PermissionTeamsRead, models.PermissionPlayersRead, models.PermissionSessionsRead,
+		models.PermissionPlayersOwn, models.PermissionLedgerOwn, models.PermissionGamesScore,
+		models.PermissionSessionsWrite, models.PermissionPlayersInvite, models.PermissionLineupsWrite,
+	},
+	models.RoleMember: {
+		models.PermissionTeamsRead, models.PermissionPlayersRead, models.PermissionSessionsRead,
+		models.PermissionPlayersOwn, models.PermissionLedgerOwn, models.PermissionGamesScore,
+	},
+	models.RoleViewer: {
+		models.PermissionTeamsRead, models.PermissionPlayersRead, models.PermissionSessionsRead,
+	},
+}
+
+type AuthorizationService struct {
+	db          *gorm.DB
+	defaultRole string
+}
+
+func NewAuthorizationService(db *gorm.DB, cfg *config.Config) *AuthorizationService {
+	defaultRole := strings.ToLower(cfg.DefaultRole)
+	if !IsValidRole(defaultRole) {
+		defaultRole = models.RoleMember
+	}
+	return &AuthorizationService{
+		db:          db,
+		defaultRole: defaultRole,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *AuthorizationService) WithContext(ctx context.Context) *AuthorizationService {
+	copy := *s
+	copy.db = s.db.WithContext(ctx)
+	return &copy
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether the permission is granted, "*" grants everything
+func HasPermission(permissions []string, permission string) bool {
+	for _, granted := range permissions {
+		if granted == permission || granted == models.PermissionAll {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveAccess combines the roles of the token, the local role assignments and
+// the captain flag of the caller's player. Users without any known role get the
+// configured default role. Permissions issued directly in the token (Auth0
+// RBAC) are kept as they are.
+func (s *AuthorizationService) ResolveAccess(token models.UserToken, principal *models.Principal) (*models.AccessResponse, error) {
+	roles := map[string]bool{}
+	for _, role := range append(append([]string{}, token.Roles...), token.RealmAccess.Roles...) {
+		role = strings.ToLower(role)
+		if IsValidRole(role) {
+			roles[role] = true
+		}
+	}
+
+	if token.Subject != "" {
+		var assigned []string
+		if err := s.db.Model(&models.RoleAssignment{}).Where("subject = ?", token.Subject).Pluck("role", &assigned).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch role assignments: %w", err)
+		}
+		for _, role := range assigned {
+			roles[role] = true
+		}
+	}
+
+	// Captains and vice captains of a team get the captain role while their
+	// membership is valid
+	if principal != nil && principal.Player != nil {
+		var captaincies int64
+		if err := s.db.Model(&models.TeamMembership{}).
+			Where("player_id = ? AND role IN ?", principal.Player.ID, []string{models.TeamRoleCaptain, models.TeamRoleViceCaptain}).
+			Scopes(membershipsActiveOn(today())).
+			Count(&captaincies).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch team memberships: %w", err)
+		}
+		if captaincies > 0 {
+			roles[models.RoleCaptain] = true
+		}
+	}
+
+	if len(roles) == 0 {
+		roles[s.defaultRole] = true
+	}
+
+	permissions := map[string]bool{}
+	for _, permission := range token.Permissions {
+		permissions[permission] = true
+	}
+	for role := range roles {
+		for _, permission := range rolePermissions[role] {
+			permissions[permission] = true
+		}
+	}
+
+	return &models.AccessResponse{
+		Subject:     token.Subject,
+		Roles:       sortedKeys(roles),
+		Permissions: sortedKeys(permissions),
+	}, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *AuthorizationService) GetRoleAssignments() ([]models.RoleAssignment, error) {
+	var assignments []models.RoleAssignment
+	if err := s.db.Order("subject, role").Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch role assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+func (s *AuthorizationService) AssignRole(req *models.RoleAssignmentRequest, createdBy *string) (*models.RoleAssignment, error) {
+	role := strings.ToLower(req.Role)
+	if !IsValidRole(role) {
+		return nil, fmt.Errorf("invalid role: %s", req.Role)
+	}
+
+	var count int64
+	if err := s.db.Model(&models.RoleAssignment{}).Where("subject = ? AND role = ?", req.Subject, role).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check role assignment: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("role '%s' is already assigned", role)
+	}
+
+	assignment := &models.RoleAssignment{
+		Subject:   req.Subject,
+		Role:      role,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.Create(assignment).Error; err != nil {
+		return nil, fmt.Errorf("failed to assign role: %w", err)
+	}
+	return assignment, nil
+}
+
+func (s *AuthorizationService) RemoveRoleAssignment(id uuid.UUID) error {
+	result := s.db.Delete(&models.RoleAssignment{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove role assignment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("role assignment not found")
+	}
+	return nil
+}
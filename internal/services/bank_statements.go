@@ -0,0 +1,288 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/models"
+)
+
+// statementEntry is a single credit or debit read from a bank statement
+type statementEntry struct {
+	BankReference    string
+	BookingDate      time.Time
+	Amount           models.Money // negative for debits
+	Counterparty     string
+	CounterpartyIBAN string
+	Reference        string
+}
+
+// parseBankStatement reads a CAMT.053 or CSV statement. An empty format is
+// detected from the content.
+func parseBankStatement(data []byte, format string) ([]statementEntry, error) {
+	if format == "" {
+		format = "csv"
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+			format = "camt053"
+		}
+	}
+
+	switch format {
+	case "camt053":
+		return parseCamt053(data)
+	case "csv":
+		return parseStatementCSV(data)
+	default:
+		return nil, fmt.Errorf("invalid statement format: %s", format)
+	}
+}
+
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtEntry struct {
+	Amount             camtAmount      `xml:"Amt"`
+	CreditDebit        string          `xml:"CdtDbtInd"`
+	BookingDate        string          `xml:"BookgDt>Dt"`
+	BookingDateTime    string          `xml:"BookgDt>DtTm"`
+	AccountServicerRef string          `xml:"AcctSvcrRef"`
+	AdditionalInfo     string          `xml:"AddtlNtryInf"`
+	Details            []camtTxDetails `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTxDetails struct {
+	AccountServicerRef string      `xml:"Refs>AcctSvcrRef"`
+	EndToEndID         string      `xml:"Refs>EndToEndId"`
+	Amount             *camtAmount `xml:"Amt"`
+	TxAmount           *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	CreditDebit        string      `xml:"CdtDbtInd"`
+	DebtorName         string      `xml:"RltdPties>Dbtr>Nm"`
+	DebtorIBAN         string      `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+	CreditorName       string      `xml:"RltdPties>Cdtr>Nm"`
+	CreditorIBAN       string      `xml:"RltdPties>CdtrAcct>Id>IBAN"`
+	Unstructured       []string    `xml:"RmtInf>Ustrd"`
+}
+
+// parseCamt053 reads the booked entries of a CAMT.053 statement. Batch entries
+// (e.g. a direct-debit collection) are split into their transaction details.
+func parseCamt053(data []byte) ([]statementEntry, error) {
+	var document camtDocument
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 statement: %v", err)
+	}
+
+	var entries []statementEntry
+	for _, statement := range document.Statements {
+		for _, ntry := range statement.Entries {
+			bookingDate, err := parseStatementDate(ntry.BookingDate, ntry.BookingDateTime)
+			if err != nil {
+				return nil, fmt.Errorf("invalid camt.053 statement: %v", err)
+			}
+
+			if len(ntry.Details) == 0 {
+				amount, err := camtMoney(ntry.Amount, ntry.CreditDebit)
+				if err != nil {
+					return nil, err
+				}
+				entries = append(entries, statementEntry{
+					BankReference: entryReference(ntry.AccountServicerRef, "", bookingDate, amount, ntry.AdditionalInfo),
+					BookingDate:   bookingDate,
+					Amount:        amount,
+					Reference:     ntry.AdditionalInfo,
+				})
+				continue
+			}
+
+			for i, details := range ntry.Details {
+				creditDebit := details.CreditDebit
+				if creditDebit == "" {
+					creditDebit = ntry.CreditDebit
+				}
+
+				rawAmount := ntry.Amount
+				switch {
+				case details.Amount != nil:
+					rawAmount = *details.Amount
+				case details.TxAmount != nil:
+					rawAmount = *details.TxAmount
+				}
+				amount, err := camtMoney(rawAmount, creditDebit)
+				if err != nil {
+					return nil, err
+				}
+
+				counterparty, counterpartyIBAN := details.DebtorName, details.DebtorIBAN
+				if creditDebit == "DBIT" {
+					counterparty, counterpartyIBAN = details.CreditorName, details.CreditorIBAN
+				}
+
+				reference := strings.Join(details.Unstructured, " ")
+				if details.EndToEndID != "" && details.EndToEndID != "NOTPROVIDED" {
+					reference = strings.TrimSpace(reference + " " + details.EndToEndID)
+				}
+
+				bankReference := details.AccountServicerRef
+				if bankReference == "" && ntry.AccountServicerRef != "" && ntry.AccountServicerRef != "NONREF" {
+					bankReference = fmt.Sprintf("%s/%d", ntry.AccountServicerRef, i+1)
+				}
+
+				entries = append(entries, statementEntry{
+					BankReference:    entryReference(bankReference, counterpartyIBAN, bookingDate, amount, reference),
+					BookingDate:      bookingDate,
+					Amount:           amount,
+					Counterparty:     counterparty,
+					CounterpartyIBAN: counterpartyIBAN,
+					Reference:        reference,
+				})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func camtMoney(amount camtAmount, creditDebit string) (models.Money, error) {
+	money, err := models.ParseMoney(amount.Value, amount.Currency)
+	if err != nil {
+		return models.Money{}, fmt.Errorf("invalid camt.053 statement: %v", err)
+	}
+	if creditDebit == "DBIT" {
+		money = money.Neg()
+	}
+	return money, nil
+}
+
+// statementCSVColumns are the recognised CSV header names
+var statementCSVColumns = []string{"booking_date", "amount", "currency", "counterparty", "iban", "reference", "bank_reference"}
+
+// parseStatementCSV reads a CSV export with a header row. Comma and semicolon
+// delimiters as well as German number and date formats are accepted.
+func parseStatementCSV(data []byte) ([]statementEntry, error) {
+	firstLine := string(data)
+	if i := strings.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv statement: %v", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for _, known := range statementCSVColumns {
+			if name == known {
+				columns[name] = i
+			}
+		}
+	}
+	for _, required := range []string{"booking_date", "amount", "reference"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid csv statement: missing column %s", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []statementEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv statement: %v", err)
+		}
+
+		bookingDate, err := parseStatementDate(field(record, "booking_date"), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv statement: line %d: %v", line, err)
+		}
+
+		currency := strings.ToUpper(field(record, "currency"))
+		amount, err := models.ParseMoney(normalizeStatementAmount(field(record, "amount")), currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv statement: line %d: %v", line, err)
+		}
+
+		reference := field(record, "reference")
+		iban := strings.ReplaceAll(field(record, "iban"), " ", "")
+		entries = append(entries, statementEntry{
+			BankReference:    entryReference(field(record, "bank_reference"), iban, bookingDate, amount, reference),
+			BookingDate:      bookingDate,
+			Amount:           amount,
+			Counterparty:     field(record, "counterparty"),
+			CounterpartyIBAN: iban,
+			Reference:        reference,
+		})
+	}
+
+	return entries, nil
+}
+
+// normalizeStatementAmount strips thousands separators, "1.234,56" and "1,234.56" both become 1234.56
+func normalizeStatementAmount(value string) string {
+	value = strings.ReplaceAll(value, " ", "")
+	comma, dot := strings.LastIndex(value, ","), strings.LastIndex(value, ".")
+	switch {
+	case comma >= 0 && dot >= 0 && comma > dot:
+		value = strings.ReplaceAll(value, ".", "")
+	case comma >= 0 && dot >= 0:
+		value = strings.ReplaceAll(value, ",", "")
+	}
+	return value
+}
+
+func parseStatementDate(date string, dateTime string) (time.Time, error) {
+	if date == "" && dateTime != "" {
+		if parsed, err := time.Parse(time.RFC3339, dateTime); err == nil {
+			return parsed, nil
+		}
+		date = dateTime
+	}
+	for _, layout := range []string{"2006-01-02", "02.01.2006", "2006-01-02T15:04:05"} {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid booking date: %q", date)
+}
+
+// entryReference returns the bank's own reference or, when the statement has
+// none, a stable hash of the entry so re-importing the same file is detected
+func entryReference(bankReference, iban string, bookingDate time.Time, amount models.Money, reference string) string {
+	if bankReference != "" && bankReference != "NONREF" {
+		return bankReference
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{bookingDate.Format("2006-01-02"), amount.String(), iban, reference}, "|")))
+	return "SHA-" + hex.EncodeToString(sum[:12])
+}
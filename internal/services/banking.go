@@ -0,0 +1,676 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/database"
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// paymentReferencePattern finds the reference from models.PaymentReference in transfer texts
+var paymentReferencePattern = regexp.MustCompile(`(?i)DARTS-?([0-9A-F]{8})`)
+
+// endToEndIDPattern finds the end-to-end ID of a SEPA collection item, the
+// payment reference followed by the start of the collection ID
+var endToEndIDPattern = regexp.MustCompile(`(?i)DARTS-[0-9A-F]{8}-[0-9A-F]{8}`)
+
+type BankingService struct {
+	db            *gorm.DB
+	ledgerService *LedgerService
+}
+
+func NewBankingService(db *gorm.DB) *BankingService {
+	return &BankingService{
+		db:            db,
+		ledgerService: NewLedgerService(db),
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *BankingService) WithContext(ctx context.Context) *BankingService {
+	copy := *s
+	copy.db = s.db.WithContext(ctx)
+	copy.ledgerService = s.ledgerService.WithContext(ctx)
+	return &copy
+}
+
+func (s *BankingService) GetMandate(playerID uuid.UUID) (*models.SepaMandate, error) {
+	var mandate models.SepaMandate
+	if err := s.db.First(&mandate, "player_id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("mandate not found")
+		}
+		return nil, fmt.Errorf("failed to fetch mandate: %w", err)
+	}
+	return &mandate, nil
+}
+
+// SaveMandate creates or replaces the direct-debit mandate of a player
+func (s *BankingService) SaveMandate(playerID uuid.UUID, req *models.SepaMandateRequest) (*models.SepaMandate, error) {
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	iban, err := normalizeIBAN(req.IBAN)
+	if err != nil {
+		return nil, err
+	}
+	signedAt, err := time.Parse("2006-01-02", req.SignedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature date")
+	}
+
+	var existing models.SepaMandate
+	err = s.db.Where("mandate_reference = ? AND player_id != ?", req.MandateReference, playerID).First(&existing).Error
+	if err == nil {
+		return nil, fmt.Errorf("mandate reference '%s' is already in use", req.MandateReference)
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check mandate reference: %w", err)
+	}
+
+	mandate, err := s.GetMandate(playerID)
+	if err != nil && err.Error() != "mandate not found" {
+		return nil, err
+	}
+	if mandate == nil {
+		mandate = &models.SepaMandate{PlayerID: playerID}
+	}
+
+	// A new mandate reference starts a new sequence, the next collection is a first one
+	if mandate.MandateReference != req.MandateReference {
+		mandate.FirstCollectionAt = nil
+	}
+	mandate.MandateReference = req.MandateReference
+	mandate.AccountHolder = req.AccountHolder
+	mandate.IBAN = iban
+	mandate.BIC = req.BIC
+	mandate.SignedAt = signedAt
+
+	if err := s.db.Save(mandate).Error; err != nil {
+		return nil, fmt.Errorf("failed to save mandate: %w", err)
+	}
+
+	return mandate, nil
+}
+
+func (s *BankingService) RevokeMandate(playerID uuid.UUID) error {
+	mandate, err := s.GetMandate(playerID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(mandate).Error; err != nil {
+		return fmt.Errorf("failed to revoke mandate: %w", err)
+	}
+	return nil
+}
+
+// ExportOutstandingCSV writes all outstanding balances as a semicolon separated
+// CSV with German decimal commas, ready for spreadsheet use
+func (s *BankingService) ExportOutstandingCSV() ([]byte, error) {
+	debts, err := s.ledgerService.GetOutstandingDebts()
+	if err != nil {
+		return nil, err
+	}
+
+	var mandatePlayerIDs []uuid.UUID
+	if err := s.db.Model(&models.SepaMandate{}).Pluck("player_id", &mandatePlayerIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch mandates: %w", err)
+	}
+	hasMandate := map[uuid.UUID]bool{}
+	for _, id := range mandatePlayerIDs {
+		hasMandate[id] = true
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Comma = ';'
+
+	rows := [][]string{{"player_id", "name", "email", "balance", "currency", "payment_reference", "sepa_mandate"}}
+	for _, debt := range debts.Debts {
+		mandate := "nein"
+		if hasMandate[debt.PlayerID] {
+			mandate = "ja"
+		}
+		rows = append(rows, []string{
+			debt.PlayerID.String(),
+			debt.PlayerName,
+			debt.Email,
+			strings.Replace(debt.Balance.Decimal(), ".", ",", 1),
+			debt.Balance.Currency,
+			models.PaymentReference(debt.PlayerID),
+			mandate,
+		})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write csv: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// ExportSepaDirectDebit creates a pain.008 file collecting the outstanding
+// balance of every member with a mandate and records it as a pending
+// collection. Amounts of earlier collections that are still pending are not
+// debited again. Mandates stay first collections until a debit is collected.
+// The debits are collected to the creditor account of the request's club.
+func (s *BankingService) ExportSepaDirectDebit(req *models.SepaExportRequest, createdBy *string) (*models.SepaCollection, []byte, error) {
+	clubID, ok := database.ClubFromContext(s.db.Statement.Context)
+	if !ok {
+		return nil, nil, fmt.Errorf("sepa creditor is not configured")
+	}
+	creditor, err := loadCreditor(s.db, clubID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !creditor.configured() {
+		return nil, nil, fmt.Errorf("sepa creditor is not configured")
+	}
+
+	now := time.Now()
+	collectionDate := now.AddDate(0, 0, 5)
+	if req != nil && req.CollectionDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.CollectionDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid collection date")
+		}
+		if parsed.Before(now.Truncate(24 * time.Hour)) {
+			return nil, nil, fmt.Errorf("collection date must not be in the past")
+		}
+		collectionDate = parsed
+	}
+
+	debts, err := s.ledgerService.GetOutstandingDebts()
+	if err != nil {
+		return nil, nil, err
+	}
+	pending, err := s.pendingDebits()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mandates []models.SepaMandate
+	if err := s.db.Find(&mandates).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch mandates: %w", err)
+	}
+	mandatesByPlayer := map[uuid.UUID]*models.SepaMandate{}
+	for i := range mandates {
+		mandatesByPlayer[mandates[i].PlayerID] = &mandates[i]
+	}
+
+	collection := &models.SepaCollection{
+		ID:             uuid.New(),
+		MessageID:      fmt.Sprintf("DARTS-%s", now.Format("20060102150405")),
+		CollectionDate: collectionDate,
+		Total:          models.NewMoney(0, "EUR"),
+		Status:         "pending",
+		CreatedBy:      createdBy,
+	}
+	batch := strings.ToUpper(strings.ReplaceAll(collection.ID.String(), "-", "")[:8])
+
+	var debits []sepaDebit
+	for _, debt := range debts.Debts {
+		mandate := mandatesByPlayer[debt.PlayerID]
+		// SEPA only collects euros
+		if mandate == nil || debt.Balance.Currency != "EUR" {
+			continue
+		}
+		amount := debt.Balance.Sub(pending[debt.PlayerID])
+		if !amount.IsPositive() {
+			continue
+		}
+
+		endToEndID := fmt.Sprintf("%s-%s", models.PaymentReference(debt.PlayerID), batch)
+		sequenceType := "RCUR"
+		if mandate.FirstCollectionAt == nil {
+			sequenceType = "FRST"
+		}
+
+		debits = append(debits, sepaDebit{
+			EndToEndID:       endToEndID,
+			Amount:           amount,
+			MandateReference: mandate.MandateReference,
+			MandateSignedAt:  mandate.SignedAt,
+			DebtorName:       mandate.AccountHolder,
+			DebtorIBAN:       mandate.IBAN,
+			DebtorBIC:        mandate.BIC,
+			Remittance:       fmt.Sprintf("Trainingsbeitraege %s %s", debt.PlayerName, endToEndID),
+			SequenceType:     sequenceType,
+		})
+		collection.Items = append(collection.Items, models.SepaCollectionItem{
+			PlayerID:     debt.PlayerID,
+			MandateID:    mandate.ID,
+			EndToEndID:   endToEndID,
+			Amount:       amount,
+			SequenceType: sequenceType,
+		})
+		collection.Total = collection.Total.Add(amount)
+	}
+
+	if len(debits) == 0 {
+		return nil, nil, fmt.Errorf("no outstanding balances with a sepa mandate")
+	}
+
+	document, err := buildPain008(creditor, collection.MessageID, debits, collectionDate, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.db.Create(collection).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to record sepa collection: %w", err)
+	}
+
+	return collection, document, nil
+}
+
+// GetSepaCollections lists the exported collections, newest first
+func (s *BankingService) GetSepaCollections() ([]models.SepaCollection, error) {
+	var collections []models.SepaCollection
+	if err := s.db.Order("created_at DESC").Find(&collections).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sepa collections: %w", err)
+	}
+	return collections, nil
+}
+
+func (s *BankingService) GetSepaCollection(id uuid.UUID) (*models.SepaCollection, error) {
+	var collection models.SepaCollection
+	if err := s.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("end_to_end_id")
+	}).Preload("Items.Player").First(&collection, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("sepa collection not found")
+		}
+		return nil, fmt.Errorf("failed to fetch sepa collection: %w", err)
+	}
+	return &collection, nil
+}
+
+// ConfirmSepaCollection books the debits of a collection the bank executed,
+// for statements that show the collection as one booking. Debits the
+// statement import already matched are not booked again.
+func (s *BankingService) ConfirmSepaCollection(id uuid.UUID, confirmedBy *string) (*models.SepaCollection, error) {
+	collection, err := s.getPendingCollection(id)
+	if err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Lastschrift %s", collection.CollectionDate.Format("02.01.2006"))
+
+	tx := s.db.Begin()
+
+	for i := range collection.Items {
+		item := &collection.Items[i]
+		if item.LedgerEntryID != nil {
+			continue
+		}
+
+		entry := &models.LedgerEntry{
+			PlayerID:    item.PlayerID,
+			Type:        "payment",
+			Amount:      item.Amount.Neg(),
+			Description: description,
+			Reference:   &item.EndToEndID,
+			RecordedBy:  confirmedBy,
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to record payment: %w", err)
+		}
+		if err := collectItem(tx, item, entry.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetSepaCollection(id)
+}
+
+// RejectSepaCollection discards a collection the bank did not execute, its
+// open amounts are collected by the next export again
+func (s *BankingService) RejectSepaCollection(id uuid.UUID) (*models.SepaCollection, error) {
+	collection, err := s.getPendingCollection(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(collection).Update("status", "rejected").Error; err != nil {
+		return nil, fmt.Errorf("failed to update sepa collection: %w", err)
+	}
+
+	return s.GetSepaCollection(id)
+}
+
+func (s *BankingService) getPendingCollection(id uuid.UUID) (*models.SepaCollection, error) {
+	collection, err := s.GetSepaCollection(id)
+	if err != nil {
+		return nil, err
+	}
+	if collection.Status != "pending" {
+		return nil, fmt.Errorf("sepa collection is already %s", collection.Status)
+	}
+	return collection, nil
+}
+
+// pendingDebits sums per player the debits of pending collections that are
+// not collected yet
+func (s *BankingService) pendingDebits() (map[uuid.UUID]models.Money, error) {
+	pending := map[uuid.UUID]models.Money{}
+
+	var collectionIDs []uuid.UUID
+	if err := s.db.Model(&models.SepaCollection{}).Where("status = ?", "pending").Pluck("id", &collectionIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sepa collections: %w", err)
+	}
+	if len(collectionIDs) == 0 {
+		return pending, nil
+	}
+
+	var rows []struct {
+		PlayerID    uuid.UUID
+		AmountCents int64
+	}
+	if err := s.db.Model(&models.SepaCollectionItem{}).
+		Select("player_id, SUM(amount_cents) AS amount_cents").
+		Where("collection_id IN ? AND ledger_entry_id IS NULL", collectionIDs).
+		Group("player_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch pending debits: %w", err)
+	}
+	for _, row := range rows {
+		pending[row.PlayerID] = models.NewMoney(row.AmountCents, "EUR")
+	}
+	return pending, nil
+}
+
+// collectItem links the booked payment to the debit, turns the mandate into a
+// recurring one and confirms the collection once all its debits are collected
+func collectItem(tx *gorm.DB, item *models.SepaCollectionItem, ledgerEntryID uuid.UUID) error {
+	now := time.Now()
+
+	item.LedgerEntryID = &ledgerEntryID
+	if err := tx.Model(item).Update("ledger_entry_id", ledgerEntryID).Error; err != nil {
+		return fmt.Errorf("failed to update sepa collection item: %w", err)
+	}
+
+	if err := tx.Model(&models.SepaMandate{}).
+		Where("id = ? AND first_collection_at IS NULL", item.MandateID).
+		Update("first_collection_at", now).Error; err != nil {
+		return fmt.Errorf("failed to mark mandate as used: %w", err)
+	}
+
+	var open int64
+	if err := tx.Model(&models.SepaCollectionItem{}).
+		Where("collection_id = ? AND ledger_entry_id IS NULL", item.CollectionID).
+		Count(&open).Error; err != nil {
+		return fmt.Errorf("failed to check sepa collection: %w", err)
+	}
+	if open > 0 {
+		return nil
+	}
+
+	if err := tx.Model(&models.SepaCollection{}).
+		Where("id = ? AND status = ?", item.CollectionID, "pending").
+		Updates(map[string]interface{}{"status": "confirmed", "confirmed_at": now}).Error; err != nil {
+		return fmt.Errorf("failed to confirm sepa collection: %w", err)
+	}
+	return nil
+}
+
+// ImportBankStatement reads a CAMT.053 or CSV statement and books every
+// incoming transfer whose reference names a player as a payment. Direct debits
+// of a SEPA collection are collected with it. Transfers without a recognisable
+// reference are kept as unmatched for manual review, transactions seen in an
+// earlier import are skipped.
+func (s *BankingService) ImportBankStatement(data []byte, format string, importedBy *string) (*models.BankImportResponse, error) {
+	entries, err := parseBankStatement(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.BankImportResponse{Transactions: []models.BankTransactionResponse{}}
+
+	tx := s.db.Begin()
+
+	for _, entry := range entries {
+		if !entry.Amount.IsPositive() {
+			response.SkippedDebits++
+			continue
+		}
+
+		var existingCount int64
+		if err := tx.Model(&models.BankTransaction{}).Where("bank_reference = ?", entry.BankReference).Count(&existingCount).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to check bank transaction: %w", err)
+		}
+		if existingCount > 0 {
+			response.Duplicates++
+			continue
+		}
+
+		transaction := &models.BankTransaction{
+			BankReference:    entry.BankReference,
+			BookingDate:      entry.BookingDate,
+			Amount:           entry.Amount,
+			Counterparty:     entry.Counterparty,
+			CounterpartyIBAN: entry.CounterpartyIBAN,
+			Reference:        entry.Reference,
+			Status:           "unmatched",
+			ImportedBy:       importedBy,
+		}
+
+		item, err := s.matchCollectionItem(tx, entry.Reference)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		var player *models.Player
+		if item != nil {
+			player = item.Player
+		} else if player, err = s.matchPlayer(tx, entry.Reference); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		// A debit booked when its collection was confirmed is not booked twice
+		if item != nil && item.LedgerEntryID != nil {
+			transaction.Status = "matched"
+			transaction.PlayerID = &item.PlayerID
+			transaction.LedgerEntryID = item.LedgerEntryID
+		}
+
+		if err := tx.Create(transaction).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to store bank transaction: %w", err)
+		}
+
+		if transaction.Status == "matched" {
+			transaction.Player = player
+			response.Matched++
+		} else if player != nil {
+			if err := s.bookTransaction(tx, transaction, player, importedBy); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if item != nil {
+				if err := collectItem(tx, item, *transaction.LedgerEntryID); err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+			}
+			transaction.Player = player
+			response.Matched++
+		} else {
+			response.Unmatched++
+		}
+
+		response.Imported++
+		response.Transactions = append(response.Transactions, transaction.ToResponse())
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return response, nil
+}
+
+// GetBankTransactions lists imported transactions, optionally filtered by status
+func (s *BankingService) GetBankTransactions(status string) ([]models.BankTransaction, error) {
+	query := s.db.Preload("Player").Order("booking_date DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var transactions []models.BankTransaction
+	if err := query.Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch bank transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// AssignBankTransaction books an unmatched transfer as payment of the given player
+func (s *BankingService) AssignBankTransaction(id uuid.UUID, playerID uuid.UUID, assignedBy *string) (*models.BankTransaction, error) {
+	transaction, err := s.getUnmatchedTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	tx := s.db.Begin()
+
+	if err := s.bookTransaction(tx, transaction, &player, assignedBy); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	transaction.Player = &player
+	return transaction, nil
+}
+
+// IgnoreBankTransaction marks an unmatched transfer that is no player payment (e.g. a sponsor)
+func (s *BankingService) IgnoreBankTransaction(id uuid.UUID) (*models.BankTransaction, error) {
+	transaction, err := s.getUnmatchedTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.Status = "ignored"
+	if err := s.db.Model(transaction).Update("status", "ignored").Error; err != nil {
+		return nil, fmt.Errorf("failed to update bank transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+func (s *BankingService) getUnmatchedTransaction(id uuid.UUID) (*models.BankTransaction, error) {
+	var transaction models.BankTransaction
+	if err := s.db.First(&transaction, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("bank transaction not found")
+		}
+		return nil, fmt.Errorf("failed to fetch bank transaction: %w", err)
+	}
+	if transaction.Status != "unmatched" {
+		return nil, fmt.Errorf("bank transaction is already %s", transaction.Status)
+	}
+	return &transaction, nil
+}
+
+// matchCollectionItem returns the SEPA debit whose end-to-end ID appears in the text
+func (s *BankingService) matchCollectionItem(tx *gorm.DB, reference string) (*models.SepaCollectionItem, error) {
+	endToEndID := endToEndIDPattern.FindString(reference)
+	if endToEndID == "" {
+		return nil, nil
+	}
+
+	var item models.SepaCollectionItem
+	err := tx.Preload("Player").
+		Scopes(database.InClub("collection_id", "sepa_collections")).
+		First(&item, "end_to_end_id = ?", strings.ToUpper(endToEndID)).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to match sepa collection: %w", err)
+	}
+	return &item, nil
+}
+
+// matchPlayer returns the player whose payment reference appears in the text, if exactly one matches
+func (s *BankingService) matchPlayer(tx *gorm.DB, reference string) (*models.Player, error) {
+	match := paymentReferencePattern.FindStringSubmatch(reference)
+	if match == nil {
+		return nil, nil
+	}
+
+	var players []models.Player
+	prefix := strings.ToLower(match[1])
+	if err := tx.Where("CAST(id AS TEXT) LIKE ?", prefix+"%").Limit(2).Find(&players).Error; err != nil {
+		return nil, fmt.Errorf("failed to match payment reference: %w", err)
+	}
+	if len(players) != 1 {
+		return nil, nil
+	}
+	return &players[0], nil
+}
+
+// bookTransaction records the transfer as a payment on the player's ledger
+func (s *BankingService) bookTransaction(tx *gorm.DB, transaction *models.BankTransaction, player *models.Player, recordedBy *string) error {
+	description := "Überweisung"
+	if transaction.Counterparty != "" {
+		description = fmt.Sprintf("Überweisung %s", transaction.Counterparty)
+	}
+
+	entry := &models.LedgerEntry{
+		PlayerID:    player.ID,
+		Type:        "payment",
+		Amount:      transaction.Amount.Neg(),
+		Description: description,
+		Reference:   &transaction.BankReference,
+		RecordedBy:  recordedBy,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record payment: %w", err)
+	}
+
+	transaction.Status = "matched"
+	transaction.PlayerID = &player.ID
+	transaction.LedgerEntryID = &entry.ID
+	err := tx.Model(transaction).Updates(map[string]interface{}{
+		"status":          transaction.Status,
+		"player_id":       transaction.PlayerID,
+		"ledger_entry_id": transaction.LedgerEntryID,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update bank transaction: %w", err)
+	}
+
+	return nil
+}
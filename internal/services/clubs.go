@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/database"
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// clubSlugPattern keeps slugs usable in headers, URLs and token claims
+var clubSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*[a-z0-9]$`)
+
+type ClubService struct {
+	db *gorm.DB
+}
+
+func NewClubService(db *gorm.DB) *ClubService {
+	return &ClubService{
+		db: db,
+	}
+}
+
+func (s *ClubService) GetClubs() ([]models.Club, error) {
+	var clubs []models.Club
+	if err := s.db.Order("name").Find(&clubs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch clubs: %w", err)
+	}
+	return clubs, nil
+}
+
+// GetClub finds a club by its slug or ID
+func (s *ClubService) GetClub(ref string) (*models.Club, error) {
+	var club models.Club
+	query := s.db.Where("slug = ?", strings.ToLower(ref))
+	if id, err := uuid.Parse(ref); err == nil {
+		query = s.db.Where("id = ?", id)
+	}
+	if err := query.First(&club).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("club not found")
+		}
+		return nil, fmt.Errorf("failed to fetch club: %w", err)
+	}
+	return &club, nil
+}
+
+// CreateClub creates a club together with its default game modes
+func (s *ClubService) CreateClub(req *models.ClubCreateRequest) (*models.Club, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !clubSlugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("invalid club slug: use lowercase letters, digits and dashes")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Club{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check club slug: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("club with slug '%s' already exists", slug)
+	}
+
+	club := &models.Club{
+		Name: strings.TrimSpace(req.Name),
+		Slug: slug,
+	}
+
+	tx := s.db.Begin()
+	if err := tx.Create(club).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create club: %w", err)
+	}
+	if err := database.SeedGameModes(tx, club.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return club, nil
+}
+
+// UpdateCreditor sets the account the club collects direct debits to and
+// names on its invoices
+func (s *ClubService) UpdateCreditor(clubID uuid.UUID, req *models.ClubCreditorRequest) (*models.Club, error) {
+	iban, err := normalizeIBAN(req.IBAN)
+	if err != nil {
+		return nil, err
+	}
+
+	var club models.Club
+	if err := s.db.First(&club, "id = ?", clubID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("club not found")
+		}
+		return nil, fmt.Errorf("failed to fetch club: %w", err)
+	}
+
+	club.CreditorName = strings.TrimSpace(req.Name)
+	club.CreditorIBAN = iban
+	club.CreditorBIC = strings.ToUpper(strings.TrimSpace(req.BIC))
+	club.CreditorID = strings.ToUpper(strings.TrimSpace(req.CreditorID))
+	if err := s.db.Model(&club).Select("creditor_name", "creditor_iban", "creditor_bic", "creditor_id").Updates(&club).Error; err != nil {
+		return nil, fmt.Errorf("failed to update club: %w", err)
+	}
+
+	return &club, nil
+}
+
+// ApplyDefaultCreditor takes the SEPA_CREDITOR_* settings over into the
+// default club if it has no creditor yet, so deployments that predate clubs
+// keep collecting to their account
+func (s *ClubService) ApplyDefaultCreditor(cfg *config.Config) error {
+	if cfg.SepaCreditorName == "" || cfg.SepaCreditorIBAN == "" || cfg.SepaCreditorID == "" {
+		return nil
+	}
+
+	club, err := s.GetClub(models.DefaultClubSlug)
+	if err != nil {
+		return err
+	}
+	if club.CreditorIBAN != "" {
+		return nil
+	}
+
+	_, err = s.UpdateCreditor(club.ID, &models.ClubCreditorRequest{
+		Name:       cfg.SepaCreditorName,
+		IBAN:       cfg.SepaCreditorIBAN,
+		BIC:        cfg.SepaCreditorBIC,
+		CreditorID: cfg.SepaCreditorID,
+	})
+	return err
+}
+
+// ResolveClub returns the club a request acts for. API keys belong to one
+// club. Users may act for the clubs in their token's clubs claim, or for the
+// default club if the claim is missing, and choose one with the X-Club header
+// when there are several.
+func (s *ClubService) ResolveClub(token models.UserToken, requested string, apiKey *models.APIKey) (*models.Club, error) {
+	requested = strings.TrimSpace(requested)
+
+	if apiKey != nil {
+		club, err := s.GetClub(apiKey.ClubID.String())
+		if err != nil {
+			return nil, err
+		}
+		if requested != "" && !clubMatches(club, requested) {
+			return nil, fmt.Errorf("club not permitted")
+		}
+		return club, nil
+	}
+
+	allowed := token.Clubs
+	if len(allowed) == 0 {
+		allowed = []string{models.DefaultClubSlug}
+	}
+	if requested == "" {
+		if len(allowed) > 1 {
+			return nil, fmt.Errorf("club selection required")
+		}
+		requested = allowed[0]
+	}
+
+	club, err := s.GetClub(requested)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range allowed {
+		if clubMatches(club, ref) {
+			return club, nil
+		}
+	}
+	return nil, fmt.Errorf("club not permitted")
+}
+
+func clubMatches(club *models.Club, ref string) bool {
+	return strings.EqualFold(club.Slug, ref) || club.ID.String() == strings.ToLower(ref)
+}
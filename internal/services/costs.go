@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CostService struct {
+	db *gorm.DB
+}
+
+func NewCostService(db *gorm.DB) *CostService {
+	return &CostService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *CostService) WithContext(ctx context.Context) *CostService {
+	return &CostService{db: s.db.WithContext(ctx)}
+}
+
+// CalculateTrainingCosts evaluates the pricing policy of a training session for
+// every attending player and splits the session expenses on top. Without a
+// policy the flat CostPerPlayer is charged to everybody who played at least one game.
+func (s *CostService) CalculateTrainingCosts(trainingID uuid.UUID) (*models.TrainingCostsResponse, error) {
+	var session models.TrainingSession
+	err := s.db.Preload("TrainingPlayers.Player").
+		Preload("Games").
+		Preload("PricingPolicy").
+		First(&session, "id = ?", trainingID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("training session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch training session: %w", err)
+	}
+
+	response := &models.TrainingCostsResponse{
+		TrainingSessionID: trainingID,
+		PlayerCosts:       []models.PlayerCost{},
+		Expenses:          []models.ExpenseAllocation{},
+		TotalExpenses:     models.NewMoney(0, session.CostPerPlayer.Currency),
+		TotalCollected:    models.NewMoney(0, session.CostPerPlayer.Currency),
+	}
+	if session.PricingPolicy != nil {
+		response.PricingPolicy = &session.PricingPolicy.Name
+		response.TotalExpenses = models.NewMoney(0, session.PricingPolicy.MemberRate.Currency)
+		response.TotalCollected = models.NewMoney(0, session.PricingPolicy.MemberRate.Currency)
+	}
+
+	// Nothing is owed for a cancelled training
+	if session.Status == "cancelled" {
+		return response, nil
+	}
+
+	for _, tp := range session.TrainingPlayers {
+		if !tp.Attended {
+			continue // Skip non-attending players
+		}
+
+		gamesPlayed := countGamesPlayed(session.Games, &tp)
+
+		cost, adjustments, err := s.playerCost(&session, &tp, gamesPlayed)
+		if err != nil {
+			return nil, err
+		}
+
+		playerCost := models.PlayerCost{
+			Fee:          cost,
+			ExpenseShare: models.NewMoney(0, cost.Currency),
+			TotalCost:    cost,
+			GamesPlayed:  gamesPlayed,
+			IsGuest:      tp.IsGuest,
+			Adjustments:  adjustments,
+		}
+
+		if tp.IsGuest {
+			playerCost.GuestID = tp.GuestID
+			playerCost.GuestName = tp.GuestName
+		} else {
+			playerCost.PlayerID = *tp.PlayerID
+			if tp.Player != nil {
+				playerName := tp.Player.Name
+				playerCost.PlayerName = &playerName
+			}
+		}
+
+		response.PlayerCosts = append(response.PlayerCosts, playerCost)
+	}
+
+	var expenses []models.SessionExpense
+	if err := s.db.Preload("Payer").Where("training_session_id = ?", trainingID).Order("created_at").Find(&expenses).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch session expenses: %w", err)
+	}
+
+	for i := range expenses {
+		allocation := allocateExpense(&expenses[i], response.PlayerCosts)
+		response.Expenses = append(response.Expenses, allocation)
+		response.TotalExpenses = response.TotalExpenses.Add(expenses[i].Amount)
+	}
+
+	for _, playerCost := range response.PlayerCosts {
+		response.TotalCollected = response.TotalCollected.Add(playerCost.TotalCost)
+	}
+
+	return response, nil
+}
+
+// allocateExpense splits an expense across the attending players according to
+// its split mode and adds each share to the player's costs. An expense
+// nobody can share, e.g. members-only without attending members, is carried by
+// the club.
+func allocateExpense(expense *models.SessionExpense, playerCosts []models.PlayerCost) models.ExpenseAllocation {
+	allocation := models.ExpenseAllocation{
+		ExpenseID:   expense.ID,
+		Description: expense.Description,
+		Amount:      expense.Amount,
+		PaidBy:      expense.PaidBy,
+		SplitMode:   expense.SplitMode,
+		Shares:      []models.ExpenseShare{},
+	}
+	if expense.Payer != nil {
+		allocation.PayerName = &expense.Payer.Name
+	}
+
+	weights := make([]int64, len(playerCosts))
+	members := 0
+	for i, playerCost := range playerCosts {
+		switch expense.SplitMode {
+		case "games_played":
+			weights[i] = int64(playerCost.GamesPlayed)
+		case "members_only":
+			if !playerCost.IsGuest {
+				weights[i] = 1
+				members++
+			}
+		default:
+			weights[i] = 1
+		}
+	}
+
+	// Allocate falls back to an equal split, which would charge the guests
+	if expense.SplitMode == "members_only" && members == 0 {
+		allocation.ClubPays = true
+		return allocation
+	}
+
+	for i, share := range expense.Amount.Allocate(weights) {
+		if share.IsZero() {
+			continue
+		}
+
+		playerCosts[i].ExpenseShare = playerCosts[i].ExpenseShare.Add(share)
+		playerCosts[i].TotalCost = playerCosts[i].TotalCost.Add(share)
+
+		expenseShare := models.ExpenseShare{Amount: share}
+		if playerCosts[i].IsGuest {
+			expenseShare.GuestID = playerCosts[i].GuestID
+			expenseShare.GuestName = playerCosts[i].GuestName
+		} else {
+			playerID := playerCosts[i].PlayerID
+			expenseShare.PlayerID = &playerID
+		}
+		allocation.Shares = append(allocation.Shares, expenseShare)
+	}
+
+	// Without attending players nobody shares the expense either
+	allocation.ClubPays = len(allocation.Shares) == 0 && expense.Amount.IsPositive()
+
+	return allocation
+}
+
+func (s *CostService) playerCost(session *models.TrainingSession, tp *models.TrainingPlayer, gamesPlayed int) (models.Money, []string, error) {
+	policy := session.PricingPolicy
+	if policy == nil {
+		if gamesPlayed == 0 {
+			return models.NewMoney(0, session.CostPerPlayer.Currency), nil, nil
+		}
+		return session.CostPerPlayer, nil, nil
+	}
+
+	if policy.RequireGamePlayed && gamesPlayed == 0 {
+		return models.NewMoney(0, policy.MemberRate.Currency), []string{"no games played"}, nil
+	}
+
+	rate := policy.MemberRate
+	if tp.IsGuest {
+		rate = policy.GuestRate
+	}
+
+	cost := rate
+	if policy.Mode == "per_game" {
+		cost = rate.Mul(int64(gamesPlayed))
+	}
+
+	var adjustments []string
+
+	if tp.IsGuest {
+		if policy.FreeFirstGuestSession {
+			firstVisit, err := s.isFirstGuestVisit(session, tp)
+			if err != nil {
+				return models.Money{}, nil, err
+			}
+			if firstVisit {
+				cost = models.NewMoney(0, cost.Currency)
+				adjustments = append(adjustments, "free first guest session")
+			}
+		}
+		return cost, adjustments, nil
+	}
+
+	if tp.Player != nil {
+		switch tp.Player.Category {
+		case "youth":
+			if policy.YouthDiscountPercent > 0 {
+				cost = cost.Sub(cost.Percent(policy.YouthDiscountPercent))
+				adjustments = append(adjustments, fmt.Sprintf("youth discount %.0f%%", policy.YouthDiscountPercent))
+			}
+		case "student":
+			if policy.StudentDiscountPercent > 0 {
+				cost = cost.Sub(cost.Percent(policy.StudentDiscountPercent))
+				adjustments = append(adjustments, fmt.Sprintf("student discount %.0f%%", policy.StudentDiscountPercent))
+			}
+		}
+	}
+
+	if policy.MonthlyCap != nil && tp.PlayerID != nil {
+		charged, err := s.chargedInMonth(*tp.PlayerID, session)
+		if err != nil {
+			return models.Money{}, nil, err
+		}
+		remaining := policy.MonthlyCap.Sub(charged)
+		if remaining.IsNegative() {
+			remaining = models.NewMoney(0, remaining.Currency)
+		}
+		if cost.Cents > remaining.Cents {
+			cost = remaining
+			adjustments = append(adjustments, "monthly cap reached")
+		}
+	}
+
+	return cost, adjustments, nil
+}
+
+// isFirstGuestVisit reports whether the guest has not attended any earlier completed training
+func (s *CostService) isFirstGuestVisit(session *models.TrainingSession, tp *models.TrainingPlayer) (bool, error) {
+	query := s.db.Model(&models.TrainingPlayer{}).
+		Joins("JOIN training_sessions ON training_sessions.id = training_players.training_session_id")
+	switch {
+	case tp.GuestID != nil:
+		query = query.Where("training_players.guest_id = ?", *tp.GuestID)
+	case tp.GuestName != nil:
+		query = query.Where("training_players.is_guest = ? AND training_players.guest_name = ?", true, *tp.GuestName)
+	default:
+		return true, nil
+	}
+
+	var visits int64
+	err := query.
+		Where("training_players.attended = ?", true).
+		Where("training_sessions.id != ? AND training_sessions.status = ? AND training_sessions.training_date < ?", session.ID, "completed", session.TrainingDate).
+		Where("training_sessions.deleted_at IS NULL").
+		Count(&visits).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to count guest visits: %w", err)
+	}
+
+	return visits == 0, nil
+}
+
+// chargedInMonth sums the training charges of other sessions in the same calendar month
+func (s *CostService) chargedInMonth(playerID uuid.UUID, session *models.TrainingSession) (models.Money, error) {
+	monthStart := time.Date(session.TrainingDate.Year(), session.TrainingDate.Month(), 1, 0, 0, 0, 0, session.TrainingDate.Location())
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	var charged int64
+	err := s.db.Model(&models.LedgerEntry{}).
+		Select("COALESCE(SUM(ledger_entries.amount_cents), 0)").
+		Joins("JOIN training_sessions ON training_sessions.id = ledger_entries.training_session_id").
+		Where("ledger_entries.player_id = ? AND ledger_entries.type = ?", playerID, "charge").
+		Where("training_sessions.id != ? AND training_sessions.training_date >= ? AND training_sessions.training_date < ?", session.ID, monthStart, monthEnd).
+		Scan(&charged).Error
+	if err != nil {
+		return models.Money{}, fmt.Errorf("failed to sum monthly charges: %w", err)
+	}
+
+	return models.NewMoney(charged, session.PricingPolicy.MonthlyCap.Currency), nil
+}
+
+// countGamesPlayed counts the started or completed games the training player took part in
+func countGamesPlayed(games []models.TrainingGame, tp *models.TrainingPlayer) int {
+	gamesPlayed := 0
+	for _, game := range games {
+		if game.Status != "completed" && game.Status != "playing" {
+			continue
+		}
+		if tp.Matches(game.Player1ID, game.Guest1ID, game.Guest1Name) || tp.Matches(game.Player2ID, game.Guest2ID, game.Guest2Name) {
+			gamesPlayed++
+		}
+	}
+	return gamesPlayed
+}
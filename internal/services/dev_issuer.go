@@ -0,0 +1,196 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// devTokenLifetime is the validity of tokens minted by the dev issuer
+const devTokenLifetime = 12 * time.Hour
+
+// DevUser is a seeded account the dev issuer can mint tokens for
+type DevUser struct {
+	Subject     string   `json:"subject"`
+	Email       string   `json:"email"`
+	Name        string   `json:"name"`
+	Roles       []string `json:"roles"`
+	Clubs       []string `json:"clubs,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// devUsers has one account per role so every permission level can be tried
+var devUsers = []DevUser{
+	{Subject: "dev|admin", Email: "admin@darts.local", Name: "Dev Admin", Roles: []string{models.RoleAdmin}, Permissions: []string{models.PermissionClubsManage}},
+	{Subject: "dev|treasurer", Email: "treasurer@darts.local", Name: "Dev Kassenwart", Roles: []string{models.RoleTreasurer}},
+	{Subject: "dev|captain", Email: "captain@darts.local", Name: "Dev Kapitän", Roles: []string{models.RoleCaptain}},
+	{Subject: "dev|member", Email: "member@darts.local", Name: "Dev Mitglied", Roles: []string{models.RoleMember}},
+	{Subject: "dev|viewer", Email: "viewer@darts.local", Name: "Dev Gast", Roles: []string{models.RoleViewer}},
+}
+
+// DevTokenRequest selects a seeded user by subject or describes a custom one
+type DevTokenRequest struct {
+	Subject     string   `json:"subject" binding:"required"`
+	Email       string   `json:"email"`
+	Name        string   `json:"name"`
+	Roles       []string `json:"roles"`
+	Clubs       []string `json:"clubs"`       // slugs of the clubs the token may act for, the default club if empty
+	Permissions []string `json:"permissions"` // extra permissions claim, e.g. clubs:manage
+}
+
+// DevIssuer is an embedded token issuer for local development and tests. It
+// signs tokens with a local RSA key and needs no connection to an identity
+// provider. It must never be enabled in production.
+type DevIssuer struct {
+	issuer     string
+	audiences  []string
+	keyID      string
+	privateKey *rsa.PrivateKey
+	validator  TokenValidator
+}
+
+// NewDevIssuer loads the signing key from DEV_AUTH_KEY_FILE or generates a new
+// one, in which case tokens become invalid when the server restarts
+func NewDevIssuer(cfg *config.Config) (*DevIssuer, error) {
+	var privateKey *rsa.PrivateKey
+	if cfg.DevAuthKeyFile != "" {
+		data, err := os.ReadFile(cfg.DevAuthKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dev auth key: %w", err)
+		}
+		privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dev auth key: %w", err)
+		}
+	} else {
+		generated, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate dev auth key: %w", err)
+		}
+		privateKey = generated
+		log.Warn().Msg("Dev auth uses a generated key, tokens are lost on restart. Set DEV_AUTH_KEY_FILE to keep them.")
+	}
+
+	// The key ID is derived from the public key so it is stable for a key file
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dev auth key: %w", err)
+	}
+	sum := sha256.Sum256(publicKey)
+
+	issuer := strings.TrimRight(cfg.DevAuthIssuer, "/")
+	validator := NewTokenValidator(cfg, issuer)
+	// The dev issuer only signs with RS256, whatever JWT_ALGORITHMS allows
+	validator.Algorithms = []string{jwt.SigningMethodRS256.Alg()}
+
+	return &DevIssuer{
+		issuer:     issuer,
+		audiences:  cfg.JWTAudiences,
+		keyID:      base64.RawURLEncoding.EncodeToString(sum[:8]),
+		privateKey: privateKey,
+		validator:  validator,
+	}, nil
+}
+
+// Authenticate verifies a token signed by this issuer
+func (d *DevIssuer) Authenticate(tokenString string) (*models.UserToken, error) {
+	return d.validator.Validate(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return &d.privateKey.PublicKey, nil
+	})
+}
+
+// Users returns the seeded development accounts
+func (d *DevIssuer) Users() []DevUser {
+	return devUsers
+}
+
+// MintToken signs a token for a seeded user, or for a custom user when the
+// subject is unknown
+func (d *DevIssuer) MintToken(req *DevTokenRequest) (*models.TokenEndpointResponse, error) {
+	user := DevUser{Subject: req.Subject, Email: req.Email, Name: req.Name, Roles: req.Roles, Permissions: req.Permissions}
+	for _, seeded := range devUsers {
+		if seeded.Subject == req.Subject || strings.TrimPrefix(seeded.Subject, "dev|") == req.Subject {
+			user = seeded
+		}
+	}
+	if len(req.Clubs) > 0 {
+		user.Clubs = req.Clubs
+	}
+	for _, role := range user.Roles {
+		if !IsValidRole(role) {
+			return nil, fmt.Errorf("invalid role: %s", role)
+		}
+	}
+
+	now := time.Now()
+	verified := user.Email != ""
+	claims := models.UserToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    d.issuer,
+			Subject:   user.Subject,
+			Audience:  d.audiences,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(devTokenLifetime)),
+		},
+		Roles:         user.Roles,
+		Email:         user.Email,
+		EmailVerified: &verified,
+		Name:          user.Name,
+		Clubs:         user.Clubs,
+		Permissions:   user.Permissions,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = d.keyID
+	signed, err := token.SignedString(d.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return &models.TokenEndpointResponse{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(devTokenLifetime.Seconds()),
+	}, nil
+}
+
+// JWKS returns the public signing key as a JSON Web Key Set
+func (d *DevIssuer) JWKS() map[string]interface{} {
+	publicKey := d.privateKey.PublicKey
+	return map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": d.keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}},
+	}
+}
+
+// OpenIDConfiguration returns the discovery document of the dev issuer
+func (d *DevIssuer) OpenIDConfiguration() *models.OpenIDConfiguration {
+	return &models.OpenIDConfiguration{
+		Issuer:                           d.issuer,
+		TokenEndpoint:                    d.issuer + "/token",
+		JwksURI:                          d.issuer + "/.well-known/jwks.json",
+		IDTokenSigningAlgValuesSupported: []string{"RS256"},
+		SubjectTypesSupported:            []string{"public"},
+		ResponseTypesSupported:           []string{"token"},
+	}
+}
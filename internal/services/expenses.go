@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var validSplitModes = []string{"equal", "games_played", "members_only"}
+
+type ExpenseService struct {
+	db *gorm.DB
+}
+
+func NewExpenseService(db *gorm.DB) *ExpenseService {
+	return &ExpenseService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *ExpenseService) WithContext(ctx context.Context) *ExpenseService {
+	return &ExpenseService{db: s.db.WithContext(ctx)}
+}
+
+func (s *ExpenseService) GetExpensesByTrainingSession(trainingID uuid.UUID) ([]models.SessionExpense, error) {
+	if _, err := s.getSession(trainingID); err != nil {
+		return nil, err
+	}
+
+	var expenses []models.SessionExpense
+	err := s.db.Preload("Payer").
+		Where("training_session_id = ?", trainingID).
+		Order("created_at").
+		Find(&expenses).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch session expenses: %w", err)
+	}
+	return expenses, nil
+}
+
+func (s *ExpenseService) CreateExpense(trainingID uuid.UUID, req *models.SessionExpenseCreateRequest, createdBy *string) (*models.SessionExpense, error) {
+	session, err := s.getSession(trainingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpensesEditable(session); err != nil {
+		return nil, err
+	}
+
+	expense := &models.SessionExpense{
+		TrainingSessionID: trainingID,
+		Description:       req.Description,
+		Amount:            models.NewMoney(req.Amount.Cents, req.Amount.Currency),
+		SplitMode:         "equal",
+		CreatedBy:         createdBy,
+	}
+
+	if !expense.Amount.IsPositive() {
+		return nil, fmt.Errorf("expense amount must be positive")
+	}
+	if req.SplitMode != nil {
+		if !isValidSplitMode(*req.SplitMode) {
+			return nil, fmt.Errorf("invalid split mode: %s", *req.SplitMode)
+		}
+		expense.SplitMode = *req.SplitMode
+	}
+	if req.PaidBy != nil && *req.PaidBy != "" {
+		payerID, err := s.resolvePayer(*req.PaidBy)
+		if err != nil {
+			return nil, err
+		}
+		expense.PaidBy = payerID
+	}
+
+	if err := s.db.Create(expense).Error; err != nil {
+		return nil, fmt.Errorf("failed to create session expense: %w", err)
+	}
+
+	return s.getExpense(trainingID, expense.ID)
+}
+
+func (s *ExpenseService) UpdateExpense(trainingID, expenseID uuid.UUID, req *models.SessionExpenseUpdateRequest) (*models.SessionExpense, error) {
+	session, err := s.getSession(trainingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpensesEditable(session); err != nil {
+		return nil, err
+	}
+
+	expense, err := s.getExpense(trainingID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		expense.Description = *req.Description
+	}
+	if req.Amount != nil {
+		if !req.Amount.IsPositive() {
+			return nil, fmt.Errorf("expense amount must be positive")
+		}
+		expense.Amount = *req.Amount
+	}
+	if req.SplitMode != nil {
+		if !isValidSplitMode(*req.SplitMode) {
+			return nil, fmt.Errorf("invalid split mode: %s", *req.SplitMode)
+		}
+		expense.SplitMode = *req.SplitMode
+	}
+	if req.PaidBy != nil {
+		if *req.PaidBy == "" {
+			expense.PaidBy = nil
+		} else {
+			payerID, err := s.resolvePayer(*req.PaidBy)
+			if err != nil {
+				return nil, err
+			}
+			expense.PaidBy = payerID
+		}
+	}
+
+	// Drop the loaded payer so Save does not write the old association back
+	expense.Payer = nil
+	if err := s.db.Save(expense).Error; err != nil {
+		return nil, fmt.Errorf("failed to update session expense: %w", err)
+	}
+
+	return s.getExpense(trainingID, expenseID)
+}
+
+func (s *ExpenseService) DeleteExpense(trainingID, expenseID uuid.UUID) error {
+	session, err := s.getSession(trainingID)
+	if err != nil {
+		return err
+	}
+	if err := checkExpensesEditable(session); err != nil {
+		return err
+	}
+
+	expense, err := s.getExpense(trainingID, expenseID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(expense).Error; err != nil {
+		return fmt.Errorf("failed to delete session expense: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ExpenseService) getSession(trainingID uuid.UUID) (*models.TrainingSession, error) {
+	var session models.TrainingSession
+	if err := s.db.First(&session, "id = ?", trainingID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("training session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch training session: %w", err)
+	}
+	return &session, nil
+}
+
+func (s *ExpenseService) getExpense(trainingID, expenseID uuid.UUID) (*models.SessionExpense, error) {
+	var expense models.SessionExpense
+	err := s.db.Preload("Payer").
+		First(&expense, "id = ? AND training_session_id = ?", expenseID, trainingID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("session expense not found")
+		}
+		return nil, fmt.Errorf("failed to fetch session expense: %w", err)
+	}
+	return &expense, nil
+}
+
+func (s *ExpenseService) resolvePayer(value string) (*uuid.UUID, error) {
+	payerID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payer ID")
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", payerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payer not found")
+		}
+		return nil, fmt.Errorf("failed to fetch payer: %w", err)
+	}
+
+	return &payerID, nil
+}
+
+// checkExpensesEditable rejects changes once the costs have been booked; reopen the training to correct them
+func checkExpensesEditable(session *models.TrainingSession) error {
+	if session.Status == "completed" || session.Status == "cancelled" {
+		return fmt.Errorf("cannot change expenses of a %s training session", session.Status)
+	}
+	return nil
+}
+
+func isValidSplitMode(mode string) bool {
+	for _, valid := range validSplitModes {
+		if mode == valid {
+			return true
+		}
+	}
+	return false
+}
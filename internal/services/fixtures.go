@@ -0,0 +1,483 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FixtureService manages league fixtures of the club's teams. The games of a
+// fixture are training games without a session, so results are recorded and
+// counted like in training.
+type FixtureService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewFixtureService(db *gorm.DB, cfg *config.Config) *FixtureService {
+	return &FixtureService{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *FixtureService) WithContext(ctx context.Context) *FixtureService {
+	return &FixtureService{db: s.db.WithContext(ctx), cfg: s.cfg}
+}
+
+// GetFixtures returns the fixtures in date order, optionally of one team or season
+func (s *FixtureService) GetFixtures(teamID, seasonID *uuid.UUID) ([]models.Fixture, error) {
+	query := s.db.Preload("Team").Preload("Season").Preload("GameMode")
+	if teamID != nil {
+		query = query.Where("team_id = ?", *teamID)
+	}
+	if seasonID != nil {
+		query = query.Where("season_id = ?", *seasonID)
+	}
+
+	var fixtures []models.Fixture
+	if err := query.Order("scheduled_at").Find(&fixtures).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch fixtures: %w", err)
+	}
+	return fixtures, nil
+}
+
+// GetFixtureByID returns the fixture with its games in match order
+func (s *FixtureService) GetFixtureByID(id uuid.UUID) (*models.Fixture, error) {
+	var fixture models.Fixture
+	err := s.db.Preload("Team").Preload("Season").Preload("GameMode").
+		Preload("Games", func(db *gorm.DB) *gorm.DB {
+			return db.Order("game_number")
+		}).
+		Preload("Games.GameMode").
+		Preload("Games.Player1").
+		Preload("Games.Partner1").
+		First(&fixture, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("fixture not found")
+		}
+		return nil, fmt.Errorf("failed to fetch fixture: %w", err)
+	}
+	return &fixture, nil
+}
+
+func (s *FixtureService) CreateFixture(req *models.FixtureCreateRequest) (*models.Fixture, error) {
+	teamID, err := uuid.Parse(req.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid team ID format")
+	}
+	if _, err := NewTeamService(s.db).findTeam(teamID); err != nil {
+		return nil, err
+	}
+
+	gameModeID, err := s.findGameMode(req.GameModeID)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &models.Fixture{
+		TeamID:      teamID,
+		Opponent:    req.Opponent,
+		IsHome:      true,
+		Location:    req.Location,
+		ScheduledAt: req.ScheduledAt,
+		GameModeID:  gameModeID,
+		Singles:     s.cfg.FixtureSingles,
+		Doubles:     s.cfg.FixtureDoubles,
+		Status:      models.FixtureStatusScheduled,
+	}
+	if req.IsHome != nil {
+		fixture.IsHome = *req.IsHome
+	}
+	if req.Singles != nil {
+		fixture.Singles = *req.Singles
+	}
+	if req.Doubles != nil {
+		fixture.Doubles = *req.Doubles
+	}
+	if fixture.GameCount() == 0 {
+		return nil, fmt.Errorf("match format needs at least one game")
+	}
+
+	if fixture.SeasonID, err = s.fixtureSeason(req.SeasonID, fixture.ScheduledAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(fixture).Error; err != nil {
+		return nil, fmt.Errorf("failed to create fixture: %w", err)
+	}
+
+	return s.GetFixtureByID(fixture.ID)
+}
+
+func (s *FixtureService) UpdateFixture(id uuid.UUID, req *models.FixtureUpdateRequest) (*models.Fixture, error) {
+	fixture, err := s.findFixture(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var games int64
+	if err := s.db.Model(&models.TrainingGame{}).Where("fixture_id = ?", fixture.ID).Count(&games).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch fixture games: %w", err)
+	}
+	if games > 0 && (req.GameModeID != nil || req.Singles != nil || req.Doubles != nil) {
+		return nil, fmt.Errorf("cannot change game mode or match format after the lineup is set")
+	}
+	if fixture.Status != models.FixtureStatusScheduled && req.ScheduledAt != nil {
+		return nil, fmt.Errorf("cannot reschedule a fixture that has started")
+	}
+
+	if req.Opponent != nil {
+		fixture.Opponent = *req.Opponent
+	}
+	if req.IsHome != nil {
+		fixture.IsHome = *req.IsHome
+	}
+	if req.Location != nil {
+		fixture.Location = emptyToNil(*req.Location)
+	}
+	if req.GameModeID != nil {
+		if fixture.GameModeID, err = s.findGameMode(*req.GameModeID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Singles != nil {
+		fixture.Singles = *req.Singles
+	}
+	if req.Doubles != nil {
+		fixture.Doubles = *req.Doubles
+	}
+	if fixture.GameCount() == 0 {
+		return nil, fmt.Errorf("match format needs at least one game")
+	}
+
+	if req.ScheduledAt != nil {
+		fixture.ScheduledAt = *req.ScheduledAt
+	}
+	if req.SeasonID != nil {
+		if fixture.SeasonID, err = s.fixtureSeason(req.SeasonID, fixture.ScheduledAt); err != nil {
+			return nil, err
+		}
+	} else if req.ScheduledAt != nil {
+		// A rescheduled fixture moves to the season of its new date
+		if fixture.SeasonID, err = s.fixtureSeason(nil, fixture.ScheduledAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.db.Omit("Team", "Season", "GameMode", "Games").Save(fixture).Error; err != nil {
+		return nil, fmt.Errorf("failed to update fixture: %w", err)
+	}
+
+	return s.GetFixtureByID(fixture.ID)
+}
+
+// DeleteFixture removes a fixture that has not started, including its lineup
+func (s *FixtureService) DeleteFixture(id uuid.UUID) error {
+	fixture, err := s.findFixture(id)
+	if err != nil {
+		return err
+	}
+	if fixture.Status != models.FixtureStatusScheduled {
+		return fmt.Errorf("cannot delete a fixture that has started")
+	}
+
+	tx := s.db.Begin()
+
+	if err := tx.Where("fixture_id = ?", fixture.ID).Delete(&models.TrainingGame{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete fixture games: %w", err)
+	}
+	if err := tx.Delete(fixture).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete fixture: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetLineup replaces the games of a fixture that has not started. Every
+// player must be on the team roster on the day of the fixture. Only captains
+// and vice captains of the team may set the lineup unless canManage is set.
+func (s *FixtureService) SetLineup(id uuid.UUID, req *models.FixtureLineupRequest, playerID *uuid.UUID, canManage bool, actor *string) (*models.Fixture, error) {
+	fixture, err := s.findFixture(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCaptain(fixture, playerID, canManage); err != nil {
+		return nil, err
+	}
+	if fixture.Status != models.FixtureStatusScheduled {
+		return nil, fmt.Errorf("cannot change the lineup of a fixture that has started")
+	}
+	if len(req.Games) != fixture.GameCount() {
+		return nil, fmt.Errorf("lineup needs %d games: %d singles followed by %d doubles", fixture.GameCount(), fixture.Singles, fixture.Doubles)
+	}
+
+	games := make([]models.TrainingGame, len(req.Games))
+	playerIDs := map[uuid.UUID]bool{}
+	for i, entry := range req.Games {
+		number := i + 1
+		size := 1
+		if i >= fixture.Singles {
+			size = 2
+		}
+		if len(entry.PlayerIDs) != size {
+			return nil, fmt.Errorf("game %d needs %d player(s)", number, size)
+		}
+
+		ids := make([]uuid.UUID, size)
+		for j, value := range entry.PlayerIDs {
+			if ids[j], err = uuid.Parse(value); err != nil {
+				return nil, fmt.Errorf("invalid player ID format")
+			}
+			playerIDs[ids[j]] = true
+		}
+		if size == 2 && ids[0] == ids[1] {
+			return nil, fmt.Errorf("game %d lists a player twice", number)
+		}
+
+		games[i] = models.TrainingGame{
+			FixtureID:  &fixture.ID,
+			GameModeID: fixture.GameModeID,
+			Player1ID:  &ids[0],
+			Guest2Name: entry.Opponent,
+			GameNumber: &number,
+			Status:     "pending",
+		}
+		if size == 2 {
+			games[i].Partner1ID = &ids[1]
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(playerIDs))
+	for id := range playerIDs {
+		ids = append(ids, id)
+	}
+	var rostered int64
+	if err := s.db.Model(&models.TeamMembership{}).
+		Where("team_id = ? AND player_id IN ?", fixture.TeamID, ids).
+		Where("player_id IN (SELECT id FROM players WHERE deleted_at IS NULL)").
+		Scopes(membershipsActiveOn(dayOf(fixture.ScheduledAt))).
+		Distinct("player_id").
+		Count(&rostered).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch team roster: %w", err)
+	}
+	if int(rostered) != len(ids) {
+		return nil, fmt.Errorf("all players must be on the team roster on the day of the fixture")
+	}
+
+	tx := s.db.Begin()
+
+	if err := tx.Where("fixture_id = ?", fixture.ID).Delete(&models.TrainingGame{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete fixture games: %w", err)
+	}
+	if err := tx.Create(&games).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create fixture games: %w", err)
+	}
+	now := time.Now()
+	if err := tx.Model(fixture).Updates(map[string]interface{}{
+		"lineup_set_by": actor,
+		"lineup_set_at": now,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update fixture: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetFixtureByID(fixture.ID)
+}
+
+// RecordGameResult scores a game of the fixture like a training game, the
+// match score follows from the game results
+func (s *FixtureService) RecordGameResult(id, gameID uuid.UUID, req *models.FixtureGameResultRequest, playerID *uuid.UUID, canManage bool) (*models.TrainingGame, error) {
+	fixture, err := s.findFixture(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCaptain(fixture, playerID, canManage); err != nil {
+		return nil, err
+	}
+
+	var game models.TrainingGame
+	if err := s.db.Where("fixture_id = ?", fixture.ID).First(&game, "id = ?", gameID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("game not found")
+		}
+		return nil, fmt.Errorf("failed to fetch game: %w", err)
+	}
+
+	return NewGameService(s.db).applyGameUpdate(&game, req.Player1Score, req.Player2Score, req.Status, req.Winner)
+}
+
+// updateFixtureScore recalculates score, status and result of the fixture a
+// game belongs to. Games of training sessions are ignored.
+func updateFixtureScore(tx *gorm.DB, game *models.TrainingGame) error {
+	if game.FixtureID == nil {
+		return nil
+	}
+
+	var fixture models.Fixture
+	if err := tx.First(&fixture, "id = ?", *game.FixtureID).Error; err != nil {
+		return fmt.Errorf("failed to fetch fixture: %w", err)
+	}
+	var games []models.TrainingGame
+	if err := tx.Where("fixture_id = ?", fixture.ID).Find(&games).Error; err != nil {
+		return fmt.Errorf("failed to fetch fixture games: %w", err)
+	}
+
+	var teamScore, opponentScore, teamLegs, opponentLegs, completed int
+	open := 0
+	started := false
+	for _, g := range games {
+		switch g.Status {
+		case "completed":
+			started = true
+			completed++
+			teamLegs += g.Player1Score
+			opponentLegs += g.Player2Score
+			if g.Winner != nil && *g.Winner == "player1" {
+				teamScore++
+			} else if g.Winner != nil && *g.Winner == "player2" {
+				opponentScore++
+			}
+		case "cancelled":
+		case "playing":
+			started = true
+			open++
+		default:
+			open++
+		}
+	}
+
+	status := models.FixtureStatusScheduled
+	var result *string
+	var completedAt *time.Time
+	switch {
+	case open == 0 && completed > 0:
+		status = models.FixtureStatusCompleted
+		outcome := models.FixtureResultDraw
+		if teamScore > opponentScore {
+			outcome = models.FixtureResultWin
+		} else if teamScore < opponentScore {
+			outcome = models.FixtureResultLoss
+		}
+		result = &outcome
+		completedAt = fixture.CompletedAt
+		if completedAt == nil {
+			now := time.Now()
+			completedAt = &now
+		}
+	case started:
+		status = models.FixtureStatusInProgress
+	}
+
+	if err := tx.Model(&fixture).Updates(map[string]interface{}{
+		"team_score":     teamScore,
+		"opponent_score": opponentScore,
+		"team_legs":      teamLegs,
+		"opponent_legs":  opponentLegs,
+		"status":         status,
+		"result":         result,
+		"completed_at":   completedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update fixture score: %w", err)
+	}
+	return nil
+}
+
+// checkCaptain allows managers and the captains and vice captains of the
+// fixture's team, today or on the day of the fixture
+func (s *FixtureService) checkCaptain(fixture *models.Fixture, playerID *uuid.UUID, canManage bool) error {
+	if canManage {
+		return nil
+	}
+	if playerID != nil {
+		captain, err := isTeamCaptain(s.db, *playerID, fixture.TeamID, today(), dayOf(fixture.ScheduledAt))
+		if err != nil {
+			return err
+		}
+		if captain {
+			return nil
+		}
+	}
+	return fmt.Errorf("only a captain of the team can manage the fixture")
+}
+
+// fixtureSeason returns the requested season, which must contain the fixture
+// date, or without a request the season containing the date if there is one.
+// An empty season ID removes the season.
+func (s *FixtureService) fixtureSeason(seasonID *string, scheduledAt time.Time) (*uuid.UUID, error) {
+	day := dayOf(scheduledAt)
+	if seasonID != nil {
+		if *seasonID == "" {
+			return nil, nil
+		}
+		id, err := uuid.Parse(*seasonID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid season ID format")
+		}
+		season, err := findSeason(s.db, id)
+		if err != nil {
+			return nil, err
+		}
+		if !season.Contains(day) {
+			return nil, fmt.Errorf("fixture must lie within the season")
+		}
+		return &season.ID, nil
+	}
+
+	var season models.Season
+	err := s.db.Where("starts_on <= ? AND ends_on >= ?", day.Format(dateLayout), day.Format(dateLayout)).
+		Order("starts_on DESC").
+		First(&season).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch season: %w", err)
+	}
+	return &season.ID, nil
+}
+
+func (s *FixtureService) findGameMode(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid game mode ID format")
+	}
+	var gameMode models.GameMode
+	if err := s.db.First(&gameMode, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return uuid.Nil, fmt.Errorf("game mode not found")
+		}
+		return uuid.Nil, fmt.Errorf("failed to fetch game mode: %w", err)
+	}
+	return gameMode.ID, nil
+}
+
+func (s *FixtureService) findFixture(id uuid.UUID) (*models.Fixture, error) {
+	var fixture models.Fixture
+	if err := s.db.First(&fixture, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("fixture not found")
+		}
+		return nil, fmt.Errorf("failed to fetch fixture: %w", err)
+	}
+	return &fixture, nil
+}
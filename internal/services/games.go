@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"darts-training-app/internal/models"
@@ -11,12 +13,22 @@ import (
 )
 
 type GameService struct {
-	db *gorm.DB
+	db                *gorm.DB
+	tournamentService *TournamentService
 }
 
 func NewGameService(db *gorm.DB) *GameService {
 	return &GameService{
-		db: db,
+		db:                db,
+		tournamentService: NewTournamentService(db),
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *GameService) WithContext(ctx context.Context) *GameService {
+	return &GameService{
+		db:                s.db.WithContext(ctx),
+		tournamentService: s.tournamentService.WithContext(ctx),
 	}
 }
 
@@ -43,7 +55,9 @@ func (s *GameService) GetGamesByTrainingSession(trainingSessionID uuid.UUID) ([]
 	return games, nil
 }
 
-func (s *GameService) CreateGame(trainingSessionID uuid.UUID, gameModeID uuid.UUID, player1ID, player2ID *uuid.UUID, guest1Name, guest2Name *string) (*models.TrainingGame, error) {
+// CreateGame creates a game between two players or guests. Guests are given by
+// ID or, for guests of the training, by name.
+func (s *GameService) CreateGame(trainingSessionID uuid.UUID, gameModeID uuid.UUID, player1ID, player2ID, guest1ID, guest2ID *uuid.UUID, guest1Name, guest2Name *string) (*models.TrainingGame, error) {
 	// Validate training session
 	var session models.TrainingSession
 	if err := s.db.First(&session, "id = ?", trainingSessionID).Error; err != nil {
@@ -89,10 +103,10 @@ func (s *GameService) CreateGame(trainingSessionID uuid.UUID, gameModeID uuid.UU
 
 	// Validate that we have exactly two players (regular or guest)
 	playerCount := 0
-	if player1ID != nil || guest1Name != nil {
+	if player1ID != nil || guest1ID != nil || guest1Name != nil {
 		playerCount++
 	}
-	if player2ID != nil || guest2Name != nil {
+	if player2ID != nil || guest2ID != nil || guest2Name != nil {
 		playerCount++
 	}
 
@@ -100,11 +114,29 @@ func (s *GameService) CreateGame(trainingSessionID uuid.UUID, gameModeID uuid.UU
 		return nil, fmt.Errorf("exactly two players are required for each game")
 	}
 
+	var err error
+	if player1ID == nil {
+		if guest1ID, guest1Name, err = s.resolveGameGuest(trainingSessionID, guest1ID, guest1Name); err != nil {
+			return nil, err
+		}
+	} else {
+		guest1ID, guest1Name = nil, nil
+	}
+	if player2ID == nil {
+		if guest2ID, guest2Name, err = s.resolveGameGuest(trainingSessionID, guest2ID, guest2Name); err != nil {
+			return nil, err
+		}
+	} else {
+		guest2ID, guest2Name = nil, nil
+	}
+
 	game := &models.TrainingGame{
-		TrainingSessionID: trainingSessionID,
+		TrainingSessionID: &trainingSessionID,
 		GameModeID:        gameModeID,
 		Player1ID:         player1ID,
 		Player2ID:         player2ID,
+		Guest1ID:          guest1ID,
+		Guest2ID:          guest2ID,
 		Guest1Name:        guest1Name,
 		Guest2Name:        guest2Name,
 		Status:            "pending",
@@ -116,7 +148,7 @@ func (s *GameService) CreateGame(trainingSessionID uuid.UUID, gameModeID uuid.UU
 
 	// Load relationships for response
 	var result models.TrainingGame
-	err := s.db.Preload("GameMode").
+	err = s.db.Preload("GameMode").
 		Preload("Player1").
 		Preload("Player2").
 		First(&result, "id = ?", game.ID).Error
@@ -127,6 +159,44 @@ func (s *GameService) CreateGame(trainingSessionID uuid.UUID, gameModeID uuid.UU
 	return &result, nil
 }
 
+// resolveGameGuest returns the guest profile of a game participant. A name is
+// looked up among the guests of the training, unknown names get a new profile.
+func (s *GameService) resolveGameGuest(trainingSessionID uuid.UUID, guestID *uuid.UUID, guestName *string) (*uuid.UUID, *string, error) {
+	if guestID != nil {
+		var guest models.Guest
+		if err := s.db.First(&guest, "id = ?", *guestID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, nil, fmt.Errorf("guest not found")
+			}
+			return nil, nil, fmt.Errorf("failed to fetch guest: %w", err)
+		}
+		return &guest.ID, &guest.Name, nil
+	}
+	if guestName == nil || strings.TrimSpace(*guestName) == "" {
+		return nil, nil, nil
+	}
+
+	name := strings.TrimSpace(*guestName)
+	var guestIDs []uuid.UUID
+	if err := s.db.Model(&models.TrainingPlayer{}).
+		Where("training_session_id = ? AND is_guest = ? AND guest_name = ? AND guest_id IS NOT NULL", trainingSessionID, true, name).
+		Pluck("guest_id", &guestIDs).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to find guest: %w", err)
+	}
+	switch len(guestIDs) {
+	case 0:
+		guest := models.Guest{Name: name}
+		if err := s.db.Create(&guest).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to create guest: %w", err)
+		}
+		return &guest.ID, &guest.Name, nil
+	case 1:
+		return &guestIDs[0], &name, nil
+	default:
+		return nil, nil, fmt.Errorf("guest name is ambiguous, use the guest ID")
+	}
+}
+
 func (s *GameService) UpdateGame(id uuid.UUID, player1Score, player2Score *int, status *string, winner *string) (*models.TrainingGame, error) {
 	var game models.TrainingGame
 	if err := s.db.First(&game, "id = ?", id).Error; err != nil {
@@ -136,6 +206,17 @@ func (s *GameService) UpdateGame(id uuid.UUID, player1Score, player2Score *int,
 		return nil, fmt.Errorf("failed to fetch game: %w", err)
 	}
 
+	// Fixture games are scored by the team's captain via the fixture
+	if game.FixtureID != nil {
+		return nil, fmt.Errorf("fixture games are scored via the fixture")
+	}
+
+	return s.applyGameUpdate(&game, player1Score, player2Score, status, winner)
+}
+
+// applyGameUpdate records scores, status and winner of a loaded game and
+// updates the tournament or fixture the game belongs to
+func (s *GameService) applyGameUpdate(game *models.TrainingGame, player1Score, player2Score *int, status *string, winner *string) (*models.TrainingGame, error) {
 	// Update fields if provided
 	if player1Score != nil {
 		game.Player1Score = *player1Score
@@ -192,15 +273,36 @@ func (s *GameService) UpdateGame(id uuid.UUID, player1Score, player2Score *int,
 		}
 	}
 
-	if err := s.db.Save(&game).Error; err != nil {
+	// Start transaction, completing a tournament game advances the bracket
+	tx := s.db.Begin()
+
+	if err := tx.Save(game).Error; err != nil {
+		tx.Rollback()
 		return nil, fmt.Errorf("failed to update game: %w", err)
 	}
 
+	if game.Status == "completed" {
+		if err := s.tournamentService.HandleGameCompleted(tx, game); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := updateFixtureScore(tx, game); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	// Load relationships for response
 	var result models.TrainingGame
 	err := s.db.Preload("GameMode").
 		Preload("Player1").
 		Preload("Player2").
+		Preload("Partner1").
 		First(&result, "id = ?", game.ID).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch updated game: %w", err)
@@ -223,6 +325,18 @@ func (s *GameService) DeleteGame(id uuid.UUID) error {
 		return fmt.Errorf("cannot delete game that is in progress or completed")
 	}
 
+	// Tournament games are managed by the bracket
+	tournamentCount := int64(0)
+	s.db.Model(&models.TournamentMatch{}).Where("training_game_id = ?", id).Count(&tournamentCount)
+	if tournamentCount > 0 {
+		return fmt.Errorf("cannot delete game that belongs to a tournament")
+	}
+
+	// Fixture games are managed by the lineup
+	if game.FixtureID != nil {
+		return fmt.Errorf("cannot delete game that belongs to a fixture")
+	}
+
 	if err := s.db.Delete(&game).Error; err != nil {
 		return fmt.Errorf("failed to delete game: %w", err)
 	}
@@ -230,7 +344,17 @@ func (s *GameService) DeleteGame(id uuid.UUID) error {
 	return nil
 }
 
-func (s *GameService) GenerateGamesForTraining(trainingSessionID uuid.UUID, gameModeID uuid.UUID) ([]models.TrainingGame, error) {
+// GenerateGamesForTraining creates games for a training session. The "round_robin"
+// format creates every pairing at once, "swiss" creates the next Swiss round.
+func (s *GameService) GenerateGamesForTraining(trainingSessionID uuid.UUID, gameModeID uuid.UUID, format string) ([]models.TrainingGame, error) {
+	switch format {
+	case "", "round_robin":
+	case "swiss":
+		return s.generateSwissRound(trainingSessionID, gameModeID)
+	default:
+		return nil, fmt.Errorf("invalid pairing format: %s", format)
+	}
+
 	// Get training session with players
 	var session models.TrainingSession
 	err := s.db.Preload("TrainingPlayers.Player").First(&session, "id = ?", trainingSessionID).Error
@@ -265,26 +389,30 @@ func (s *GameService) GenerateGamesForTraining(trainingSessionID uuid.UUID, game
 			player1 := attendingPlayers[i]
 			player2 := attendingPlayers[j]
 
-			var player1ID, player2ID *uuid.UUID
+			var player1ID, player2ID, guest1ID, guest2ID *uuid.UUID
 			var guest1Name, guest2Name *string
 
 			if player1.IsGuest {
+				guest1ID = player1.GuestID
 				guest1Name = player1.GuestName
 			} else {
 				player1ID = player1.PlayerID
 			}
 
 			if player2.IsGuest {
+				guest2ID = player2.GuestID
 				guest2Name = player2.GuestName
 			} else {
 				player2ID = player2.PlayerID
 			}
 
 			game := &models.TrainingGame{
-				TrainingSessionID: trainingSessionID,
+				TrainingSessionID: &trainingSessionID,
 				GameModeID:        gameModeID,
 				Player1ID:         player1ID,
 				Player2ID:         player2ID,
+				Guest1ID:          guest1ID,
+				Guest2ID:          guest2ID,
 				Guest1Name:        guest1Name,
 				Guest2Name:        guest2Name,
 				Status:            "pending",
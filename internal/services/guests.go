@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GuestService struct {
+	db           *gorm.DB
+	suggestAfter int // visits after which a guest is suggested to become a member
+}
+
+func NewGuestService(db *gorm.DB, cfg *config.Config) *GuestService {
+	return &GuestService{
+		db:           db,
+		suggestAfter: cfg.GuestMembershipVisits,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *GuestService) WithContext(ctx context.Context) *GuestService {
+	copy := *s
+	copy.db = s.db.WithContext(ctx)
+	return &copy
+}
+
+// GetGuests returns the guests of the club with their statistics, optionally
+// filtered by a part of the name
+func (s *GuestService) GetGuests(search string) ([]models.Guest, map[uuid.UUID]models.GuestStats, error) {
+	var guests []models.Guest
+	query := s.db.Preload("Inviter").Order("name")
+	if search = strings.TrimSpace(search); search != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(search)+"%")
+	}
+	if err := query.Find(&guests).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch guests: %w", err)
+	}
+
+	stats, err := s.guestStats(guests)
+	if err != nil {
+		return nil, nil, err
+	}
+	return guests, stats, nil
+}
+
+// GetMembershipSuggestions returns the guests who visited often enough to be
+// asked to join the club, most frequent first
+func (s *GuestService) GetMembershipSuggestions() ([]models.Guest, map[uuid.UUID]models.GuestStats, error) {
+	var guests []models.Guest
+	if err := s.db.Preload("Inviter").Where("converted_player_id IS NULL").Find(&guests).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch guests: %w", err)
+	}
+
+	stats, err := s.guestStats(guests)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suggested := []models.Guest{}
+	for _, guest := range guests {
+		if stats[guest.ID].SuggestMembership {
+			suggested = append(suggested, guest)
+		}
+	}
+	sort.SliceStable(suggested, func(i, j int) bool {
+		return stats[suggested[i].ID].Visits > stats[suggested[j].ID].Visits
+	})
+	return suggested, stats, nil
+}
+
+func (s *GuestService) GetGuestByID(id uuid.UUID) (*models.Guest, models.GuestStats, error) {
+	var guest models.Guest
+	if err := s.db.Preload("Inviter").First(&guest, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, models.GuestStats{}, fmt.Errorf("guest not found")
+		}
+		return nil, models.GuestStats{}, fmt.Errorf("failed to fetch guest: %w", err)
+	}
+
+	stats, err := s.guestStats([]models.Guest{guest})
+	if err != nil {
+		return nil, models.GuestStats{}, err
+	}
+	return &guest, stats[guest.ID], nil
+}
+
+func (s *GuestService) CreateGuest(req *models.GuestCreateRequest) (*models.Guest, error) {
+	invitedBy, err := findInviter(s.db, req.InvitedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	guest := &models.Guest{
+		Name:      strings.TrimSpace(req.Name),
+		Email:     req.Email,
+		Phone:     req.Phone,
+		InvitedBy: invitedBy,
+	}
+	if err := s.db.Create(guest).Error; err != nil {
+		return nil, fmt.Errorf("failed to create guest: %w", err)
+	}
+
+	return guest, nil
+}
+
+// UpdateGuest changes the profile. A new name is copied to the guest's
+// training entries and games.
+func (s *GuestService) UpdateGuest(id uuid.UUID, req *models.GuestUpdateRequest) (*models.Guest, error) {
+	var guest models.Guest
+	if err := s.db.First(&guest, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("guest not found")
+		}
+		return nil, fmt.Errorf("failed to fetch guest: %w", err)
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Email != nil {
+		updates["email"] = emptyToNil(strings.TrimSpace(*req.Email))
+	}
+	if req.Phone != nil {
+		updates["phone"] = emptyToNil(strings.TrimSpace(*req.Phone))
+	}
+	if req.InvitedBy != nil {
+		invitedBy, err := findInviter(s.db, req.InvitedBy)
+		if err != nil {
+			return nil, err
+		}
+		updates["invited_by"] = invitedBy
+	}
+	if len(updates) == 0 {
+		return &guest, nil
+	}
+
+	tx := s.db.Begin()
+	if err := tx.Model(&guest).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update guest: %w", err)
+	}
+	if name, ok := updates["name"]; ok {
+		if err := tx.Model(&models.TrainingPlayer{}).Where("guest_id = ? AND is_guest = ?", id, true).UpdateColumn("guest_name", name).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to rename guest entries: %w", err)
+		}
+		if err := tx.Model(&models.TrainingGame{}).Where("guest1_id = ? AND player1_id IS NULL", id).UpdateColumn("guest1_name", name).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to rename guest games: %w", err)
+		}
+		if err := tx.Model(&models.TrainingGame{}).Where("guest2_id = ? AND player2_id IS NULL", id).UpdateColumn("guest2_name", name).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to rename guest games: %w", err)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if err := s.db.Preload("Inviter").First(&guest, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch guest: %w", err)
+	}
+	return &guest, nil
+}
+
+// DeleteGuest removes a guest who never took part in a training
+func (s *GuestService) DeleteGuest(id uuid.UUID) error {
+	var guest models.Guest
+	if err := s.db.First(&guest, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("guest not found")
+		}
+		return fmt.Errorf("failed to fetch guest: %w", err)
+	}
+
+	var entries int64
+	if err := s.db.Model(&models.TrainingPlayer{}).Where("guest_id = ?", id).Count(&entries).Error; err != nil {
+		return fmt.Errorf("failed to check guest entries: %w", err)
+	}
+	if entries > 0 {
+		return fmt.Errorf("cannot delete guest who took part in %d training sessions", entries)
+	}
+
+	if err := s.db.Delete(&guest).Error; err != nil {
+		return fmt.Errorf("failed to delete guest: %w", err)
+	}
+	return nil
+}
+
+// ConvertGuest creates a member from the guest and moves the guest's training
+// entries and games to the new player
+func (s *GuestService) ConvertGuest(id uuid.UUID, req *models.GuestConvertRequest, actor *string) (*models.Player, error) {
+	var guest models.Guest
+	if err := s.db.First(&guest, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("guest not found")
+		}
+		return nil, fmt.Errorf("failed to fetch guest: %w", err)
+	}
+	if guest.ConvertedPlayerID != nil {
+		return nil, fmt.Errorf("guest is already converted")
+	}
+
+	email := guest.Email
+	if req.Email != nil {
+		email = req.Email
+	}
+	if email == nil || *email == "" {
+		return nil, fmt.Errorf("email is required to convert a guest")
+	}
+
+	tx := s.db.Begin()
+
+	player, err := NewPlayerService(tx).CreatePlayer(&models.PlayerCreateRequest{
+		Name:     guest.Name,
+		Email:    *email,
+		IsActive: true,
+		Category: req.Category,
+		TeamID:   req.TeamID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	result := &models.PlayerMergeResult{TargetPlayerID: player.ID, GuestID: &guest.ID}
+	if err := mergeGuest(tx, player, guest.ID, result, actor); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return NewPlayerService(s.db).GetPlayerByID(player.ID)
+}
+
+// guestStats counts the attended, completed trainings of the guests
+func (s *GuestService) guestStats(guests []models.Guest) (map[uuid.UUID]models.GuestStats, error) {
+	stats := make(map[uuid.UUID]models.GuestStats, len(guests))
+	if len(guests) == 0 {
+		return stats, nil
+	}
+
+	ids := make([]uuid.UUID, len(guests))
+	for i, guest := range guests {
+		ids[i] = guest.ID
+	}
+
+	var rows []struct {
+		GuestID     uuid.UUID
+		Visits      int
+		LastVisitAt *time.Time
+	}
+	if err := s.db.Model(&models.TrainingPlayer{}).
+		Select("training_players.guest_id, COUNT(*) AS visits, MAX(training_sessions.training_date) AS last_visit_at").
+		Joins("JOIN training_sessions ON training_sessions.id = training_players.training_session_id").
+		Where("training_players.guest_id IN ? AND training_players.attended = ?", ids, true).
+		Where("training_sessions.status = ? AND training_sessions.deleted_at IS NULL", "completed").
+		Group("training_players.guest_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count guest visits: %w", err)
+	}
+
+	for _, row := range rows {
+		stats[row.GuestID] = models.GuestStats{
+			Visits:      row.Visits,
+			LastVisitAt: row.LastVisitAt,
+		}
+	}
+	for _, guest := range guests {
+		guestStats := stats[guest.ID]
+		guestStats.SuggestMembership = guest.ConvertedPlayerID == nil && guestStats.Visits >= s.suggestAfter
+		stats[guest.ID] = guestStats
+	}
+	return stats, nil
+}
+
+// findInviter resolves the member who brought a guest, an empty ID means none
+func findInviter(db *gorm.DB, invitedBy *string) (*uuid.UUID, error) {
+	if invitedBy == nil || *invitedBy == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*invitedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inviting player ID format")
+	}
+
+	var count int64
+	if err := db.Model(&models.Player{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to validate inviting player: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("inviting player not found")
+	}
+	return &id, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
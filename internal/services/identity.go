@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// userInfoCacheTTL limits how often the userinfo endpoint is asked about the
+// same user, e.g. while their email is still unverified
+const userInfoCacheTTL = 10 * time.Minute
+
+// UserInfoProvider returns the profile of the user an access token belongs to
+type UserInfoProvider interface {
+	GetUserInfo(accessToken string) (*models.Auth0User, error)
+}
+
+type cachedUserInfo struct {
+	user      *models.Auth0User
+	expiresAt time.Time
+}
+
+// PrincipalResolver maps an authenticated token to the caller of a request
+type PrincipalResolver interface {
+	ResolvePrincipal(ctx context.Context, token models.UserToken, accessToken string) (*models.Principal, error)
+}
+
+// skipPlayerLinkingKey marks requests that link the caller's player themselves
+type skipPlayerLinkingKey struct{}
+
+// SkipPlayerLinking returns a context in which ResolvePrincipal only uses a
+// player already linked to the subject and neither links nor creates one by
+// email. Accepting an invitation links the invited player, a player linked or
+// created beforehand would block it.
+func SkipPlayerLinking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipPlayerLinkingKey{}, true)
+}
+
+// PlayerLinkingSkipped reports whether ctx was returned by SkipPlayerLinking
+func PlayerLinkingSkipped(ctx context.Context) bool {
+	skipped, _ := ctx.Value(skipPlayerLinkingKey{}).(bool)
+	return skipped
+}
+
+// IdentityService maps token subjects to players
+type IdentityService struct {
+	playerService     *PlayerService
+	userInfo          UserInfoProvider
+	autoCreatePlayers bool
+
+	cacheMutex sync.Mutex
+	cache      map[string]cachedUserInfo
+}
+
+// NewIdentityService creates the service, userInfo may be nil to rely on the
+// token claims only
+func NewIdentityService(playerService *PlayerService, userInfo UserInfoProvider, cfg *config.Config) *IdentityService {
+	return &IdentityService{
+		playerService:     playerService,
+		userInfo:          userInfo,
+		autoCreatePlayers: cfg.AutoCreatePlayers,
+		cache:             map[string]cachedUserInfo{},
+	}
+}
+
+// ResolvePrincipal returns the caller of a request. A player already linked to
+// the subject is used directly. Otherwise name and email come from the token
+// or the userinfo endpoint, and a player with the same verified email is
+// linked or a new player created. Unverified emails are never used for
+// linking so nobody can take over another member's profile. Players are
+// looked up and created in the club of ctx.
+func (s *IdentityService) ResolvePrincipal(ctx context.Context, token models.UserToken, accessToken string) (*models.Principal, error) {
+	playerService := s.playerService.WithContext(ctx)
+
+	principal := &models.Principal{
+		Subject:  token.Subject,
+		Email:    token.Email,
+		Name:     token.Name,
+		Nickname: token.Nickname,
+	}
+	if token.EmailVerified != nil {
+		principal.EmailVerified = *token.EmailVerified
+	}
+	if principal.Subject == "" {
+		return principal, nil
+	}
+
+	player, err := playerService.GetPlayerByAuth0ID(principal.Subject)
+	if err == nil {
+		s.applyPlayer(principal, player)
+		return principal, nil
+	}
+	if err.Error() != "player not found" {
+		return nil, err
+	}
+
+	// Client credential tokens belong to machines, not players
+	if strings.HasSuffix(principal.Subject, "@clients") || PlayerLinkingSkipped(ctx) {
+		return principal, nil
+	}
+
+	if (principal.Email == "" || !principal.EmailVerified) && s.userInfo != nil && accessToken != "" {
+		if user := s.lookupUserInfo(principal.Subject, accessToken); user != nil {
+			principal.Email = user.Email
+			principal.EmailVerified = user.EmailVerified
+			if user.Name != "" {
+				principal.Name = user.Name
+			}
+			if user.Nickname != "" {
+				principal.Nickname = user.Nickname
+			}
+		}
+	}
+
+	if !s.autoCreatePlayers || principal.Email == "" || !principal.EmailVerified {
+		return principal, nil
+	}
+
+	name := principal.Name
+	if name == "" {
+		name = principal.Nickname
+	}
+	if name == "" {
+		name = strings.Split(principal.Email, "@")[0]
+	}
+
+	player, err = playerService.FindOrCreatePlayerByAuth0(models.Auth0User{
+		Sub:           principal.Subject,
+		Email:         principal.Email,
+		EmailVerified: principal.EmailVerified,
+		Name:          name,
+		Nickname:      principal.Nickname,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("subject", principal.Subject).Str("player_id", player.ID.String()).Msg("linked user to player")
+	s.applyPlayer(principal, player)
+	return principal, nil
+}
+
+func (s *IdentityService) applyPlayer(principal *models.Principal, player *models.Player) {
+	principal.PlayerID = &player.ID
+	principal.Player = player
+	if principal.Email == "" {
+		principal.Email = player.Email
+	}
+	if principal.Name == "" {
+		principal.Name = player.Name
+	}
+	if principal.Nickname == "" && player.Nickname != nil {
+		principal.Nickname = *player.Nickname
+	}
+}
+
+func (s *IdentityService) lookupUserInfo(subject string, accessToken string) *models.Auth0User {
+	s.cacheMutex.Lock()
+	cached, ok := s.cache[subject]
+	s.cacheMutex.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.user
+	}
+
+	user, err := s.userInfo.GetUserInfo(accessToken)
+	if err != nil {
+		log.Warn().Err(err).Str("subject", subject).Msg("Failed to load user info")
+		return nil
+	}
+	// Only trust a profile describing the token's own subject
+	if user.Sub != "" && user.Sub != subject {
+		return nil
+	}
+
+	s.cacheMutex.Lock()
+	for cachedSubject, entry := range s.cache {
+		if time.Now().After(entry.expiresAt) {
+			delete(s.cache, cachedSubject)
+		}
+	}
+	s.cache[subject] = cachedUserInfo{user: user, expiresAt: time.Now().Add(userInfoCacheTTL)}
+	s.cacheMutex.Unlock()
+	return user
+}
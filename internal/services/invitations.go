@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/config"
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// invitationNonceLength is the number of random bytes in an invitation token
+const invitationNonceLength = 32
+
+type InvitationService struct {
+	db         *gorm.DB
+	mailSender MailSender
+	secret     []byte
+	ttl        time.Duration
+	acceptURL  string
+}
+
+// NewInvitationService signs tokens with INVITATION_SECRET. Without a secret a
+// random key is used, so links sent before a restart stop working.
+func NewInvitationService(db *gorm.DB, mailSender MailSender, cfg *config.Config) *InvitationService {
+	secret := []byte(cfg.InvitationSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Sprintf("failed to generate invitation secret: %v", err))
+		}
+		log.Warn().Msg("INVITATION_SECRET is not set, invitation links become invalid on restart")
+	}
+	return &InvitationService{
+		db:         db,
+		mailSender: mailSender,
+		secret:     secret,
+		ttl:        cfg.InvitationTTL,
+		acceptURL:  cfg.InvitationURL,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *InvitationService) WithContext(ctx context.Context) *InvitationService {
+	copy := *s
+	copy.db = s.db.WithContext(ctx)
+	return &copy
+}
+
+func (s *InvitationService) GetInvitations() ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	if err := s.db.Preload("Player").Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// CreateInvitation invites the holder of the email address to link their login
+// to the player and sends the invitation mail
+func (s *InvitationService) CreateInvitation(req *models.InvitationCreateRequest, createdBy *string) (*models.Invitation, error) {
+	playerID, err := uuid.Parse(req.PlayerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid player ID format")
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player.AnonymizedAt != nil {
+		return nil, fmt.Errorf("cannot invite an anonymized player")
+	}
+	if player.Auth0UserID != nil {
+		return nil, fmt.Errorf("player is already linked to an account")
+	}
+
+	email := player.Email
+	if req.Email != nil {
+		email = strings.TrimSpace(*req.Email)
+	}
+
+	var pending int64
+	if err := s.db.Model(&models.Invitation{}).
+		Where("player_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", playerID, time.Now()).
+		Count(&pending).Error; err != nil {
+		return nil, fmt.Errorf("failed to check pending invitations: %w", err)
+	}
+	if pending > 0 {
+		return nil, fmt.Errorf("player already has a pending invitation")
+	}
+
+	invitation := &models.Invitation{
+		ID:        uuid.New(),
+		PlayerID:  playerID,
+		Email:     email,
+		CreatedBy: createdBy,
+	}
+
+	// The invitation is only kept if the mail could be sent
+	tx := s.db.Begin()
+	token, err := s.issueToken(invitation)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Create(invitation).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	if err := s.sendInvitation(tx, invitation, &player, token); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	invitation.Player = &player
+	return invitation, nil
+}
+
+// ResendInvitation sends a new link and extends the expiry. Links sent before
+// stop working.
+func (s *InvitationService) ResendInvitation(id uuid.UUID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := s.db.Preload("Player").First(&invitation, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invitation not found")
+		}
+		return nil, fmt.Errorf("failed to fetch invitation: %w", err)
+	}
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return nil, fmt.Errorf("invitation is no longer valid")
+	}
+	if invitation.Player == nil {
+		return nil, fmt.Errorf("player not found")
+	}
+	if invitation.Player.Auth0UserID != nil {
+		return nil, fmt.Errorf("player is already linked to an account")
+	}
+
+	tx := s.db.Begin()
+	token, err := s.issueToken(&invitation)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Model(&invitation).Updates(map[string]interface{}{
+		"token_hash": invitation.TokenHash,
+		"expires_at": invitation.ExpiresAt,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update invitation: %w", err)
+	}
+	if err := s.sendInvitation(tx, &invitation, invitation.Player, token); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+func (s *InvitationService) RevokeInvitation(id uuid.UUID) error {
+	var invitation models.Invitation
+	if err := s.db.First(&invitation, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("invitation not found")
+		}
+		return fmt.Errorf("failed to fetch invitation: %w", err)
+	}
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return fmt.Errorf("invitation is no longer valid")
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&invitation).Update("revoked_at", now).Error; err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+	return nil
+}
+
+// AcceptInvitation links the login identified by subject to the invited
+// player. Each token can be used once.
+func (s *InvitationService) AcceptInvitation(token string, subject string) (*models.Player, error) {
+	id, nonce, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.db.Begin()
+
+	var invitation models.Invitation
+	if err := tx.First(&invitation, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invalid invitation")
+		}
+		return nil, fmt.Errorf("failed to fetch invitation: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashInvitationNonce(nonce)), []byte(invitation.TokenHash)) != 1 {
+		tx.Rollback()
+		return nil, fmt.Errorf("invalid invitation")
+	}
+	switch invitation.Status() {
+	case models.InvitationExpired:
+		tx.Rollback()
+		return nil, fmt.Errorf("invitation has expired")
+	case models.InvitationAccepted, models.InvitationRevoked:
+		tx.Rollback()
+		return nil, fmt.Errorf("invitation is no longer valid")
+	}
+
+	var player models.Player
+	if err := tx.First(&player, "id = ?", invitation.PlayerID).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player.Auth0UserID != nil && *player.Auth0UserID != subject {
+		tx.Rollback()
+		return nil, fmt.Errorf("player is already linked to an account")
+	}
+
+	// A player created for the account before the invitation was accepted,
+	// e.g. by AUTO_CREATE_PLAYERS for a different email, is replaced by the
+	// invited player as long as nothing refers to it yet
+	var linked []models.Player
+	if err := tx.Where("auth0_user_id = ? AND id != ?", subject, player.ID).Find(&linked).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to check linked players: %w", err)
+	}
+	for i := range linked {
+		used, err := playerHasHistory(tx, linked[i].ID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if used {
+			tx.Rollback()
+			return nil, fmt.Errorf("account is already linked to another player")
+		}
+		if err := tx.Model(&linked[i]).Update("auth0_user_id", nil).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to unlink player: %w", err)
+		}
+		if err := tx.Delete(&linked[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to delete player: %w", err)
+		}
+	}
+
+	// Only one of concurrent requests with the same token may accept it
+	now := time.Now()
+	result := tx.Model(&models.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitation.ID).
+		Updates(map[string]interface{}{"accepted_at": now, "accepted_by": subject})
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to accept invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, fmt.Errorf("invitation is no longer valid")
+	}
+
+	if err := tx.Model(&player).Update("auth0_user_id", subject).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to link player: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if err := s.db.Preload("Team").First(&player, "id = ?", player.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	return &player, nil
+}
+
+// playerHasHistory reports whether trainings, games, bookings, memberships or
+// other records refer to the player
+func playerHasHistory(tx *gorm.DB, playerID uuid.UUID) (bool, error) {
+	references := []struct {
+		model interface{}
+		where string
+	}{
+		{&models.TrainingPlayer{}, "player_id = ?"},
+		{&models.TrainingGame{}, "player1_id = ? OR player2_id = ? OR partner1_id = ?"},
+		{&models.TrainingSession{}, "created_by = ?"},
+		{&models.LedgerEntry{}, "player_id = ?"},
+		{&models.SessionExpense{}, "paid_by = ?"},
+		{&models.Invoice{}, "player_id = ?"},
+		{&models.BankTransaction{}, "player_id = ?"},
+		{&models.SepaMandate{}, "player_id = ?"},
+		{&models.Invitation{}, "player_id = ?"},
+		{&models.TeamMembership{}, "player_id = ?"},
+		{&models.Guest{}, "invited_by = ? OR converted_player_id = ?"},
+	}
+	for _, reference := range references {
+		args := make([]interface{}, strings.Count(reference.where, "?"))
+		for i := range args {
+			args[i] = playerID
+		}
+		var count int64
+		if err := tx.Model(reference.model).Where(reference.where, args...).Count(&count).Error; err != nil {
+			return false, fmt.Errorf("failed to check player history: %w", err)
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// issueToken sets a new token hash and expiry on the invitation and returns
+// the token. The token carries the invitation ID and a random nonce, signed
+// with the service's secret.
+func (s *InvitationService) issueToken(invitation *models.Invitation) (string, error) {
+	nonce := make([]byte, invitationNonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	payload := append(invitation.ID[:], nonce...)
+	invitation.TokenHash = hashInvitationNonce(nonce)
+	invitation.ExpiresAt = time.Now().Add(s.ttl)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload)), nil
+}
+
+// parseToken verifies the signature and returns the invitation ID and nonce
+func (s *InvitationService) parseToken(token string) (uuid.UUID, []byte, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(strings.TrimSpace(token), ".")
+	if !found {
+		return uuid.Nil, nil, fmt.Errorf("invalid invitation")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != len(uuid.UUID{})+invitationNonceLength {
+		return uuid.Nil, nil, fmt.Errorf("invalid invitation")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(payload)) {
+		return uuid.Nil, nil, fmt.Errorf("invalid invitation")
+	}
+
+	id, err := uuid.FromBytes(payload[:len(uuid.UUID{})])
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("invalid invitation")
+	}
+	return id, payload[len(uuid.UUID{}):], nil
+}
+
+func (s *InvitationService) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func hashInvitationNonce(nonce []byte) string {
+	sum := sha256.Sum256(nonce)
+	return hex.EncodeToString(sum[:])
+}
+
+// sendInvitation mails the link and records the delivery on the invitation
+func (s *InvitationService) sendInvitation(tx *gorm.DB, invitation *models.Invitation, player *models.Player, token string) error {
+	var club models.Club
+	if err := tx.First(&club, "id = ?", invitation.ClubID).Error; err != nil {
+		return fmt.Errorf("failed to fetch club: %w", err)
+	}
+
+	link := s.acceptURL + "?" + url.Values{"token": {token}, "club": {club.Slug}}.Encode()
+	body := fmt.Sprintf("Hallo %s,\n\n"+
+		"du wurdest eingeladen, dem Trainingsbereich von %s beizutreten.\n"+
+		"Melde dich an und bestätige die Einladung über folgenden Link:\n\n"+
+		"%s\n\n"+
+		"Der Link ist bis %s gültig und kann nur einmal verwendet werden.\n",
+		player.Name, club.Name, link, invitation.ExpiresAt.Format("02.01.2006 15:04"))
+
+	if err := s.mailSender.Send(Mail{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("Einladung zu %s", club.Name),
+		Body:    body,
+	}); err != nil {
+		log.Error().Err(err).Str("invitation_id", invitation.ID.String()).Msg("Failed to send invitation mail")
+		return fmt.Errorf("failed to send invitation mail")
+	}
+
+	now := time.Now()
+	invitation.SentAt = &now
+	invitation.SendCount++
+	if err := tx.Model(invitation).Updates(map[string]interface{}{
+		"sent_at":    invitation.SentAt,
+		"send_count": invitation.SendCount,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"darts-training-app/internal/models"
+)
+
+// invoiceDocument holds the formatted texts shared by the HTML and PDF output
+type invoiceDocument struct {
+	Number           string
+	IssuedAt         string
+	Period           string
+	IssuerName       string
+	IssuerIBAN       string
+	IssuerBIC        string
+	RecipientName    string
+	RecipientEmail   string
+	PaymentReference string
+	Lines            []invoiceDocumentLine
+	Total            string
+}
+
+type invoiceDocumentLine struct {
+	Position int
+	Date     string
+	Name     string
+	Amount   string
+}
+
+// invoiceDocument prepares the invoice for rendering, the issuer is the
+// creditor of the invoice's club
+func (s *InvoiceService) invoiceDocument(invoice *models.Invoice) (invoiceDocument, error) {
+	issuer, err := loadCreditor(s.db, invoice.ClubID)
+	if err != nil {
+		return invoiceDocument{}, err
+	}
+
+	document := invoiceDocument{
+		Number:           invoice.Number,
+		IssuedAt:         invoice.IssuedAt.Format("02.01.2006"),
+		Period:           invoice.Period(),
+		IssuerName:       issuer.Name,
+		IssuerIBAN:       issuer.IBAN,
+		IssuerBIC:        issuer.BIC,
+		PaymentReference: fmt.Sprintf("%s %s", models.PaymentReference(invoice.PlayerID), invoice.Number),
+		Total:            germanAmount(invoice.Total),
+	}
+	if document.IssuerName == "" {
+		document.IssuerName = "Dartverein"
+	}
+	if invoice.Player != nil {
+		document.RecipientName = invoice.Player.Name
+		document.RecipientEmail = invoice.Player.Email
+	}
+
+	for _, line := range invoice.Lines {
+		document.Lines = append(document.Lines, invoiceDocumentLine{
+			Position: line.Position,
+			Date:     line.TrainingDate.Format("02.01.2006"),
+			Name:     line.SessionName,
+			Amount:   germanAmount(line.Amount),
+		})
+	}
+	return document, nil
+}
+
+// germanAmount formats money with a decimal comma, e.g. "12,30 EUR"
+func germanAmount(amount models.Money) string {
+	return strings.Replace(amount.Decimal(), ".", ",", 1) + " " + amount.Currency
+}
+
+var invoiceHTMLTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<title>Rechnung {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 40px; color: #222; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { padding: 6px 8px; border-bottom: 1px solid #ddd; text-align: left; }
+td.amount, th.amount { text-align: right; }
+tfoot td { font-weight: bold; border-bottom: none; }
+.meta { margin-top: 24px; }
+</style>
+</head>
+<body>
+<p>{{.IssuerName}}</p>
+<p>{{.RecipientName}}<br>{{.RecipientEmail}}</p>
+<h1>Rechnung {{.Number}}</h1>
+<p class="meta">Rechnungsdatum: {{.IssuedAt}}<br>Leistungszeitraum: {{.Period}}</p>
+<table>
+<thead><tr><th>Pos.</th><th>Datum</th><th>Training</th><th class="amount">Betrag</th></tr></thead>
+<tbody>
+{{range .Lines}}<tr><td>{{.Position}}</td><td>{{.Date}}</td><td>{{.Name}}</td><td class="amount">{{.Amount}}</td></tr>
+{{end}}</tbody>
+<tfoot><tr><td colspan="3">Gesamtbetrag</td><td class="amount">{{.Total}}</td></tr></tfoot>
+</table>
+<p class="meta">Bitte überweise den Gesamtbetrag{{if .IssuerIBAN}} auf das Konto {{.IssuerIBAN}}{{if .IssuerBIC}} (BIC {{.IssuerBIC}}){{end}}{{end}} mit dem Verwendungszweck <strong>{{.PaymentReference}}</strong>.</p>
+</body>
+</html>
+`))
+
+func renderInvoiceHTML(document invoiceDocument) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := invoiceHTMLTemplate.Execute(&buffer, document); err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// pdfLinesPerPage is the number of line items that fit below the page header
+const pdfLinesPerPage = 30
+
+// renderInvoicePDF writes an A4 PDF using the standard Helvetica fonts, so no
+// font files need to be embedded
+func renderInvoicePDF(document invoiceDocument) []byte {
+	var pages []string
+	lines := document.Lines
+	for page := 0; page == 0 || len(lines) > 0; page++ {
+		count := len(lines)
+		if count > pdfLinesPerPage {
+			count = pdfLinesPerPage
+		}
+		pages = append(pages, invoicePDFPage(document, lines[:count], page == 0, len(lines) == count))
+		lines = lines[count:]
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3 regular font, 4 bold font, then a
+	// page and a content stream per page
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	var kids []string
+	for _, content := range pages {
+		pageID := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buffer bytes.Buffer
+	buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buffer.Len()
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buffer.Len()
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buffer.Bytes()
+}
+
+func invoicePDFPage(document invoiceDocument, lines []invoiceDocumentLine, first, last bool) string {
+	var content strings.Builder
+	text := func(font string, size float64, x, y float64, value string) {
+		fmt.Fprintf(&content, "BT /%s %.0f Tf %.0f %.0f Td (%s) Tj ET\n", font, size, x, y, pdfText(value))
+	}
+	// Amounts are right-aligned by estimating the Helvetica width of digits
+	rightText := func(font string, size float64, right float64, y float64, value string) {
+		text(font, size, right-float64(len(value))*size*0.556, y, value)
+	}
+
+	y := 780.0
+	if first {
+		text("F1", 10, 50, y, document.IssuerName)
+		text("F1", 11, 50, y-40, document.RecipientName)
+		text("F1", 11, 50, y-54, document.RecipientEmail)
+		text("F2", 18, 50, y-110, "Rechnung "+document.Number)
+		text("F1", 11, 50, y-134, "Rechnungsdatum: "+document.IssuedAt)
+		text("F1", 11, 50, y-148, "Leistungszeitraum: "+document.Period)
+		y -= 190
+	} else {
+		text("F1", 10, 50, y, "Rechnung "+document.Number+" (Fortsetzung)")
+		y -= 30
+	}
+
+	text("F2", 10, 50, y, "Pos.")
+	text("F2", 10, 90, y, "Datum")
+	text("F2", 10, 170, y, "Training")
+	rightText("F2", 10, 545, y, "Betrag")
+	fmt.Fprintf(&content, "0.5 w 50 %.0f m 545 %.0f l S\n", y-6, y-6)
+	y -= 22
+
+	for _, line := range lines {
+		name := line.Name
+		if runes := []rune(name); len(runes) > 60 {
+			name = string(runes[:57]) + "..."
+		}
+		text("F1", 10, 50, y, fmt.Sprintf("%d", line.Position))
+		text("F1", 10, 90, y, line.Date)
+		text("F1", 10, 170, y, name)
+		rightText("F1", 10, 545, y, line.Amount)
+		y -= 16
+	}
+
+	if last {
+		fmt.Fprintf(&content, "0.5 w 50 %.0f m 545 %.0f l S\n", y+10, y+10)
+		y -= 8
+		text("F2", 11, 50, y, "Gesamtbetrag")
+		rightText("F2", 11, 545, y, document.Total)
+		y -= 40
+
+		payment := "Bitte den Gesamtbetrag"
+		if document.IssuerIBAN != "" {
+			payment += " auf das Konto " + document.IssuerIBAN
+			if document.IssuerBIC != "" {
+				payment += " (BIC " + document.IssuerBIC + ")"
+			}
+		}
+		text("F1", 10, 50, y, payment+" überweisen.")
+		text("F1", 10, 50, y-14, "Verwendungszweck: "+document.PaymentReference)
+	}
+
+	return strings.TrimSuffix(content.String(), "\n")
+}
+
+// pdfText encodes a string for a WinAnsi font and escapes PDF string delimiters
+func pdfText(value string) string {
+	var encoded strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			encoded.WriteByte('\\')
+			encoded.WriteByte(byte(r))
+		case r == '€':
+			encoded.WriteByte(0x80)
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			encoded.WriteByte(byte(r))
+		default:
+			encoded.WriteByte('?')
+		}
+	}
+	return encoded.String()
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"darts-training-app/internal/database"
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// invoicedEntryTypes are the ledger entries billed on an invoice. Reimbursements
+// are settled through the balance and do not appear on invoices.
+var invoicedEntryTypes = []string{"charge", "expense"}
+
+type InvoiceService struct {
+	db *gorm.DB
+}
+
+func NewInvoiceService(db *gorm.DB) *InvoiceService {
+	return &InvoiceService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *InvoiceService) WithContext(ctx context.Context) *InvoiceService {
+	copy := *s
+	copy.db = s.db.WithContext(ctx)
+	return &copy
+}
+
+// invoiceSessionRow is the amount a player was charged for one finished session
+type invoiceSessionRow struct {
+	PlayerID          uuid.UUID
+	TrainingSessionID uuid.UUID
+	SessionName       string
+	TrainingDate      time.Time
+	Cents             int64
+	Currency          string
+}
+
+// GenerateMonthlyInvoices creates one invoice per player for the charges of
+// all sessions up to the end of the given month that are not invoiced yet, so
+// sessions finished after their month was invoiced land on the next invoice.
+// Running it again only picks up sessions finished in the meantime. Invoices
+// are numbered per club, so the run needs a club in its context.
+func (s *InvoiceService) GenerateMonthlyInvoices(year, month int) ([]models.Invoice, error) {
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("invalid invoice period")
+	}
+	if _, ok := database.ClubFromContext(s.db.Statement.Context); !ok {
+		return nil, fmt.Errorf("invoice run requires a club")
+	}
+	periodEnd := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local).AddDate(0, 1, 0)
+	issuedAt := time.Now()
+
+	tx := s.db.Begin()
+
+	// Lock the number sequence first so concurrent runs neither skip numbers
+	// nor invoice the same session twice
+	if err := lockInvoiceCounter(tx, issuedAt.Year()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var rows []invoiceSessionRow
+	err := tx.Table("ledger_entries AS le").
+		Select("le.player_id, le.training_session_id, ts.name AS session_name, ts.training_date, SUM(le.amount_cents) AS cents, le.amount_currency AS currency").
+		Joins("JOIN training_sessions ts ON ts.id = le.training_session_id AND ts.deleted_at IS NULL").
+		Joins("JOIN players p ON p.id = le.player_id AND p.deleted_at IS NULL").
+		Scopes(database.InClub("le.player_id", "players")).
+		Where("ts.status = ? AND ts.training_date < ?", "completed", periodEnd).
+		Where("le.type IN ?", invoicedEntryTypes).
+		Where("NOT EXISTS (SELECT 1 FROM invoice_lines il JOIN invoices i ON i.id = il.invoice_id WHERE il.training_session_id = le.training_session_id AND i.player_id = le.player_id)").
+		Group("le.player_id, le.training_session_id, ts.name, ts.training_date, le.amount_currency").
+		Order("le.player_id, ts.training_date, ts.name").
+		Scan(&rows).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to fetch training charges: %w", err)
+	}
+
+	// Group the sessions per player and currency, keeping the date order
+	type invoiceKey struct {
+		playerID uuid.UUID
+		currency string
+	}
+	var keys []invoiceKey
+	linesByKey := map[invoiceKey][]models.InvoiceLine{}
+	for _, row := range rows {
+		if row.Cents <= 0 {
+			continue
+		}
+		key := invoiceKey{playerID: row.PlayerID, currency: row.Currency}
+		if _, exists := linesByKey[key]; !exists {
+			keys = append(keys, key)
+		}
+		linesByKey[key] = append(linesByKey[key], models.InvoiceLine{
+			TrainingSessionID: row.TrainingSessionID,
+			SessionName:       row.SessionName,
+			TrainingDate:      row.TrainingDate,
+			Amount:            models.NewMoney(row.Cents, row.Currency),
+			Position:          len(linesByKey[key]) + 1,
+		})
+	}
+
+	invoices := make([]models.Invoice, 0, len(keys))
+	for _, key := range keys {
+		lines := linesByKey[key]
+		total := models.NewMoney(0, key.currency)
+		for _, line := range lines {
+			total = total.Add(line.Amount)
+		}
+
+		number, err := nextInvoiceNumber(tx, issuedAt.Year())
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		invoice := models.Invoice{
+			Number:      number,
+			PlayerID:    key.playerID,
+			PeriodYear:  year,
+			PeriodMonth: month,
+			Total:       total,
+			IssuedAt:    issuedAt,
+			Lines:       lines,
+		}
+		if err := tx.Create(&invoice).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to create invoice: %w", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for i := range invoices {
+		var player models.Player
+		if err := s.db.First(&player, "id = ?", invoices[i].PlayerID).Error; err == nil {
+			invoices[i].Player = &player
+		}
+	}
+
+	return invoices, nil
+}
+
+// lockInvoiceCounter creates the club's counter of the year if needed and
+// locks it until the transaction ends
+func lockInvoiceCounter(tx *gorm.DB, year int) error {
+	counter := models.InvoiceCounter{Year: year}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&counter).Error; err != nil {
+		return fmt.Errorf("failed to create invoice counter: %w", err)
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&counter, "year = ?", year).Error; err != nil {
+		return fmt.Errorf("failed to lock invoice counter: %w", err)
+	}
+	return nil
+}
+
+// nextInvoiceNumber increments the locked counter of the year, numbers are
+// gapless because the increment is rolled back together with the invoice
+func nextInvoiceNumber(tx *gorm.DB, year int) (string, error) {
+	var counter models.InvoiceCounter
+	if err := tx.First(&counter, "year = ?", year).Error; err != nil {
+		return "", fmt.Errorf("failed to fetch invoice counter: %w", err)
+	}
+	counter.LastNumber++
+	if err := tx.Model(&counter).Update("last_number", counter.LastNumber).Error; err != nil {
+		return "", fmt.Errorf("failed to update invoice counter: %w", err)
+	}
+	return fmt.Sprintf("%d-%06d", year, counter.LastNumber), nil
+}
+
+func (s *InvoiceService) GetPlayerInvoices(playerID uuid.UUID) ([]models.Invoice, error) {
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	var invoices []models.Invoice
+	if err := s.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Where("player_id = ?", playerID).Order("issued_at DESC, number DESC").Find(&invoices).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch invoices: %w", err)
+	}
+
+	for i := range invoices {
+		invoices[i].Player = &player
+	}
+	return invoices, nil
+}
+
+func (s *InvoiceService) GetInvoice(playerID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := s.db.Preload("Player").Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).First(&invoice, "id = ? AND player_id = ?", invoiceID, playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// RenderHTML returns the invoice as a printable HTML page
+func (s *InvoiceService) RenderHTML(invoice *models.Invoice) ([]byte, error) {
+	document, err := s.invoiceDocument(invoice)
+	if err != nil {
+		return nil, err
+	}
+	return renderInvoiceHTML(document)
+}
+
+// RenderPDF returns the invoice as a PDF document
+func (s *InvoiceService) RenderPDF(invoice *models.Invoice) ([]byte, error) {
+	document, err := s.invoiceDocument(invoice)
+	if err != nil {
+		return nil, err
+	}
+	return renderInvoicePDF(document), nil
+}
+
+// StartMonthlyJob invoices everything up to the previous month right away and
+// then once per interval, separately for every club. Runs are idempotent, so
+// restarts do not create duplicate invoices.
+func (s *InvoiceService) StartMonthlyJob(interval time.Duration) {
+	go func() {
+		for {
+			now := time.Now()
+			previous := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+
+			var clubs []models.Club
+			if err := s.db.Find(&clubs).Error; err != nil {
+				log.Error().Err(err).Msg("monthly invoicing failed to fetch clubs")
+			}
+			for _, club := range clubs {
+				invoices, err := s.WithContext(database.WithClub(context.Background(), club.ID)).GenerateMonthlyInvoices(previous.Year(), int(previous.Month()))
+				if err != nil {
+					log.Error().Err(err).Str("club", club.Slug).Msg("monthly invoicing failed")
+				} else if len(invoices) > 0 {
+					log.Info().Int("invoices", len(invoices)).Str("club", club.Slug).Str("period", previous.Format("01/2006")).Msg("monthly invoices created")
+				}
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LedgerService struct {
+	db *gorm.DB
+}
+
+func NewLedgerService(db *gorm.DB) *LedgerService {
+	return &LedgerService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *LedgerService) WithContext(ctx context.Context) *LedgerService {
+	return &LedgerService{db: s.db.WithContext(ctx)}
+}
+
+// ChargeTrainingSession books the calculated training costs on the players'
+// accounts: the fee as a charge, the share of the session expenses as an
+// expense and money advanced for an expense as a reimbursement. Bookings from
+// an earlier finish of the same session are replaced, so reopening and
+// finishing again does not double-charge. Invoiced sessions are not charged
+// again, their invoices would no longer match the bookings.
+func (s *LedgerService) ChargeTrainingSession(tx *gorm.DB, session *models.TrainingSession, costs *models.TrainingCostsResponse) error {
+	invoiced, err := isSessionInvoiced(tx, session.ID)
+	if err != nil {
+		return err
+	}
+	if invoiced {
+		return fmt.Errorf("training session is already invoiced")
+	}
+
+	if err := tx.Where("training_session_id = ? AND type IN ?", session.ID, sessionEntryTypes).Delete(&models.LedgerEntry{}).Error; err != nil {
+		return fmt.Errorf("failed to remove previous training charges: %w", err)
+	}
+
+	description := fmt.Sprintf("Training %s (%s)", session.Name, session.TrainingDate.Format("02.01.2006"))
+
+	for _, cost := range costs.PlayerCosts {
+		// Guests pay on the spot and have no account
+		if cost.IsGuest {
+			continue
+		}
+
+		if cost.Fee.IsPositive() {
+			if err := s.createSessionEntry(tx, cost.PlayerID, session.ID, "charge", cost.Fee, description); err != nil {
+				return err
+			}
+		}
+		if cost.ExpenseShare.IsPositive() {
+			if err := s.createSessionEntry(tx, cost.PlayerID, session.ID, "expense", cost.ExpenseShare, "Umlage "+description); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, expense := range costs.Expenses {
+		// Expenses paid from the club cash box need no reimbursement
+		if expense.PaidBy == nil || !expense.Amount.IsPositive() {
+			continue
+		}
+		if err := s.createSessionEntry(tx, *expense.PaidBy, session.ID, "reimbursement", expense.Amount.Neg(), "Auslage "+expense.Description); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sessionEntryTypes are the ledger entry types booked when a training is finished
+var sessionEntryTypes = []string{"charge", "expense", "reimbursement"}
+
+// isSessionInvoiced reports whether the charges of the session are on an invoice
+func isSessionInvoiced(tx *gorm.DB, sessionID uuid.UUID) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.InvoiceLine{}).Where("training_session_id = ?", sessionID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check invoices: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (s *LedgerService) createSessionEntry(tx *gorm.DB, playerID, sessionID uuid.UUID, entryType string, amount models.Money, description string) error {
+	entry := &models.LedgerEntry{
+		PlayerID:          playerID,
+		TrainingSessionID: &sessionID,
+		Type:              entryType,
+		Amount:            amount,
+		Description:       description,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create training %s: %w", entryType, err)
+	}
+	return nil
+}
+
+// RecordPayment books a payment received by the treasurer
+func (s *LedgerService) RecordPayment(playerID uuid.UUID, req *models.PaymentCreateRequest, recordedBy *string) (*models.LedgerEntry, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("payment amount must be positive")
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	description := "Zahlung"
+	if req.Description != nil && *req.Description != "" {
+		description = *req.Description
+	}
+
+	entry := &models.LedgerEntry{
+		PlayerID:    playerID,
+		Type:        "payment",
+		Amount:      req.Amount.Neg(),
+		Description: description,
+		Reference:   req.Reference,
+		RecordedBy:  recordedBy,
+	}
+
+	if err := s.db.Create(entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (s *LedgerService) GetPlayerBalance(playerID uuid.UUID) (*models.PlayerBalanceResponse, error) {
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	var entries []models.LedgerEntry
+	if err := s.db.Where("player_id = ?", playerID).Order("created_at").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+
+	response := &models.PlayerBalanceResponse{
+		PlayerID:     player.ID,
+		PlayerName:   player.Name,
+		TotalCharged: models.NewMoney(0, models.DefaultCurrency),
+		TotalPaid:    models.NewMoney(0, models.DefaultCurrency),
+		Balance:      models.NewMoney(0, models.DefaultCurrency),
+		Entries:      make([]models.LedgerEntryResponse, len(entries)),
+	}
+
+	for i, entry := range entries {
+		if entry.Amount.IsPositive() {
+			response.TotalCharged = response.TotalCharged.Add(entry.Amount)
+		} else {
+			response.TotalPaid = response.TotalPaid.Sub(entry.Amount)
+		}
+		response.Balance = response.Balance.Add(entry.Amount)
+		response.Entries[i] = entry.ToResponse()
+	}
+
+	return response, nil
+}
+
+// GetOutstandingDebts returns every player with a positive balance, highest debt first
+func (s *LedgerService) GetOutstandingDebts() (*models.OutstandingDebtsResponse, error) {
+	var rows []struct {
+		PlayerID     uuid.UUID
+		PlayerName   string
+		Email        string
+		BalanceCents int64
+		Currency     string
+	}
+	err := s.db.Model(&models.LedgerEntry{}).
+		Select("ledger_entries.player_id, players.name AS player_name, players.email, SUM(ledger_entries.amount_cents) AS balance_cents, ledger_entries.amount_currency AS currency").
+		Joins("JOIN players ON players.id = ledger_entries.player_id").
+		Group("ledger_entries.player_id, players.name, players.email, ledger_entries.amount_currency").
+		Having("SUM(ledger_entries.amount_cents) > 0").
+		Order("balance_cents DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch outstanding debts: %w", err)
+	}
+
+	response := &models.OutstandingDebtsResponse{
+		Debts:            make([]models.OutstandingDebt, len(rows)),
+		TotalOutstanding: models.NewMoney(0, models.DefaultCurrency),
+	}
+	for i, row := range rows {
+		response.Debts[i] = models.OutstandingDebt{
+			PlayerID:   row.PlayerID,
+			PlayerName: row.PlayerName,
+			Email:      row.Email,
+			Balance:    models.NewMoney(row.BalanceCents, row.Currency),
+		}
+		response.TotalOutstanding = response.TotalOutstanding.Add(response.Debts[i].Balance)
+	}
+
+	return response, nil
+}
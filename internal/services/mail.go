@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"darts-training-app/internal/config"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Mail is a plain text message to a single recipient
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MailSender delivers mails. LogMailSender and FileMailSender stand in for a
+// mail server during development, SMTPMailSender delivers them for real.
+type MailSender interface {
+	Send(mail Mail) error
+}
+
+// NewMailSender returns the sender selected with MAIL_SENDER
+func NewMailSender(cfg *config.Config) (MailSender, error) {
+	switch cfg.MailSender {
+	case "log":
+		return NewLogMailSender(), nil
+	case "file":
+		return NewFileMailSender(cfg.MailDir, cfg.MailFrom)
+	case "smtp":
+		return NewSMTPMailSender(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown mail sender: %s", cfg.MailSender)
+	}
+}
+
+// LogMailSender writes mails to the application log
+type LogMailSender struct{}
+
+func NewLogMailSender() *LogMailSender {
+	return &LogMailSender{}
+}
+
+func (s *LogMailSender) Send(mail Mail) error {
+	log.Info().
+		Str("to", mail.To).
+		Str("subject", mail.Subject).
+		Msg(mail.Body)
+	return nil
+}
+
+// unsafeFileChars are replaced in the recipient part of mail file names
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// FileMailSender stores each mail as .eml file that mail clients can open
+type FileMailSender struct {
+	dir  string
+	from string
+}
+
+// NewFileMailSender creates dir if it does not exist yet
+func NewFileMailSender(dir string, from string) (*FileMailSender, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailSender{dir: dir, from: from}, nil
+}
+
+func (s *FileMailSender) Send(mail Mail) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s-%s.eml", now.Format("20060102-150405"), unsafeFileChars.ReplaceAllString(mail.To, "_"), uuid.NewString()[:8])
+	if err := os.WriteFile(filepath.Join(s.dir, name), buildMessage(s.from, mail, now), 0o640); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	return nil
+}
+
+// SMTPMailSender delivers mails via an SMTP server, with STARTTLS if offered
+type SMTPMailSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailSender(cfg *config.Config) *SMTPMailSender {
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return &SMTPMailSender{
+		addr: net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		auth: auth,
+		from: cfg.MailFrom,
+	}
+}
+
+func (s *SMTPMailSender) Send(mail Mail) error {
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{mail.To}, buildMessage(s.from, mail, time.Now())); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// buildMessage formats a UTF-8 plain text mail as RFC 5322 message
+func buildMessage(from string, mail Mail, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(mail.Body)
+	return buf.Bytes()
+}
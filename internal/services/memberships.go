@@ -0,0 +1,461 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// dateLayout is the format of calendar days in requests and date columns
+const dateLayout = "2006-01-02"
+
+// today returns the current calendar day
+func today() time.Time {
+	return dayOf(time.Now())
+}
+
+// dayOf returns the calendar day of t in its own location
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseDay parses a calendar day, field names the request field for the error
+func parseDay(field, value string) (time.Time, error) {
+	day, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s, use YYYY-MM-DD", field)
+	}
+	return day, nil
+}
+
+// membershipsActiveOn limits team memberships to those valid on day
+func membershipsActiveOn(day time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		value := day.Format(dateLayout)
+		return db.Where("team_memberships.valid_from <= ? AND (team_memberships.valid_until IS NULL OR team_memberships.valid_until >= ?)", value, value)
+	}
+}
+
+// membershipsOverlapping limits team memberships to those sharing a day with
+// the period from until until, an open period has no end
+func membershipsOverlapping(from time.Time, until *time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("(team_memberships.valid_until IS NULL OR team_memberships.valid_until >= ?)", from.Format(dateLayout))
+		if until != nil {
+			db = db.Where("team_memberships.valid_from <= ?", until.Format(dateLayout))
+		}
+		return db
+	}
+}
+
+// syncPlayerTeams sets players.team_id to the team of the membership valid
+// today, regular members before reserves and newer memberships before older
+// ones. Without player IDs all players are updated.
+func syncPlayerTeams(db *gorm.DB, playerIDs ...uuid.UUID) (int64, error) {
+	day := today().Format(dateLayout)
+	query := `UPDATE players SET team_id = current.team_id
+		FROM (SELECT players.id, (
+			SELECT team_memberships.team_id FROM team_memberships
+			WHERE team_memberships.player_id = players.id
+			AND team_memberships.valid_from <= ? AND (team_memberships.valid_until IS NULL OR team_memberships.valid_until >= ?)
+			ORDER BY team_memberships.role = ?, team_memberships.valid_from DESC
+			LIMIT 1) AS team_id
+			FROM players) AS current
+		WHERE current.id = players.id AND players.team_id IS DISTINCT FROM current.team_id`
+	args := []interface{}{day, day, models.TeamRoleReserve}
+	if len(playerIDs) > 0 {
+		query += " AND players.id IN ?"
+		args = append(args, playerIDs)
+	}
+
+	update := db.Exec(query, args...)
+	if update.Error != nil {
+		return 0, fmt.Errorf("failed to update player teams: %w", update.Error)
+	}
+	return update.RowsAffected, nil
+}
+
+// isTeamCaptain reports whether the player is captain or vice captain of the
+// team on one of the days
+func isTeamCaptain(db *gorm.DB, playerID, teamID uuid.UUID, days ...time.Time) (bool, error) {
+	for _, day := range days {
+		var captaincies int64
+		if err := db.Model(&models.TeamMembership{}).
+			Where("player_id = ? AND team_id = ? AND role IN ?", playerID, teamID, []string{models.TeamRoleCaptain, models.TeamRoleViceCaptain}).
+			Scopes(membershipsActiveOn(day)).
+			Count(&captaincies).Error; err != nil {
+			return false, fmt.Errorf("failed to fetch team memberships: %w", err)
+		}
+		if captaincies > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// joinTeam adds the player to the team from today on
+func joinTeam(tx *gorm.DB, playerID, teamID uuid.UUID) error {
+	membership := &models.TeamMembership{
+		TeamID:    teamID,
+		PlayerID:  playerID,
+		Role:      models.TeamRolePlayer,
+		ValidFrom: today(),
+	}
+	if err := checkMembershipOverlap(tx, membership); err != nil {
+		return err
+	}
+	if err := tx.Create(membership).Error; err != nil {
+		return fmt.Errorf("failed to create team membership: %w", err)
+	}
+	return nil
+}
+
+// leaveTeam ends the player's memberships from today on: memberships valid
+// today end yesterday, later ones are removed. Without teamID the player
+// leaves all teams.
+func leaveTeam(tx *gorm.DB, playerID uuid.UUID, teamID *uuid.UUID) error {
+	day := today()
+	memberships := func() *gorm.DB {
+		query := tx.Model(&models.TeamMembership{}).Where("player_id = ?", playerID)
+		if teamID != nil {
+			query = query.Where("team_id = ?", *teamID)
+		}
+		return query
+	}
+
+	if err := memberships().Where("valid_from >= ?", day.Format(dateLayout)).Delete(&models.TeamMembership{}).Error; err != nil {
+		return fmt.Errorf("failed to remove team membership: %w", err)
+	}
+	if err := memberships().Scopes(membershipsActiveOn(day)).Update("valid_until", day.AddDate(0, 0, -1)).Error; err != nil {
+		return fmt.Errorf("failed to end team membership: %w", err)
+	}
+	return nil
+}
+
+// StartDailySync keeps the current team of the players in line with the
+// memberships, which start and end on their own as days pass
+func (s *TeamService) StartDailySync(interval time.Duration) {
+	go func() {
+		for {
+			updated, err := syncPlayerTeams(s.db)
+			if err != nil {
+				log.Error().Err(err).Msg("team sync failed")
+			} else if updated > 0 {
+				log.Info().Int64("players", updated).Msg("player teams updated")
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// GetTeamPlayers returns the roster of the team on date, today if date is
+// empty, or everyone who was a member during the season if seasonID is given
+func (s *TeamService) GetTeamPlayers(id uuid.UUID, date string, seasonID *uuid.UUID) ([]models.TeamMembership, error) {
+	day := today()
+	if date != "" {
+		var err error
+		if day, err = parseDay("date", date); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.findTeam(id); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Preload("Player").
+		Where("team_id = ?", id).
+		Where("player_id IN (SELECT id FROM players WHERE deleted_at IS NULL)")
+	if seasonID != nil {
+		season, err := findSeason(s.db, *seasonID)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Scopes(membershipsOverlapping(season.StartsOn, &season.EndsOn))
+	} else {
+		query = query.Scopes(membershipsActiveOn(day))
+	}
+
+	var memberships []models.TeamMembership
+	if err := query.Order("valid_from").Find(&memberships).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch team players: %w", err)
+	}
+
+	sort.SliceStable(memberships, func(i, j int) bool {
+		return memberships[i].Player.Name < memberships[j].Player.Name
+	})
+	return memberships, nil
+}
+
+// GetTeamMemberships returns the membership history of a team, newest first
+func (s *TeamService) GetTeamMemberships(teamID uuid.UUID) ([]models.TeamMembership, error) {
+	if _, err := s.findTeam(teamID); err != nil {
+		return nil, err
+	}
+
+	var memberships []models.TeamMembership
+	err := s.db.Preload("Player").Preload("Season").
+		Where("team_id = ?", teamID).
+		Order("valid_from DESC").Order("created_at DESC").
+		Find(&memberships).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch team memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// GetPlayerMemberships returns the teams a player belonged to, newest first
+func (s *TeamService) GetPlayerMemberships(playerID uuid.UUID) ([]models.TeamMembership, error) {
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	var memberships []models.TeamMembership
+	err := s.db.Preload("Team").Preload("Season").
+		Where("player_id = ?", playerID).
+		Order("valid_from DESC").Order("created_at DESC").
+		Find(&memberships).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch team memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+func (s *TeamService) CreateMembership(teamID uuid.UUID, req *models.TeamMembershipCreateRequest) (*models.TeamMembership, error) {
+	if _, err := s.findTeam(teamID); err != nil {
+		return nil, err
+	}
+
+	playerID, err := uuid.Parse(req.PlayerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid player ID format")
+	}
+	var player models.Player
+	if err := s.db.First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	membership := &models.TeamMembership{
+		TeamID:    teamID,
+		PlayerID:  playerID,
+		Role:      models.TeamRolePlayer,
+		ValidFrom: today(),
+	}
+	if req.Role != nil {
+		membership.Role = *req.Role
+	}
+
+	var season *models.Season
+	if req.SeasonID != nil && *req.SeasonID != "" {
+		seasonID, err := uuid.Parse(*req.SeasonID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid season ID format")
+		}
+		if season, err = findSeason(s.db, seasonID); err != nil {
+			return nil, err
+		}
+		membership.SeasonID = &season.ID
+		membership.ValidFrom = season.StartsOn
+		membership.ValidUntil = &season.EndsOn
+	}
+
+	if req.ValidFrom != nil {
+		if membership.ValidFrom, err = parseDay("valid_from", *req.ValidFrom); err != nil {
+			return nil, err
+		}
+	}
+	if req.ValidUntil != nil {
+		membership.ValidUntil = nil
+		if *req.ValidUntil != "" {
+			validUntil, err := parseDay("valid_until", *req.ValidUntil)
+			if err != nil {
+				return nil, err
+			}
+			membership.ValidUntil = &validUntil
+		}
+	}
+
+	if err := validateMembership(membership, season); err != nil {
+		return nil, err
+	}
+
+	tx := s.db.Begin()
+
+	if err := checkMembershipOverlap(tx, membership); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Create(membership).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create team membership: %w", err)
+	}
+	if _, err := syncPlayerTeams(tx, playerID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.findMembership(teamID, membership.ID)
+}
+
+func (s *TeamService) UpdateMembership(teamID, id uuid.UUID, req *models.TeamMembershipUpdateRequest) (*models.TeamMembership, error) {
+	membership, err := s.findMembership(teamID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role != nil {
+		membership.Role = *req.Role
+	}
+	if req.ValidFrom != nil {
+		if membership.ValidFrom, err = parseDay("valid_from", *req.ValidFrom); err != nil {
+			return nil, err
+		}
+	}
+	if req.ValidUntil != nil {
+		membership.ValidUntil = nil
+		if *req.ValidUntil != "" {
+			validUntil, err := parseDay("valid_until", *req.ValidUntil)
+			if err != nil {
+				return nil, err
+			}
+			membership.ValidUntil = &validUntil
+		}
+	}
+
+	if err := validateMembership(membership, membership.Season); err != nil {
+		return nil, err
+	}
+
+	tx := s.db.Begin()
+
+	if err := checkMembershipOverlap(tx, membership); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Model(membership).Select("role", "valid_from", "valid_until").Updates(membership).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update team membership: %w", err)
+	}
+	if _, err := syncPlayerTeams(tx, membership.PlayerID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.findMembership(teamID, id)
+}
+
+// DeleteMembership removes a membership entered by mistake. Players leaving
+// the team keep their membership with an end date.
+func (s *TeamService) DeleteMembership(teamID, id uuid.UUID) error {
+	membership, err := s.findMembership(teamID, id)
+	if err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+
+	if err := tx.Delete(&models.TeamMembership{}, "id = ?", membership.ID).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete team membership: %w", err)
+	}
+	if _, err := syncPlayerTeams(tx, membership.PlayerID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *TeamService) findTeam(id uuid.UUID) (*models.Team, error) {
+	var team models.Team
+	if err := s.db.First(&team, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("team not found")
+		}
+		return nil, fmt.Errorf("failed to fetch team: %w", err)
+	}
+	return &team, nil
+}
+
+func (s *TeamService) findMembership(teamID, id uuid.UUID) (*models.TeamMembership, error) {
+	var membership models.TeamMembership
+	err := s.db.Preload("Player").Preload("Season").
+		Where("team_id = ?", teamID).
+		First(&membership, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("membership not found")
+		}
+		return nil, fmt.Errorf("failed to fetch team membership: %w", err)
+	}
+	return &membership, nil
+}
+
+func validateMembership(membership *models.TeamMembership, season *models.Season) error {
+	if !models.IsValidTeamRole(membership.Role) {
+		return fmt.Errorf("invalid team role: %s", membership.Role)
+	}
+	if membership.ValidUntil != nil && membership.ValidUntil.Before(membership.ValidFrom) {
+		return fmt.Errorf("membership must not end before it starts")
+	}
+	if season != nil && (!season.Contains(membership.ValidFrom) || membership.ValidUntil == nil || !season.Contains(*membership.ValidUntil)) {
+		return fmt.Errorf("membership must lie within the season")
+	}
+	return nil
+}
+
+// checkMembershipOverlap rejects a second membership of the player in the
+// same team and a second captain or vice captain for the same days
+func checkMembershipOverlap(tx *gorm.DB, membership *models.TeamMembership) error {
+	others := func() *gorm.DB {
+		query := tx.Model(&models.TeamMembership{}).
+			Where("team_id = ?", membership.TeamID).
+			Scopes(membershipsOverlapping(membership.ValidFrom, membership.ValidUntil))
+		if membership.ID != uuid.Nil {
+			query = query.Where("id <> ?", membership.ID)
+		}
+		return query
+	}
+
+	var count int64
+	if err := others().Where("player_id = ?", membership.PlayerID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check team memberships: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("player is already a member of the team in this period")
+	}
+
+	if membership.Role == models.TeamRoleCaptain || membership.Role == models.TeamRoleViceCaptain {
+		if err := others().Where("role = ?", membership.Role).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check team memberships: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("team already has a %s in this period", strings.ReplaceAll(membership.Role, "_", " "))
+		}
+	}
+	return nil
+}
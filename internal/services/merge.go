@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MergeService cleans up duplicate player records: it merges a second player
+// record of the same person, or the guest profile of a person who joined the
+// club, into a player
+type MergeService struct {
+	db *gorm.DB
+}
+
+func NewMergeService(db *gorm.DB) *MergeService {
+	return &MergeService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *MergeService) WithContext(ctx context.Context) *MergeService {
+	return &MergeService{db: s.db.WithContext(ctx)}
+}
+
+// MergeIntoPlayer moves everything referring to the source player or guest
+// to the target player in one transaction. A dry run performs the same
+// changes and rolls them back, so the counts match a real merge.
+func (s *MergeService) MergeIntoPlayer(targetID uuid.UUID, req *models.PlayerMergeRequest, actor *string) (*models.PlayerMergeResult, error) {
+	hasSource := req.SourcePlayerID != nil && *req.SourcePlayerID != ""
+	hasGuest := req.GuestID != nil && *req.GuestID != ""
+	if hasSource == hasGuest {
+		return nil, fmt.Errorf("either source_player_id or guest_id is required")
+	}
+
+	tx := s.db.Begin()
+
+	var target models.Player
+	if err := tx.First(&target, "id = ?", targetID).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if target.AnonymizedAt != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("cannot merge into an anonymized player")
+	}
+
+	result := &models.PlayerMergeResult{
+		TargetPlayerID: target.ID,
+		DryRun:         req.DryRun,
+	}
+
+	var err error
+	if hasSource {
+		sourceID, parseErr := uuid.Parse(*req.SourcePlayerID)
+		if parseErr != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("invalid source player ID format")
+		}
+		result.SourcePlayerID = &sourceID
+		err = s.mergePlayer(tx, &target, sourceID, result, actor)
+	} else {
+		guestID, parseErr := uuid.Parse(*req.GuestID)
+		if parseErr != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("invalid guest ID format")
+		}
+		result.GuestID = &guestID
+		err = mergeGuest(tx, &target, guestID, result, actor)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if req.DryRun {
+		if err := tx.Rollback().Error; err != nil {
+			return nil, fmt.Errorf("failed to roll back dry run: %w", err)
+		}
+		return result, nil
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// mergePlayer moves the records of the source player to the target and
+// deletes the source. Deleted source players can be merged as well.
+func (s *MergeService) mergePlayer(tx *gorm.DB, target *models.Player, sourceID uuid.UUID, result *models.PlayerMergeResult, actor *string) error {
+	if sourceID == target.ID {
+		return fmt.Errorf("cannot merge a player into itself")
+	}
+
+	var source models.Player
+	if err := tx.Unscoped().First(&source, "id = ?", sourceID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("source player not found")
+		}
+		return fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if source.Auth0UserID != nil && target.Auth0UserID != nil {
+		return fmt.Errorf("both players are linked to an account")
+	}
+
+	// Two players in the same training are two people
+	var shared int64
+	if err := tx.Table("training_players AS merged").
+		Joins("JOIN training_players AS kept ON kept.training_session_id = merged.training_session_id").
+		Where("merged.player_id = ? AND kept.player_id = ?", source.ID, target.ID).
+		Count(&shared).Error; err != nil {
+		return fmt.Errorf("failed to check shared training sessions: %w", err)
+	}
+	if shared > 0 {
+		return fmt.Errorf("players took part in the same training session")
+	}
+
+	var mandates int64
+	if err := tx.Model(&models.SepaMandate{}).Where("player_id IN ?", []uuid.UUID{source.ID, target.ID}).Count(&mandates).Error; err != nil {
+		return fmt.Errorf("failed to check mandates: %w", err)
+	}
+	if mandates > 1 {
+		return fmt.Errorf("both players have a SEPA mandate")
+	}
+
+	// Two members of the same team at the same time are two people
+	var teammates int64
+	if err := tx.Table("team_memberships AS merged").
+		Joins("JOIN team_memberships AS kept ON kept.team_id = merged.team_id").
+		Where("merged.player_id = ? AND kept.player_id = ?", source.ID, target.ID).
+		Where("merged.valid_from <= COALESCE(kept.valid_until, 'infinity'::date) AND kept.valid_from <= COALESCE(merged.valid_until, 'infinity'::date)").
+		Count(&teammates).Error; err != nil {
+		return fmt.Errorf("failed to check team memberships: %w", err)
+	}
+	if teammates > 0 {
+		return fmt.Errorf("players were members of the same team at the same time")
+	}
+
+	moves := []struct {
+		count *int64
+		model interface{}
+		where string
+		set   map[string]interface{}
+	}{
+		{&result.TrainingPlayers, &models.TrainingPlayer{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.CreatedSessions, &models.TrainingSession{}, "created_by = ?", map[string]interface{}{"created_by": target.ID}},
+		{&result.LedgerEntries, &models.LedgerEntry{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.Expenses, &models.SessionExpense{}, "paid_by = ?", map[string]interface{}{"paid_by": target.ID}},
+		{&result.Invoices, &models.Invoice{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.BankTransactions, &models.BankTransaction{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.Mandates, &models.SepaMandate{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.SepaDebits, &models.SepaCollectionItem{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.Invitations, &models.Invitation{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.TeamMemberships, &models.TeamMembership{}, "player_id = ?", map[string]interface{}{"player_id": target.ID}},
+		{&result.Guests, &models.Guest{}, "invited_by = ?", map[string]interface{}{"invited_by": target.ID}},
+	}
+	for _, move := range moves {
+		// Deleted sessions keep their creator as well
+		update := tx.Unscoped().Model(move.model).Where(move.where, source.ID).UpdateColumns(move.set)
+		if update.Error != nil {
+			return fmt.Errorf("failed to move records: %w", update.Error)
+		}
+		*move.count = update.RowsAffected
+	}
+
+	games1 := tx.Model(&models.TrainingGame{}).Where("player1_id = ?", source.ID).Update("player1_id", target.ID)
+	if games1.Error != nil {
+		return fmt.Errorf("failed to move games: %w", games1.Error)
+	}
+	games2 := tx.Model(&models.TrainingGame{}).Where("player2_id = ?", source.ID).Update("player2_id", target.ID)
+	if games2.Error != nil {
+		return fmt.Errorf("failed to move games: %w", games2.Error)
+	}
+	partners := tx.Model(&models.TrainingGame{}).Where("partner1_id = ?", source.ID).Update("partner1_id", target.ID)
+	if partners.Error != nil {
+		return fmt.Errorf("failed to move games: %w", partners.Error)
+	}
+	result.Games = games1.RowsAffected + games2.RowsAffected + partners.RowsAffected
+
+	converted := tx.Model(&models.Guest{}).Where("converted_player_id = ?", source.ID).UpdateColumn("converted_player_id", target.ID)
+	if converted.Error != nil {
+		return fmt.Errorf("failed to move converted guests: %w", converted.Error)
+	}
+	result.Guests += converted.RowsAffected
+
+	// The target keeps its profile and takes over what only the source has
+	updates := map[string]interface{}{}
+	if target.Nickname == nil && source.Nickname != nil {
+		updates["nickname"] = source.Nickname
+	}
+
+	// The login moves before the source is deleted, it is unique per club
+	if source.Auth0UserID != nil {
+		if err := tx.Unscoped().Model(&source).Update("auth0_user_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to unlink source player: %w", err)
+		}
+		updates["auth0_user_id"] = *source.Auth0UserID
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(target).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update player: %w", err)
+		}
+	}
+
+	if err := tx.Unscoped().Delete(&source).Error; err != nil {
+		return fmt.Errorf("failed to delete source player: %w", err)
+	}
+	if _, err := syncPlayerTeams(tx, target.ID); err != nil {
+		return err
+	}
+
+	return recordAudit(tx, &models.AuditLog{
+		ClubID:     target.ClubID,
+		Action:     models.AuditPlayerMerged,
+		EntityType: "player",
+		EntityID:   target.ID,
+		Actor:      actor,
+		Details: fmt.Sprintf("merged player %s: %d trainings, %d games, %d ledger entries, %d invoices",
+			source.ID, result.TrainingPlayers, result.Games, result.LedgerEntries, result.Invoices),
+	})
+}
+
+// mergeGuest assigns the training entries and games of a guest to the target
+// player and marks the guest as converted
+func mergeGuest(tx *gorm.DB, target *models.Player, guestID uuid.UUID, result *models.PlayerMergeResult, actor *string) error {
+	var guest models.Guest
+	if err := tx.First(&guest, "id = ?", guestID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("guest not found")
+		}
+		return fmt.Errorf("failed to fetch guest: %w", err)
+	}
+	if guest.ConvertedPlayerID != nil {
+		return fmt.Errorf("guest is already converted")
+	}
+
+	var shared int64
+	if err := tx.Table("training_players AS guest").
+		Joins("JOIN training_players AS kept ON kept.training_session_id = guest.training_session_id").
+		Where("guest.guest_id = ? AND guest.is_guest = ? AND kept.player_id = ?", guest.ID, true, target.ID).
+		Count(&shared).Error; err != nil {
+		return fmt.Errorf("failed to check shared training sessions: %w", err)
+	}
+	if shared > 0 {
+		return fmt.Errorf("guest and player took part in the same training session")
+	}
+
+	// The guest ID stays on the rows as history of the guest's visits
+	trainingPlayers := tx.Model(&models.TrainingPlayer{}).
+		Where("guest_id = ? AND is_guest = ?", guest.ID, true).
+		UpdateColumns(map[string]interface{}{"player_id": target.ID, "is_guest": false, "guest_name": nil})
+	if trainingPlayers.Error != nil {
+		return fmt.Errorf("failed to convert guest attendance: %w", trainingPlayers.Error)
+	}
+	result.TrainingPlayers = trainingPlayers.RowsAffected
+
+	games1 := tx.Model(&models.TrainingGame{}).
+		Where("guest1_id = ? AND player1_id IS NULL", guest.ID).
+		UpdateColumns(map[string]interface{}{"player1_id": target.ID, "guest1_name": nil})
+	if games1.Error != nil {
+		return fmt.Errorf("failed to convert guest games: %w", games1.Error)
+	}
+	games2 := tx.Model(&models.TrainingGame{}).
+		Where("guest2_id = ? AND player2_id IS NULL", guest.ID).
+		UpdateColumns(map[string]interface{}{"player2_id": target.ID, "guest2_name": nil})
+	if games2.Error != nil {
+		return fmt.Errorf("failed to convert guest games: %w", games2.Error)
+	}
+	result.Games = games1.RowsAffected + games2.RowsAffected
+
+	now := time.Now()
+	if err := tx.Model(&guest).Updates(map[string]interface{}{
+		"converted_player_id": target.ID,
+		"converted_at":        now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update guest: %w", err)
+	}
+
+	return recordAudit(tx, &models.AuditLog{
+		ClubID:     target.ClubID,
+		Action:     models.AuditGuestConverted,
+		EntityType: "player",
+		EntityID:   target.ID,
+		Actor:      actor,
+		Details:    fmt.Sprintf("converted guest %s: %d trainings, %d games", guest.ID, result.TrainingPlayers, result.Games),
+	})
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Notification is an event addressed to a single player
+type Notification struct {
+	Type              string            `json:"type"` // e.g. training_cancelled
+	PlayerID          uuid.UUID         `json:"player_id"`
+	Email             string            `json:"email"`
+	Name              string            `json:"name"`
+	TrainingSessionID *uuid.UUID        `json:"training_session_id,omitempty"`
+	Subject           string            `json:"subject"`
+	Message           string            `json:"message"`
+	Data              map[string]string `json:"data,omitempty"`
+}
+
+// Notifier delivers notifications to players. Implementations may send mails,
+// push messages or forward the event to another system.
+type Notifier interface {
+	Notify(notification Notification) error
+}
+
+// LogNotifier writes notifications to the application log; it is the default
+// when no other delivery channel is configured.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(notification Notification) error {
+	log.Info().
+		Str("type", notification.Type).
+		Str("player_id", notification.PlayerID.String()).
+		Str("email", notification.Email).
+		Str("subject", notification.Subject).
+		Msg(notification.Message)
+	return nil
+}
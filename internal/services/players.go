@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"darts-training-app/internal/models"
 
 	"github.com/google/uuid"
@@ -18,6 +20,11 @@ func NewPlayerService(db *gorm.DB) *PlayerService {
 	}
 }
 
+// WithContext returns the service bound to ctx
+func (s *PlayerService) WithContext(ctx context.Context) *PlayerService {
+	return &PlayerService{db: s.db.WithContext(ctx)}
+}
+
 func (s *PlayerService) GetAllPlayers() ([]models.Player, error) {
 	var players []models.Player
 	err := s.db.Preload("Team").Find(&players).Error
@@ -90,17 +97,35 @@ func (s *PlayerService) CreatePlayer(req *models.PlayerCreateRequest) (*models.P
 		teamID = &parsedTeamID
 	}
 
-	player := &models.Player{
-		Name:      req.Name,
-		Email:     req.Email,
-		Nickname:  req.Nickname,
-		IsCaptain: req.IsCaptain,
-		IsActive:  req.IsActive,
-		TeamID:    teamID,
+	category := "regular"
+	if req.Category != nil {
+		if !isValidPlayerCategory(*req.Category) {
+			return nil, fmt.Errorf("invalid player category: %s", *req.Category)
+		}
+		category = *req.Category
 	}
 
-	if err := s.db.Create(player).Error; err != nil {
-		return nil, fmt.Errorf("failed to create player: %w", err)
+	player := &models.Player{
+		Name:     req.Name,
+		Email:    req.Email,
+		Nickname: req.Nickname,
+		IsActive: req.IsActive,
+		Category: category,
+		TeamID:   teamID,
+	}
+
+	// The team is recorded as a membership starting today
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(player).Error; err != nil {
+			return fmt.Errorf("failed to create player: %w", err)
+		}
+		if teamID != nil {
+			return joinTeam(tx, player.ID, *teamID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return player, nil
@@ -135,14 +160,18 @@ func (s *PlayerService) UpdatePlayer(id uuid.UUID, req *models.PlayerUpdateReque
 	if req.Nickname != nil {
 		player.Nickname = req.Nickname
 	}
-	if req.IsCaptain != nil {
-		player.IsCaptain = *req.IsCaptain
-	}
 	if req.IsActive != nil {
 		player.IsActive = *req.IsActive
 	}
+	if req.Category != nil {
+		if !isValidPlayerCategory(*req.Category) {
+			return nil, fmt.Errorf("invalid player category: %s", *req.Category)
+		}
+		player.Category = *req.Category
+	}
 
 	// Handle team assignment
+	previousTeamID := player.TeamID
 	if req.TeamID != nil {
 		if *req.TeamID == "" {
 			// Remove from team
@@ -166,13 +195,129 @@ func (s *PlayerService) UpdatePlayer(id uuid.UUID, req *models.PlayerUpdateReque
 		}
 	}
 
-	if err := s.db.Save(&player).Error; err != nil {
-		return nil, fmt.Errorf("failed to update player: %w", err)
+	// Moving the player ends the membership in the old team instead of
+	// overwriting it, so the team history is kept
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&player).Error; err != nil {
+			return fmt.Errorf("failed to update player: %w", err)
+		}
+		if sameTeam(previousTeamID, player.TeamID) {
+			return nil
+		}
+		if previousTeamID != nil {
+			if err := leaveTeam(tx, player.ID, previousTeamID); err != nil {
+				return err
+			}
+		}
+		if player.TeamID != nil {
+			if err := joinTeam(tx, player.ID, *player.TeamID); err != nil {
+				return err
+			}
+		}
+		_, err := syncPlayerTeams(tx, player.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &player, nil
+}
+
+func sameTeam(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Dart weights accepted on a profile, in grams
+const (
+	minDartWeight = 10
+	maxDartWeight = 60
+)
+
+// UpdateProfile changes the self-service fields of a player's own profile
+func (s *PlayerService) UpdateProfile(id uuid.UUID, req *models.PlayerProfileUpdateRequest) (*models.Player, error) {
+	var player models.Player
+	if err := s.db.Preload("Team").First(&player, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	if req.Nickname != nil {
+		player.Nickname = emptyToNil(*req.Nickname)
+	}
+	if req.ThrowingHand != nil {
+		hand := strings.ToLower(strings.TrimSpace(*req.ThrowingHand))
+		if hand != "" && hand != models.ThrowingHandLeft && hand != models.ThrowingHandRight {
+			return nil, fmt.Errorf("invalid throwing hand: %s", *req.ThrowingHand)
+		}
+		player.ThrowingHand = emptyToNil(hand)
+	}
+	if req.DartWeight != nil {
+		if *req.DartWeight < minDartWeight || *req.DartWeight > maxDartWeight {
+			return nil, fmt.Errorf("invalid dart weight: must be between %d and %d grams", minDartWeight, maxDartWeight)
+		}
+		player.DartWeight = req.DartWeight
+	}
+	if req.AvatarURL != nil {
+		player.AvatarURL = emptyToNil(*req.AvatarURL)
+	}
+	if req.NotifyTrainingUpdates != nil {
+		player.NotifyTrainingUpdates = *req.NotifyTrainingUpdates
+	}
+	if req.EmailVisible != nil {
+		player.EmailVisible = *req.EmailVisible
+	}
+	if req.StatsVisible != nil {
+		player.StatsVisible = *req.StatsVisible
+	}
+
+	if err := s.db.Omit("Team").Save(&player).Error; err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
 	}
 
 	return &player, nil
 }
 
+// GetPlayerStats counts the completed games and attended trainings of a player
+func (s *PlayerService) GetPlayerStats(id uuid.UUID) (*models.PlayerStats, error) {
+	var games struct {
+		GamesPlayed int
+		Wins        int
+		Draws       int
+	}
+	err := s.db.Model(&models.TrainingGame{}).
+		Select(`COUNT(*) AS games_played,
+			COUNT(*) FILTER (WHERE ((player1_id = ? OR partner1_id = ?) AND winner = 'player1') OR (player2_id = ? AND winner = 'player2')) AS wins,
+			COUNT(*) FILTER (WHERE winner = 'draw') AS draws`, id, id, id).
+		Where("status = ?", "completed").
+		Where("(player1_id = ? OR partner1_id = ? OR player2_id = ?)", id, id, id).
+		Scan(&games).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player games: %w", err)
+	}
+
+	var sessionsAttended int64
+	if err := s.db.Model(&models.TrainingPlayer{}).
+		Where("player_id = ? AND attended = ?", id, true).
+		Distinct("training_session_id").
+		Count(&sessionsAttended).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch player attendance: %w", err)
+	}
+
+	return &models.PlayerStats{
+		GamesPlayed:      games.GamesPlayed,
+		Wins:             games.Wins,
+		Draws:            games.Draws,
+		Losses:           games.GamesPlayed - games.Wins - games.Draws,
+		SessionsAttended: int(sessionsAttended),
+	}, nil
+}
+
 func (s *PlayerService) UpdatePlayerAuth0ID(id uuid.UUID, auth0UserID string) error {
 	var player models.Player
 	if err := s.db.First(&player, "id = ?", id).Error; err != nil {
@@ -220,10 +365,10 @@ func (s *PlayerService) DeletePlayer(id uuid.UUID) error {
 	// Check for training games where this player participated
 	gameCount := int64(0)
 	s.db.Model(&models.TrainingGame{}).
-		Where("player1_id = ? OR player2_id = ?", id, id).
+		Where("player1_id = ? OR partner1_id = ? OR player2_id = ?", id, id, id).
 		Count(&gameCount)
 	if gameCount > 0 {
-		return fmt.Errorf("cannot delete player who participated in %d games", gameCount)
+		return fmt.Errorf("cannot delete player who participated in %d games, anonymize the player instead", gameCount)
 	}
 
 	if err := s.db.Delete(&player).Error; err != nil {
@@ -334,4 +479,24 @@ func (s *PlayerService) GetActivePlayersByTeam(teamID uuid.UUID) ([]models.Playe
 		return nil, fmt.Errorf("failed to fetch active team players: %w", err)
 	}
 	return players, nil
-}
\ No newline at end of file
+}
+
+// isValidPlayerCategory checks the category used for pricing discounts
+func isValidPlayerCategory(category string) bool {
+	validCategories := []string{"regular", "youth", "student"}
+	for _, valid := range validCategories {
+		if category == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// emptyToNil clears an optional profile field when the value is blank
+func emptyToNil(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PricingService struct {
+	db *gorm.DB
+}
+
+func NewPricingService(db *gorm.DB) *PricingService {
+	return &PricingService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *PricingService) WithContext(ctx context.Context) *PricingService {
+	return &PricingService{db: s.db.WithContext(ctx)}
+}
+
+func (s *PricingService) GetAllPricingPolicies() ([]models.PricingPolicy, error) {
+	var policies []models.PricingPolicy
+	if err := s.db.Order("name").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch pricing policies: %w", err)
+	}
+	return policies, nil
+}
+
+func (s *PricingService) GetPricingPolicyByID(id uuid.UUID) (*models.PricingPolicy, error) {
+	var policy models.PricingPolicy
+	if err := s.db.First(&policy, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("pricing policy not found")
+		}
+		return nil, fmt.Errorf("failed to fetch pricing policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (s *PricingService) CreatePricingPolicy(req *models.PricingPolicyCreateRequest) (*models.PricingPolicy, error) {
+	var existingPolicy models.PricingPolicy
+	err := s.db.Where("name = ?", req.Name).First(&existingPolicy).Error
+	if err == nil {
+		return nil, fmt.Errorf("pricing policy with name '%s' already exists", req.Name)
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check existing pricing policy: %w", err)
+	}
+
+	policy := &models.PricingPolicy{
+		Name:                   req.Name,
+		Description:            req.Description,
+		Mode:                   "per_session",
+		MemberRate:             models.NewMoney(req.MemberRate.Cents, req.MemberRate.Currency),
+		GuestRate:              models.NewMoney(req.GuestRate.Cents, req.GuestRate.Currency),
+		YouthDiscountPercent:   req.YouthDiscountPercent,
+		StudentDiscountPercent: req.StudentDiscountPercent,
+		MonthlyCap:             req.MonthlyCap,
+		FreeFirstGuestSession:  req.FreeFirstGuestSession,
+		RequireGamePlayed:      true,
+	}
+	if req.Mode != nil {
+		if *req.Mode != "per_session" && *req.Mode != "per_game" {
+			return nil, fmt.Errorf("invalid pricing mode: %s", *req.Mode)
+		}
+		policy.Mode = *req.Mode
+	}
+	if req.RequireGamePlayed != nil {
+		policy.RequireGamePlayed = *req.RequireGamePlayed
+	}
+	if err := validatePolicyAmounts(policy); err != nil {
+		return nil, err
+	}
+
+	tx := s.db.Begin()
+
+	if err := tx.Create(policy).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create pricing policy: %w", err)
+	}
+
+	// Zero values are replaced by column defaults on create, so write the flags explicitly
+	if err := tx.Model(policy).Select("RequireGamePlayed", "FreeFirstGuestSession").Updates(policy).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create pricing policy: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (s *PricingService) UpdatePricingPolicy(id uuid.UUID, req *models.PricingPolicyUpdateRequest) (*models.PricingPolicy, error) {
+	policy, err := s.GetPricingPolicyByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && *req.Name != policy.Name {
+		var existingPolicy models.PricingPolicy
+		err := s.db.Where("name = ? AND id != ?", *req.Name, id).First(&existingPolicy).Error
+		if err == nil {
+			return nil, fmt.Errorf("pricing policy with name '%s' already exists", *req.Name)
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to check existing pricing policy: %w", err)
+		}
+		policy.Name = *req.Name
+	}
+
+	if req.Description != nil {
+		policy.Description = req.Description
+	}
+	if req.Mode != nil {
+		if *req.Mode != "per_session" && *req.Mode != "per_game" {
+			return nil, fmt.Errorf("invalid pricing mode: %s", *req.Mode)
+		}
+		policy.Mode = *req.Mode
+	}
+	if req.MemberRate != nil {
+		policy.MemberRate = *req.MemberRate
+	}
+	if req.GuestRate != nil {
+		policy.GuestRate = *req.GuestRate
+	}
+	if req.YouthDiscountPercent != nil {
+		policy.YouthDiscountPercent = *req.YouthDiscountPercent
+	}
+	if req.StudentDiscountPercent != nil {
+		policy.StudentDiscountPercent = *req.StudentDiscountPercent
+	}
+	if req.MonthlyCap != nil {
+		if req.MonthlyCap.IsNegative() {
+			policy.MonthlyCap = nil
+		} else {
+			policy.MonthlyCap = req.MonthlyCap
+		}
+	}
+	if req.FreeFirstGuestSession != nil {
+		policy.FreeFirstGuestSession = *req.FreeFirstGuestSession
+	}
+	if req.RequireGamePlayed != nil {
+		policy.RequireGamePlayed = *req.RequireGamePlayed
+	}
+	if err := validatePolicyAmounts(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Save(policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to update pricing policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (s *PricingService) DeletePricingPolicy(id uuid.UUID) error {
+	policy, err := s.GetPricingPolicyByID(id)
+	if err != nil {
+		return err
+	}
+
+	sessionCount := int64(0)
+	s.db.Model(&models.TrainingSession{}).Where("pricing_policy_id = ?", id).Count(&sessionCount)
+	if sessionCount > 0 {
+		return fmt.Errorf("cannot delete pricing policy used by %d training sessions", sessionCount)
+	}
+
+	if err := s.db.Delete(policy).Error; err != nil {
+		return fmt.Errorf("failed to delete pricing policy: %w", err)
+	}
+
+	return nil
+}
+
+// validatePolicyAmounts ensures all rates are non-negative and share one currency
+func validatePolicyAmounts(policy *models.PricingPolicy) error {
+	amounts := []models.Money{policy.MemberRate, policy.GuestRate}
+	if policy.MonthlyCap != nil {
+		amounts = append(amounts, *policy.MonthlyCap)
+	}
+
+	for _, amount := range amounts {
+		if amount.IsNegative() {
+			return fmt.Errorf("pricing policy amounts must not be negative")
+		}
+		if amount.Currency != policy.MemberRate.Currency {
+			return fmt.Errorf("pricing policy amounts must use the same currency")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PrivacyService answers data subject requests: it exports everything stored
+// about a player and anonymises players who asked to be forgotten
+type PrivacyService struct {
+	db *gorm.DB
+}
+
+func NewPrivacyService(db *gorm.DB) *PrivacyService {
+	return &PrivacyService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *PrivacyService) WithContext(ctx context.Context) *PrivacyService {
+	return &PrivacyService{db: s.db.WithContext(ctx)}
+}
+
+// ExportPlayerData collects the profile, attendance, games, bookings, invoices,
+// bank data and former guest profiles of a player, deleted players included. The export is
+// recorded in the audit log.
+func (s *PrivacyService) ExportPlayerData(playerID uuid.UUID, actor *string) (*models.PlayerDataExport, error) {
+	var player models.Player
+	if err := s.db.Unscoped().Preload("Team").First(&player, "id = ?", playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	export := &models.PlayerDataExport{
+		ExportedAt: time.Now(),
+		Player:     player,
+	}
+
+	var trainingPlayers []models.TrainingPlayer
+	if err := s.db.Preload("TrainingSession").
+		Where("player_id = ?", playerID).
+		Order("created_at").
+		Find(&trainingPlayers).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch attendance: %w", err)
+	}
+	export.Attendance = make([]models.PlayerAttendance, 0, len(trainingPlayers))
+	for _, tp := range trainingPlayers {
+		attendance := models.PlayerAttendance{
+			TrainingSessionID: tp.TrainingSessionID,
+			Attended:          tp.Attended,
+		}
+		if tp.TrainingSession != nil {
+			attendance.SessionName = tp.TrainingSession.Name
+			attendance.TrainingDate = tp.TrainingSession.TrainingDate
+		}
+		export.Attendance = append(export.Attendance, attendance)
+	}
+
+	var memberships []models.TeamMembership
+	if err := s.db.Preload("Team", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Preload("Season").Where("player_id = ?", playerID).Order("valid_from").Find(&memberships).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch team memberships: %w", err)
+	}
+	export.TeamMemberships = make([]models.TeamMembershipResponse, len(memberships))
+	for i, membership := range memberships {
+		export.TeamMemberships[i] = membership.ToResponse()
+	}
+
+	var games []models.TrainingGame
+	if err := s.db.Preload("GameMode").Preload("Player1").Preload("Partner1").Preload("Player2").
+		Where("player1_id = ? OR partner1_id = ? OR player2_id = ?", playerID, playerID, playerID).
+		Order("created_at").
+		Find(&games).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch games: %w", err)
+	}
+	export.Games = make([]models.TrainingGameResponse, len(games))
+	for i, game := range games {
+		export.Games[i] = game.ToResponse()
+	}
+
+	var entries []models.LedgerEntry
+	if err := s.db.Where("player_id = ?", playerID).Order("created_at").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+	export.LedgerEntries = make([]models.LedgerEntryResponse, len(entries))
+	for i, entry := range entries {
+		export.LedgerEntries[i] = entry.ToResponse()
+	}
+
+	var invoices []models.Invoice
+	if err := s.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Where("player_id = ?", playerID).Order("issued_at").Find(&invoices).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch invoices: %w", err)
+	}
+	export.Invoices = make([]models.InvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		export.Invoices[i] = invoice.ToResponse()
+	}
+
+	var mandate models.SepaMandate
+	err := s.db.First(&mandate, "player_id = ?", playerID).Error
+	if err == nil {
+		response := mandate.ToResponse()
+		export.SepaMandate = &response
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to fetch mandate: %w", err)
+	}
+
+	var transactions []models.BankTransaction
+	if err := s.db.Where("player_id = ?", playerID).Order("booking_date").Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch bank transactions: %w", err)
+	}
+	export.BankTransactions = make([]models.BankTransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		export.BankTransactions[i] = transaction.ToResponse()
+	}
+
+	var expenses []models.SessionExpense
+	if err := s.db.Where("paid_by = ?", playerID).Order("created_at").Find(&expenses).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch expenses: %w", err)
+	}
+	export.PaidExpenses = make([]models.SessionExpenseResponse, len(expenses))
+	for i, expense := range expenses {
+		export.PaidExpenses[i] = expense.ToResponse()
+	}
+
+	var guests []models.Guest
+	if err := s.db.Where("converted_player_id = ?", playerID).Order("created_at").Find(&guests).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch guest profiles: %w", err)
+	}
+	export.GuestProfiles = make([]models.GuestResponse, len(guests))
+	for i, guest := range guests {
+		export.GuestProfiles[i] = guest.ToResponse(models.GuestStats{})
+	}
+
+	export.RoleAssignments = []models.RoleAssignment{}
+	if player.Auth0UserID != nil {
+		if err := s.db.Where("subject = ?", *player.Auth0UserID).Find(&export.RoleAssignments).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch role assignments: %w", err)
+		}
+	}
+
+	if err := recordAudit(s.db, &models.AuditLog{
+		ClubID:     player.ClubID,
+		Action:     models.AuditPlayerExported,
+		EntityType: "player",
+		EntityID:   player.ID,
+		Actor:      actor,
+		Details:    fmt.Sprintf("%d games, %d trainings, %d ledger entries, %d invoices", len(export.Games), len(export.Attendance), len(export.LedgerEntries), len(export.Invoices)),
+	}); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// ExportPlayerArchive returns the export as ZIP archive with one JSON file per section
+func (s *PrivacyService) ExportPlayerArchive(playerID uuid.UUID, actor *string) ([]byte, error) {
+	export, err := s.ExportPlayerData(playerID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"player.json", export.Player},
+		{"attendance.json", export.Attendance},
+		{"team_memberships.json", export.TeamMemberships},
+		{"games.json", export.Games},
+		{"ledger_entries.json", export.LedgerEntries},
+		{"invoices.json", export.Invoices},
+		{"sepa_mandate.json", export.SepaMandate},
+		{"bank_transactions.json", export.BankTransactions},
+		{"paid_expenses.json", export.PaidExpenses},
+		{"guest_profiles.json", export.GuestProfiles},
+		{"role_assignments.json", export.RoleAssignments},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// AnonymizePlayer replaces the personal data of a player with a pseudonym.
+// Games, attendance, bookings and invoices keep referring to the player, so
+// standings, statistics and the club accounts stay intact. The SEPA mandate
+// and the player's role assignments are deleted, the sender, IBAN and
+// transfer text of the player's bank transactions are cleared, as are the
+// guest profiles the player was converted from. Deleted players can be
+// anonymised as well.
+func (s *PrivacyService) AnonymizePlayer(playerID uuid.UUID, actor *string) (*models.Player, error) {
+	tx := s.db.Begin()
+
+	var player models.Player
+	if err := tx.Unscoped().First(&player, "id = ?", playerID).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("player not found")
+		}
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player.AnonymizedAt != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("player is already anonymized")
+	}
+
+	// The club must still be able to settle open amounts with the person
+	var balanceCents int64
+	if err := tx.Model(&models.LedgerEntry{}).
+		Where("player_id = ?", playerID).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&balanceCents).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to fetch player balance: %w", err)
+	}
+	if balanceCents != 0 {
+		tx.Rollback()
+		return nil, fmt.Errorf("cannot anonymize player with an open balance")
+	}
+
+	pseudonym := "Ehemaliges Mitglied " + strings.ToUpper(player.ID.String()[:8])
+	now := time.Now()
+	if err := tx.Unscoped().Model(&player).Updates(map[string]interface{}{
+		"name":                    pseudonym,
+		"email":                   fmt.Sprintf("anonymized-%s@invalid", player.ID),
+		"nickname":                nil,
+		"auth0_user_id":           nil,
+		"team_id":                 nil,
+		"is_active":               false,
+		"throwing_hand":           nil,
+		"dart_weight":             nil,
+		"avatar_url":              nil,
+		"notify_training_updates": false,
+		"email_visible":           false,
+		"anonymized_at":           now,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to anonymize player: %w", err)
+	}
+
+	mandates := tx.Where("player_id = ?", playerID).Delete(&models.SepaMandate{})
+	if mandates.Error != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete mandate: %w", mandates.Error)
+	}
+
+	// Bank transactions stay as proof of the payments, without the sender's details
+	transactions := tx.Model(&models.BankTransaction{}).Where("player_id = ?", playerID).Updates(map[string]interface{}{
+		"counterparty":      pseudonym,
+		"counterparty_iban": "",
+		"reference":         "",
+	})
+	if transactions.Error != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to anonymize bank transactions: %w", transactions.Error)
+	}
+
+	// The guest profile keeps counting the visits before the conversion
+	guests := tx.Model(&models.Guest{}).Where("converted_player_id = ?", playerID).Updates(map[string]interface{}{
+		"name":  pseudonym,
+		"email": nil,
+		"phone": nil,
+	})
+	if guests.Error != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to anonymize guest profiles: %w", guests.Error)
+	}
+
+	// Past memberships stay in the team history under the pseudonym
+	if err := leaveTeam(tx, playerID, nil); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Invitations hold the email address the player was invited with
+	invitations := tx.Where("player_id = ?", playerID).Delete(&models.Invitation{})
+	if invitations.Error != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete invitations: %w", invitations.Error)
+	}
+
+	var rolesRemoved int64
+	if player.Auth0UserID != nil {
+		roles := tx.Where("subject = ?", *player.Auth0UserID).Delete(&models.RoleAssignment{})
+		if roles.Error != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to delete role assignments: %w", roles.Error)
+		}
+		rolesRemoved = roles.RowsAffected
+	}
+
+	details := fmt.Sprintf("%d mandates, %d invitations and %d role assignments deleted, %d bank transactions and %d guest profiles anonymized",
+		mandates.RowsAffected, invitations.RowsAffected, rolesRemoved, transactions.RowsAffected, guests.RowsAffected)
+	if err := recordAudit(tx, &models.AuditLog{
+		ClubID:     player.ClubID,
+		Action:     models.AuditPlayerAnonymized,
+		EntityType: "player",
+		EntityID:   player.ID,
+		Actor:      actor,
+		Details:    details,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if err := s.db.Unscoped().First(&player, "id = ?", playerID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	return &player, nil
+}
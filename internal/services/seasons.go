@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SeasonService struct {
+	db *gorm.DB
+}
+
+func NewSeasonService(db *gorm.DB) *SeasonService {
+	return &SeasonService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *SeasonService) WithContext(ctx context.Context) *SeasonService {
+	return &SeasonService{db: s.db.WithContext(ctx)}
+}
+
+// GetSeasons returns the seasons of the club, newest first
+func (s *SeasonService) GetSeasons() ([]models.Season, error) {
+	var seasons []models.Season
+	if err := s.db.Order("starts_on DESC").Find(&seasons).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch seasons: %w", err)
+	}
+	return seasons, nil
+}
+
+func (s *SeasonService) GetSeasonByID(id uuid.UUID) (*models.Season, error) {
+	return findSeason(s.db, id)
+}
+
+func (s *SeasonService) CreateSeason(req *models.SeasonCreateRequest) (*models.Season, error) {
+	startsOn, err := parseDay("starts_on", req.StartsOn)
+	if err != nil {
+		return nil, err
+	}
+	endsOn, err := parseDay("ends_on", req.EndsOn)
+	if err != nil {
+		return nil, err
+	}
+
+	season := &models.Season{
+		Name:     req.Name,
+		StartsOn: startsOn,
+		EndsOn:   endsOn,
+	}
+	if err := s.validateSeason(season); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(season).Error; err != nil {
+		return nil, fmt.Errorf("failed to create season: %w", err)
+	}
+	return season, nil
+}
+
+// UpdateSeason changes name or period. The period must still cover the
+// memberships of the season.
+func (s *SeasonService) UpdateSeason(id uuid.UUID, req *models.SeasonUpdateRequest) (*models.Season, error) {
+	season, err := findSeason(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		season.Name = *req.Name
+	}
+	if req.StartsOn != nil {
+		if season.StartsOn, err = parseDay("starts_on", *req.StartsOn); err != nil {
+			return nil, err
+		}
+	}
+	if req.EndsOn != nil {
+		if season.EndsOn, err = parseDay("ends_on", *req.EndsOn); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validateSeason(season); err != nil {
+		return nil, err
+	}
+
+	var outside int64
+	if err := s.db.Model(&models.TeamMembership{}).
+		Where("season_id = ?", season.ID).
+		Where("(valid_from < ? OR valid_until > ? OR valid_until IS NULL)", season.StartsOn.Format(dateLayout), season.EndsOn.Format(dateLayout)).
+		Count(&outside).Error; err != nil {
+		return nil, fmt.Errorf("failed to check team memberships: %w", err)
+	}
+	if outside > 0 {
+		return nil, fmt.Errorf("season must cover its %d team memberships", outside)
+	}
+
+	if err := s.db.Save(season).Error; err != nil {
+		return nil, fmt.Errorf("failed to update season: %w", err)
+	}
+	return season, nil
+}
+
+// DeleteSeason removes a season without memberships
+func (s *SeasonService) DeleteSeason(id uuid.UUID) error {
+	season, err := findSeason(s.db, id)
+	if err != nil {
+		return err
+	}
+
+	var memberships int64
+	if err := s.db.Model(&models.TeamMembership{}).Where("season_id = ?", season.ID).Count(&memberships).Error; err != nil {
+		return fmt.Errorf("failed to check team memberships: %w", err)
+	}
+	if memberships > 0 {
+		return fmt.Errorf("cannot delete season with %d team memberships", memberships)
+	}
+
+	if err := s.db.Delete(season).Error; err != nil {
+		return fmt.Errorf("failed to delete season: %w", err)
+	}
+	return nil
+}
+
+// validateSeason checks the period and that the name is unique in the club
+func (s *SeasonService) validateSeason(season *models.Season) error {
+	if season.EndsOn.Before(season.StartsOn) {
+		return fmt.Errorf("season must not end before it starts")
+	}
+
+	var existing int64
+	query := s.db.Model(&models.Season{}).Where("name = ?", season.Name)
+	if season.ID != uuid.Nil {
+		query = query.Where("id <> ?", season.ID)
+	}
+	if err := query.Count(&existing).Error; err != nil {
+		return fmt.Errorf("failed to check existing season: %w", err)
+	}
+	if existing > 0 {
+		return fmt.Errorf("season with name '%s' already exists", season.Name)
+	}
+	return nil
+}
+
+func findSeason(db *gorm.DB, id uuid.UUID) (*models.Season, error) {
+	var season models.Season
+	if err := db.First(&season, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("season not found")
+		}
+		return nil, fmt.Errorf("failed to fetch season: %w", err)
+	}
+	return &season, nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const pain008Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.008.001.02"
+
+// sepaCreditor is the club account the direct debits are collected to
+type sepaCreditor struct {
+	Name string
+	IBAN string
+	BIC  string
+	ID   string // Gläubiger-Identifikationsnummer
+}
+
+// loadCreditor returns the creditor configured for the club
+func loadCreditor(db *gorm.DB, clubID uuid.UUID) (sepaCreditor, error) {
+	var club models.Club
+	if err := db.First(&club, "id = ?", clubID).Error; err != nil {
+		return sepaCreditor{}, fmt.Errorf("failed to fetch club: %w", err)
+	}
+	return sepaCreditor{
+		Name: club.CreditorName,
+		IBAN: club.CreditorIBAN,
+		BIC:  club.CreditorBIC,
+		ID:   club.CreditorID,
+	}, nil
+}
+
+// configured reports whether direct debits can be collected to the creditor
+func (c sepaCreditor) configured() bool {
+	return c.Name != "" && c.IBAN != "" && c.ID != ""
+}
+
+// sepaDebit is a single collection from a member's account
+type sepaDebit struct {
+	EndToEndID       string
+	Amount           models.Money
+	MandateReference string
+	MandateSignedAt  time.Time
+	DebtorName       string
+	DebtorIBAN       string
+	DebtorBIC        *string
+	Remittance       string
+	SequenceType     string // FRST, RCUR
+}
+
+type pain008Document struct {
+	XMLName xml.Name                 `xml:"Document"`
+	Xmlns   string                   `xml:"xmlns,attr"`
+	Content pain008CstmrDrctDbtInitn `xml:"CstmrDrctDbtInitn"`
+}
+
+type pain008CstmrDrctDbtInitn struct {
+	GroupHeader  pain008GroupHeader  `xml:"GrpHdr"`
+	PaymentInfos []pain008PaymentInf `xml:"PmtInf"`
+}
+
+type pain008GroupHeader struct {
+	MessageID     string `xml:"MsgId"`
+	CreatedAt     string `xml:"CreDtTm"`
+	NumberOfTxs   int    `xml:"NbOfTxs"`
+	ControlSum    string `xml:"CtrlSum"`
+	InitiatorName string `xml:"InitgPty>Nm"`
+}
+
+type pain008PaymentInf struct {
+	PaymentInfoID    string             `xml:"PmtInfId"`
+	PaymentMethod    string             `xml:"PmtMtd"`
+	NumberOfTxs      int                `xml:"NbOfTxs"`
+	ControlSum       string             `xml:"CtrlSum"`
+	ServiceLevel     string             `xml:"PmtTpInf>SvcLvl>Cd"`
+	LocalInstrument  string             `xml:"PmtTpInf>LclInstrm>Cd"`
+	SequenceType     string             `xml:"PmtTpInf>SeqTp"`
+	CollectionDate   string             `xml:"ReqdColltnDt"`
+	CreditorName     string             `xml:"Cdtr>Nm"`
+	CreditorIBAN     string             `xml:"CdtrAcct>Id>IBAN"`
+	CreditorAgent    pain008Agent       `xml:"CdtrAgt"`
+	ChargeBearer     string             `xml:"ChrgBr"`
+	CreditorSchemeID string             `xml:"CdtrSchmeId>Id>PrvtId>Othr>Id"`
+	SchemeName       string             `xml:"CdtrSchmeId>Id>PrvtId>Othr>SchmeNm>Prtry"`
+	Transactions     []pain008DrctDbtTx `xml:"DrctDbtTxInf"`
+}
+
+type pain008Agent struct {
+	BIC   string          `xml:"FinInstnId>BIC,omitempty"`
+	Other *pain008OtherID `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type pain008OtherID struct {
+	ID string `xml:"Id"`
+}
+
+type pain008Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type pain008DrctDbtTx struct {
+	EndToEndID      string        `xml:"PmtId>EndToEndId"`
+	Amount          pain008Amount `xml:"InstdAmt"`
+	MandateID       string        `xml:"DrctDbtTx>MndtRltdInf>MndtId"`
+	MandateSignedAt string        `xml:"DrctDbtTx>MndtRltdInf>DtOfSgntr"`
+	DebtorAgent     pain008Agent  `xml:"DbtrAgt"`
+	DebtorName      string        `xml:"Dbtr>Nm"`
+	DebtorIBAN      string        `xml:"DbtrAcct>Id>IBAN"`
+	Remittance      string        `xml:"RmtInf>Ustrd"`
+}
+
+// buildPain008 renders a SEPA core direct-debit initiation with one payment
+// information block per sequence type
+func buildPain008(creditor sepaCreditor, messageID string, debits []sepaDebit, collectionDate time.Time, now time.Time) ([]byte, error) {
+	total := models.NewMoney(0, models.DefaultCurrency)
+	bySequence := map[string][]sepaDebit{}
+	for _, debit := range debits {
+		total = total.Add(debit.Amount)
+		bySequence[debit.SequenceType] = append(bySequence[debit.SequenceType], debit)
+	}
+
+	document := pain008Document{
+		Xmlns: pain008Namespace,
+		Content: pain008CstmrDrctDbtInitn{
+			GroupHeader: pain008GroupHeader{
+				MessageID:     messageID,
+				CreatedAt:     now.Format("2006-01-02T15:04:05"),
+				NumberOfTxs:   len(debits),
+				ControlSum:    total.Decimal(),
+				InitiatorName: sepaText(creditor.Name, 70),
+			},
+		},
+	}
+
+	for _, sequenceType := range []string{"FRST", "RCUR"} {
+		group := bySequence[sequenceType]
+		if len(group) == 0 {
+			continue
+		}
+
+		groupTotal := models.NewMoney(0, models.DefaultCurrency)
+		transactions := make([]pain008DrctDbtTx, len(group))
+		for i, debit := range group {
+			groupTotal = groupTotal.Add(debit.Amount)
+			transactions[i] = pain008DrctDbtTx{
+				EndToEndID:      debit.EndToEndID,
+				Amount:          pain008Amount{Currency: debit.Amount.Currency, Value: debit.Amount.Decimal()},
+				MandateID:       debit.MandateReference,
+				MandateSignedAt: debit.MandateSignedAt.Format("2006-01-02"),
+				DebtorAgent:     sepaAgent(debit.DebtorBIC),
+				DebtorName:      sepaText(debit.DebtorName, 70),
+				DebtorIBAN:      debit.DebtorIBAN,
+				Remittance:      sepaText(debit.Remittance, 140),
+			}
+		}
+
+		var creditorBIC *string
+		if creditor.BIC != "" {
+			creditorBIC = &creditor.BIC
+		}
+
+		document.Content.PaymentInfos = append(document.Content.PaymentInfos, pain008PaymentInf{
+			PaymentInfoID:    fmt.Sprintf("%s-%s", messageID, sequenceType),
+			PaymentMethod:    "DD",
+			NumberOfTxs:      len(group),
+			ControlSum:       groupTotal.Decimal(),
+			ServiceLevel:     "SEPA",
+			LocalInstrument:  "CORE",
+			SequenceType:     sequenceType,
+			CollectionDate:   collectionDate.Format("2006-01-02"),
+			CreditorName:     sepaText(creditor.Name, 70),
+			CreditorIBAN:     creditor.IBAN,
+			CreditorAgent:    sepaAgent(creditorBIC),
+			ChargeBearer:     "SLEV",
+			CreditorSchemeID: creditor.ID,
+			SchemeName:       "SEPA",
+			Transactions:     transactions,
+		})
+	}
+
+	output, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render pain.008: %w", err)
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+func sepaAgent(bic *string) pain008Agent {
+	if bic != nil && *bic != "" {
+		return pain008Agent{BIC: *bic}
+	}
+	return pain008Agent{Other: &pain008OtherID{ID: "NOTPROVIDED"}}
+}
+
+// sepaDisallowed matches characters outside the SEPA latin character set
+var sepaDisallowed = regexp.MustCompile(`[^A-Za-z0-9/\-?:().,'+ ]`)
+
+// sepaText transliterates umlauts, drops characters banks reject and truncates to max
+func sepaText(value string, max int) string {
+	replacer := strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "Ä", "Ae", "Ö", "Oe", "Ü", "Ue", "ß", "ss", "&", "+")
+	value = sepaDisallowed.ReplaceAllString(replacer.Replace(value), "")
+	if len(value) > max {
+		value = value[:max]
+	}
+	return value
+}
+
+// normalizeIBAN removes spaces and validates the ISO 13616 mod-97 checksum
+func normalizeIBAN(iban string) (string, error) {
+	iban = strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+	if len(iban) < 15 || len(iban) > 34 {
+		return "", fmt.Errorf("invalid IBAN")
+	}
+
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(fmt.Sprintf("%d", r-'A'+10))
+		default:
+			return "", fmt.Errorf("invalid IBAN")
+		}
+	}
+
+	number, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok || new(big.Int).Mod(number, big.NewInt(97)).Int64() != 1 {
+		return "", fmt.Errorf("invalid IBAN")
+	}
+	return iban, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type sessionTransition struct {
+	from []string
+	to   string
+}
+
+// sessionTransitions is the training session state machine, keyed by action.
+// Every status change of a training session has to go through one of these.
+var sessionTransitions = map[string]sessionTransition{
+	"start":      {from: []string{"planned"}, to: "active"},
+	"finish":     {from: []string{"active"}, to: "completed"},
+	"cancel":     {from: []string{"planned", "postponed"}, to: "cancelled"},
+	"postpone":   {from: []string{"planned"}, to: "postponed"},
+	"reschedule": {from: []string{"postponed"}, to: "planned"},
+	"reopen":     {from: []string{"completed"}, to: "active"},
+}
+
+// TransitionTrainingSession applies a state machine action to a training session and records it in the history.
+// Finishing a training charges the calculated costs to the players' accounts,
+// invoiced trainings cannot be reopened or finished again.
+func (s *TrainingService) TransitionTrainingSession(id uuid.UUID, action string, actor *string, req *models.TrainingSessionTransitionRequest) (*models.TrainingSession, error) {
+	var session models.TrainingSession
+	if err := s.db.First(&session, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("training session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch training session: %w", err)
+	}
+
+	var reason *string
+	if req != nil {
+		reason = req.Reason
+		if action == "reschedule" && req.TrainingDate != nil {
+			session.TrainingDate = *req.TrainingDate
+		}
+	}
+
+	if action == "reopen" || action == "finish" {
+		invoiced, err := isSessionInvoiced(s.db, id)
+		if err != nil {
+			return nil, err
+		}
+		if invoiced {
+			return nil, fmt.Errorf("cannot %s training session that is already invoiced", action)
+		}
+	}
+
+	var costs *models.TrainingCostsResponse
+	if action == "finish" {
+		var err error
+		if costs, err = s.GetTrainingCosts(id); err != nil {
+			return nil, err
+		}
+	}
+
+	tx := s.db.Begin()
+
+	if err := s.transitionSession(tx, &session, action, actor, reason); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if costs != nil {
+		if err := s.ledgerService.ChargeTrainingSession(tx, &session, costs); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetTrainingSessionByID(id)
+}
+
+// GetTrainingSessionTransitions returns the status history of a training session, oldest first
+func (s *TrainingService) GetTrainingSessionTransitions(id uuid.UUID) ([]models.TrainingSessionTransition, error) {
+	var session models.TrainingSession
+	if err := s.db.First(&session, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("training session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch training session: %w", err)
+	}
+
+	var transitions []models.TrainingSessionTransition
+	err := s.db.Where("training_session_id = ?", id).Order("created_at").Find(&transitions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch training session transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+// transitionSession validates and applies the action within the given transaction
+func (s *TrainingService) transitionSession(tx *gorm.DB, session *models.TrainingSession, action string, actor *string, reason *string) error {
+	transition, ok := sessionTransitions[action]
+	if !ok {
+		return fmt.Errorf("unknown training session action: %s", action)
+	}
+
+	allowed := false
+	for _, from := range transition.from {
+		if session.Status == from {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("cannot %s training session in status %s", action, session.Status)
+	}
+
+	entry := &models.TrainingSessionTransition{
+		TrainingSessionID: session.ID,
+		Action:            action,
+		FromStatus:        session.Status,
+		ToStatus:          transition.to,
+		ChangedBy:         actor,
+		Reason:            reason,
+		CreatedAt:         time.Now(),
+	}
+
+	session.Status = transition.to
+	if err := tx.Save(session).Error; err != nil {
+		return fmt.Errorf("failed to update training session: %w", err)
+	}
+
+	if err := tx.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record training session transition: %w", err)
+	}
+
+	return nil
+}
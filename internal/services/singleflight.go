@@ -0,0 +1,43 @@
+package services
+
+import "sync"
+
+// flightGroup runs one call per key at a time. Callers arriving while a call
+// is in flight wait for it and share its result instead of starting their own,
+// so a burst of requests causes a single request to the identity provider.
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Do runs fn unless a call with the same key is already running, in which case
+// it waits for that call and returns its error
+func (g *flightGroup) Do(key string, fn func() error) error {
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mutex.Unlock()
+		<-call.done
+		return call.err
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mutex.Unlock()
+
+	defer func() {
+		g.mutex.Lock()
+		delete(g.calls, key)
+		g.mutex.Unlock()
+		close(call.done)
+	}()
+
+	call.err = fn()
+	return call.err
+}
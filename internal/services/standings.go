@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const defaultTiebreakers = "leg_difference,head_to_head,average"
+
+var validTiebreakers = []string{"leg_difference", "head_to_head", "average", "buchholz"}
+
+type StandingsService struct {
+	db *gorm.DB
+}
+
+func NewStandingsService(db *gorm.DB) *StandingsService {
+	return &StandingsService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *StandingsService) WithContext(ctx context.Context) *StandingsService {
+	return &StandingsService{db: s.db.WithContext(ctx)}
+}
+
+// GetStandings computes the session table from all completed games of the
+// session. It works for round-robin as well as Swiss sessions; Swiss byes
+// count as a win without legs.
+func (s *StandingsService) GetStandings(trainingSessionID uuid.UUID) (*models.StandingsResponse, error) {
+	var session models.TrainingSession
+	err := s.db.Preload("TrainingPlayers.Player").
+		Preload("Games").
+		First(&session, "id = ?", trainingSessionID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("training session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch training session: %w", err)
+	}
+
+	var byes []models.SwissBye
+	if err := s.db.Where("training_session_id = ?", trainingSessionID).Find(&byes).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch swiss byes: %w", err)
+	}
+
+	rows := make(map[uuid.UUID]*models.StandingRow)
+	var table []*models.StandingRow
+	for _, tp := range session.TrainingPlayers {
+		if !tp.Attended {
+			continue
+		}
+		row := &models.StandingRow{
+			TrainingPlayerID: tp.ID,
+			PlayerID:         tp.PlayerID,
+			Name:             tp.DisplayName(),
+			IsGuest:          tp.IsGuest,
+		}
+		rows[tp.ID] = row
+		table = append(table, row)
+	}
+
+	type result struct {
+		player1, player2 uuid.UUID
+		points1, points2 int
+	}
+	var results []result
+
+	for _, game := range session.Games {
+		if game.Status != "completed" || game.Winner == nil {
+			continue
+		}
+
+		tp1 := findTrainingPlayer(session.TrainingPlayers, game.Player1ID, game.Guest1ID, game.Guest1Name)
+		tp2 := findTrainingPlayer(session.TrainingPlayers, game.Player2ID, game.Guest2ID, game.Guest2Name)
+		if tp1 == nil || tp2 == nil || rows[tp1.ID] == nil || rows[tp2.ID] == nil {
+			continue
+		}
+		row1, row2 := rows[tp1.ID], rows[tp2.ID]
+
+		row1.Played++
+		row2.Played++
+		row1.LegsFor += game.Player1Score
+		row1.LegsAgainst += game.Player2Score
+		row2.LegsFor += game.Player2Score
+		row2.LegsAgainst += game.Player1Score
+
+		res := result{player1: tp1.ID, player2: tp2.ID}
+		switch *game.Winner {
+		case "player1":
+			row1.Won++
+			row2.Lost++
+			res.points1, res.points2 = session.PointsWin, session.PointsLoss
+		case "player2":
+			row2.Won++
+			row1.Lost++
+			res.points1, res.points2 = session.PointsLoss, session.PointsWin
+		default:
+			row1.Drawn++
+			row2.Drawn++
+			res.points1, res.points2 = session.PointsDraw, session.PointsDraw
+		}
+		row1.Points += res.points1
+		row2.Points += res.points2
+		results = append(results, res)
+	}
+
+	for _, bye := range byes {
+		if row := rows[bye.TrainingPlayerID]; row != nil {
+			row.Played++
+			row.Won++
+			row.Points += session.PointsWin
+		}
+	}
+
+	for _, row := range table {
+		row.LegDifference = row.LegsFor - row.LegsAgainst
+		if row.Played > 0 {
+			row.Average = float64(row.LegsFor) / float64(row.Played)
+		}
+	}
+
+	// Head-to-head only counts games among players on equal points; Buchholz
+	// sums the points of every opponent met.
+	for _, res := range results {
+		row1, row2 := rows[res.player1], rows[res.player2]
+		if row1.Points == row2.Points {
+			row1.HeadToHead += res.points1
+			row2.HeadToHead += res.points2
+		}
+		row1.Buchholz += row2.Points
+		row2.Buchholz += row1.Points
+	}
+
+	tiebreakers := session.TiebreakerList()
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		for _, tiebreaker := range tiebreakers {
+			switch tiebreaker {
+			case "leg_difference":
+				if a.LegDifference != b.LegDifference {
+					return a.LegDifference > b.LegDifference
+				}
+			case "head_to_head":
+				if a.HeadToHead != b.HeadToHead {
+					return a.HeadToHead > b.HeadToHead
+				}
+			case "average":
+				if a.Average != b.Average {
+					return a.Average > b.Average
+				}
+			case "buchholz":
+				if a.Buchholz != b.Buchholz {
+					return a.Buchholz > b.Buchholz
+				}
+			}
+		}
+		return false
+	})
+
+	response := &models.StandingsResponse{
+		TrainingSessionID: trainingSessionID,
+		PointsWin:         session.PointsWin,
+		PointsDraw:        session.PointsDraw,
+		PointsLoss:        session.PointsLoss,
+		Tiebreakers:       tiebreakers,
+		Rows:              make([]models.StandingRow, len(table)),
+	}
+	for i, row := range table {
+		row.Rank = i + 1
+		response.Rows[i] = *row
+	}
+
+	return response, nil
+}
+
+func validateTiebreakers(tiebreakers []string) error {
+	for _, tiebreaker := range tiebreakers {
+		tiebreakerValid := false
+		for _, valid := range validTiebreakers {
+			if tiebreaker == valid {
+				tiebreakerValid = true
+				break
+			}
+		}
+		if !tiebreakerValid {
+			return fmt.Errorf("invalid tiebreaker: %s", tiebreaker)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// swissPairingBudget limits the backtracking search so that an impossible
+// round fails fast instead of exploring every permutation.
+const swissPairingBudget = 100000
+
+type swissEntry struct {
+	player    models.TrainingPlayer
+	score     float64
+	buchholz  float64
+	played    int
+	byes      int
+	opponents map[uuid.UUID]bool
+}
+
+// GetSwissStandings returns the Swiss table of a training session ordered by score and Buchholz
+func (s *GameService) GetSwissStandings(trainingSessionID uuid.UUID) ([]models.SwissStandingResponse, error) {
+	session, byes, err := s.loadSwissSession(trainingSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, _ := buildSwissTable(session, byes)
+
+	standings := make([]models.SwissStandingResponse, len(entries))
+	for i, entry := range entries {
+		standings[i] = models.SwissStandingResponse{
+			Rank:             i + 1,
+			TrainingPlayerID: entry.player.ID,
+			Name:             entry.player.DisplayName(),
+			Score:            entry.score,
+			Buchholz:         entry.buchholz,
+			GamesPlayed:      entry.played,
+			Byes:             entry.byes,
+		}
+	}
+
+	return standings, nil
+}
+
+// generateSwissRound pairs the next Swiss round: players with equal scores
+// meet, nobody meets the same opponent twice and an odd player out gets a bye.
+func (s *GameService) generateSwissRound(trainingSessionID uuid.UUID, gameModeID uuid.UUID) ([]models.TrainingGame, error) {
+	session, byes, err := s.loadSwissSession(trainingSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status != "planned" && session.Status != "active" {
+		return nil, fmt.Errorf("can only generate swiss rounds for planned or active training sessions")
+	}
+
+	for _, game := range session.Games {
+		if game.Status == "cancelled" {
+			continue
+		}
+		if game.Round == nil {
+			return nil, fmt.Errorf("cannot mix swiss rounds with other games")
+		}
+		if game.Status != "completed" {
+			return nil, fmt.Errorf("previous swiss round is not finished yet")
+		}
+	}
+
+	entries, lastRound := buildSwissTable(session, byes)
+	if len(entries) < 2 {
+		return nil, fmt.Errorf("need at least 2 attending players to generate games")
+	}
+
+	round := lastRound + 1
+	pairs, bye, ok := pairSwissRound(entries)
+	if !ok {
+		return nil, fmt.Errorf("no more swiss rounds possible without repeat pairings")
+	}
+
+	tx := s.db.Begin()
+
+	var games []models.TrainingGame
+	for _, pair := range pairs {
+		game := &models.TrainingGame{
+			TrainingSessionID: &trainingSessionID,
+			GameModeID:        gameModeID,
+			Round:             &round,
+			Status:            "pending",
+		}
+
+		if pair[0].player.IsGuest {
+			game.Guest1ID = pair[0].player.GuestID
+			game.Guest1Name = pair[0].player.GuestName
+		} else {
+			game.Player1ID = pair[0].player.PlayerID
+		}
+
+		if pair[1].player.IsGuest {
+			game.Guest2ID = pair[1].player.GuestID
+			game.Guest2Name = pair[1].player.GuestName
+		} else {
+			game.Player2ID = pair[1].player.PlayerID
+		}
+
+		if err := tx.Create(game).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to create game: %w", err)
+		}
+
+		games = append(games, *game)
+	}
+
+	if bye != nil {
+		swissBye := &models.SwissBye{
+			TrainingSessionID: trainingSessionID,
+			TrainingPlayerID:  bye.player.ID,
+			Round:             round,
+		}
+		if err := tx.Create(swissBye).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to record bye: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Load relationships for response
+	for i := range games {
+		err := s.db.Preload("GameMode").
+			Preload("Player1").
+			Preload("Player2").
+			First(&games[i], "id = ?", games[i].ID).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch created game: %w", err)
+		}
+	}
+
+	return games, nil
+}
+
+func (s *GameService) loadSwissSession(trainingSessionID uuid.UUID) (*models.TrainingSession, []models.SwissBye, error) {
+	var session models.TrainingSession
+	err := s.db.Preload("TrainingPlayers.Player").
+		Preload("Games").
+		First(&session, "id = ?", trainingSessionID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, fmt.Errorf("training session not found")
+		}
+		return nil, nil, fmt.Errorf("failed to fetch training session: %w", err)
+	}
+
+	var byes []models.SwissBye
+	if err := s.db.Where("training_session_id = ?", trainingSessionID).Find(&byes).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch swiss byes: %w", err)
+	}
+
+	return &session, byes, nil
+}
+
+// buildSwissTable scores the attending players from the completed Swiss games
+// and returns them ranked, together with the number of the last round played.
+func buildSwissTable(session *models.TrainingSession, byes []models.SwissBye) ([]*swissEntry, int) {
+	entries := make(map[uuid.UUID]*swissEntry)
+	var ranked []*swissEntry
+	for _, tp := range session.TrainingPlayers {
+		if !tp.Attended {
+			continue
+		}
+		entry := &swissEntry{player: tp, opponents: make(map[uuid.UUID]bool)}
+		entries[tp.ID] = entry
+		ranked = append(ranked, entry)
+	}
+
+	lastRound := 0
+	for _, game := range session.Games {
+		if game.Round == nil || game.Status == "cancelled" {
+			continue
+		}
+		if *game.Round > lastRound {
+			lastRound = *game.Round
+		}
+
+		tp1 := findTrainingPlayer(session.TrainingPlayers, game.Player1ID, game.Guest1ID, game.Guest1Name)
+		tp2 := findTrainingPlayer(session.TrainingPlayers, game.Player2ID, game.Guest2ID, game.Guest2Name)
+		if tp1 == nil || tp2 == nil || entries[tp1.ID] == nil || entries[tp2.ID] == nil {
+			continue
+		}
+		entry1, entry2 := entries[tp1.ID], entries[tp2.ID]
+		entry1.opponents[tp2.ID] = true
+		entry2.opponents[tp1.ID] = true
+
+		if game.Status != "completed" || game.Winner == nil {
+			continue
+		}
+		entry1.played++
+		entry2.played++
+		switch *game.Winner {
+		case "player1":
+			entry1.score++
+		case "player2":
+			entry2.score++
+		default:
+			entry1.score += 0.5
+			entry2.score += 0.5
+		}
+	}
+
+	for _, bye := range byes {
+		if bye.Round > lastRound {
+			lastRound = bye.Round
+		}
+		if entry := entries[bye.TrainingPlayerID]; entry != nil {
+			entry.byes++
+			entry.score++
+		}
+	}
+
+	// Buchholz is the sum of the scores of all opponents met so far
+	for _, entry := range ranked {
+		for opponentID := range entry.opponents {
+			entry.buchholz += entries[opponentID].score
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].buchholz > ranked[j].buchholz
+	})
+
+	return ranked, lastRound
+}
+
+// pairSwissRound picks the bye (lowest ranked player without a bye so far)
+// and pairs the remaining players top-down without repeat pairings.
+func pairSwissRound(ranked []*swissEntry) ([][2]*swissEntry, *swissEntry, bool) {
+	budget := swissPairingBudget
+
+	if len(ranked)%2 == 0 {
+		pairs, ok := pairSwiss(ranked, &budget)
+		return pairs, nil, ok
+	}
+
+	for _, allowRepeatBye := range []bool{false, true} {
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if ranked[i].byes > 0 && !allowRepeatBye {
+				continue
+			}
+			rest := make([]*swissEntry, 0, len(ranked)-1)
+			rest = append(rest, ranked[:i]...)
+			rest = append(rest, ranked[i+1:]...)
+			if pairs, ok := pairSwiss(rest, &budget); ok {
+				return pairs, ranked[i], true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
+func pairSwiss(entries []*swissEntry, budget *int) ([][2]*swissEntry, bool) {
+	if len(entries) == 0 {
+		return nil, true
+	}
+
+	first := entries[0]
+	for i := 1; i < len(entries); i++ {
+		*budget--
+		if *budget < 0 {
+			return nil, false
+		}
+
+		opponent := entries[i]
+		if first.opponents[opponent.player.ID] {
+			continue
+		}
+
+		rest := make([]*swissEntry, 0, len(entries)-2)
+		rest = append(rest, entries[1:i]...)
+		rest = append(rest, entries[i+1:]...)
+		if pairs, ok := pairSwiss(rest, budget); ok {
+			return append([][2]*swissEntry{{first, opponent}}, pairs...), true
+		}
+	}
+
+	return nil, false
+}
+
+// findTrainingPlayer maps a game participant back to its training player
+func findTrainingPlayer(players []models.TrainingPlayer, playerID, guestID *uuid.UUID, guestName *string) *models.TrainingPlayer {
+	for i := range players {
+		if players[i].Matches(playerID, guestID, guestName) {
+			return &players[i]
+		}
+	}
+	return nil
+}
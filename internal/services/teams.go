@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"darts-training-app/internal/models"
 
@@ -18,6 +19,12 @@ func NewTeamService(db *gorm.DB) *TeamService {
 	}
 }
 
+// WithContext returns the service bound to ctx, queries then only see the
+// rows of the club set on ctx
+func (s *TeamService) WithContext(ctx context.Context) *TeamService {
+	return &TeamService{db: s.db.WithContext(ctx)}
+}
+
 func (s *TeamService) GetAllTeams() ([]models.Team, error) {
 	var teams []models.Team
 	err := s.db.Preload("Players").Find(&teams).Error
@@ -111,28 +118,16 @@ func (s *TeamService) DeleteTeam(id uuid.UUID) error {
 		return fmt.Errorf("cannot delete team with %d players. Please reassign or delete players first", playerCount)
 	}
 
+	// Check if team has current or future memberships
+	membershipCount := int64(0)
+	s.db.Model(&models.TeamMembership{}).Where("team_id = ?", id).Scopes(membershipsOverlapping(today(), nil)).Count(&membershipCount)
+	if membershipCount > 0 {
+		return fmt.Errorf("cannot delete team with %d current or future memberships. Please end them first", membershipCount)
+	}
+
 	if err := s.db.Delete(&team).Error; err != nil {
 		return fmt.Errorf("failed to delete team: %w", err)
 	}
 
 	return nil
-}
-
-func (s *TeamService) GetTeamPlayers(id uuid.UUID) ([]models.Player, error) {
-	// Check if team exists
-	var team models.Team
-	if err := s.db.First(&team, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("team not found")
-		}
-		return nil, fmt.Errorf("failed to fetch team: %w", err)
-	}
-
-	var players []models.Player
-	err := s.db.Where("team_id = ?", id).Find(&players).Error
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch team players: %w", err)
-	}
-
-	return players, nil
 }
\ No newline at end of file
@@ -0,0 +1,729 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"darts-training-app/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TournamentService struct {
+	db *gorm.DB
+}
+
+func NewTournamentService(db *gorm.DB) *TournamentService {
+	return &TournamentService{
+		db: db,
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *TournamentService) WithContext(ctx context.Context) *TournamentService {
+	return &TournamentService{db: s.db.WithContext(ctx)}
+}
+
+// bracketOrder defines the display order of the brackets of a tournament
+var bracketOrder = map[string]int{
+	"group":       0,
+	"winners":     1,
+	"losers":      2,
+	"grand_final": 3,
+}
+
+func (s *TournamentService) GetTournamentByTrainingSession(trainingSessionID uuid.UUID) (*models.Tournament, error) {
+	var tournament models.Tournament
+	err := s.db.Preload("Winner.Player").
+		Preload("Entrants", func(db *gorm.DB) *gorm.DB { return db.Order("seed") }).
+		Preload("Entrants.TrainingPlayer.Player").
+		Preload("Matches.Participant1.Player").
+		Preload("Matches.Participant2.Player").
+		Preload("Matches.TrainingGame").
+		First(&tournament, "training_session_id = ?", trainingSessionID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("tournament not found")
+		}
+		return nil, fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+
+	sort.SliceStable(tournament.Matches, func(i, j int) bool {
+		a, b := tournament.Matches[i], tournament.Matches[j]
+		if bracketOrder[a.Bracket] != bracketOrder[b.Bracket] {
+			return bracketOrder[a.Bracket] < bracketOrder[b.Bracket]
+		}
+		if a.GroupIndex != nil && b.GroupIndex != nil && *a.GroupIndex != *b.GroupIndex {
+			return *a.GroupIndex < *b.GroupIndex
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.Position < b.Position
+	})
+
+	return &tournament, nil
+}
+
+func (s *TournamentService) CreateTournament(trainingSessionID uuid.UUID, req *models.TournamentCreateRequest) (*models.Tournament, error) {
+	var session models.TrainingSession
+	err := s.db.Preload("TrainingPlayers.Player").First(&session, "id = ?", trainingSessionID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("training session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch training session: %w", err)
+	}
+
+	if session.Status != "planned" && session.Status != "active" {
+		return nil, fmt.Errorf("cannot create tournament for completed or cancelled training")
+	}
+
+	var existing int64
+	s.db.Model(&models.Tournament{}).Where("training_session_id = ?", trainingSessionID).Count(&existing)
+	if existing > 0 {
+		return nil, fmt.Errorf("training session already has a tournament")
+	}
+
+	var gameMode models.GameMode
+	if err := s.db.First(&gameMode, "id = ?", req.GameModeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("game mode not found")
+		}
+		return nil, fmt.Errorf("failed to fetch game mode: %w", err)
+	}
+
+	validFormats := []string{"single_elimination", "double_elimination", "group_knockout"}
+	formatValid := false
+	for _, format := range validFormats {
+		if req.Format == format {
+			formatValid = true
+			break
+		}
+	}
+	if !formatValid {
+		return nil, fmt.Errorf("invalid tournament format: %s", req.Format)
+	}
+
+	seeding := "random"
+	if req.Seeding != nil {
+		seeding = *req.Seeding
+	}
+	if seeding != "random" && seeding != "win_rate" {
+		return nil, fmt.Errorf("invalid seeding: %s", seeding)
+	}
+
+	// Filter attending players
+	var attendingPlayers []models.TrainingPlayer
+	for _, tp := range session.TrainingPlayers {
+		if tp.Attended {
+			attendingPlayers = append(attendingPlayers, tp)
+		}
+	}
+
+	if len(attendingPlayers) < 2 {
+		return nil, fmt.Errorf("need at least 2 attending players to create a tournament")
+	}
+
+	tournament := &models.Tournament{
+		ID:                uuid.New(),
+		TrainingSessionID: trainingSessionID,
+		GameModeID:        req.GameModeID,
+		Format:            req.Format,
+		Seeding:           seeding,
+		Status:            "active",
+	}
+
+	if req.Format == "group_knockout" {
+		tournament.GroupCount = 2
+		tournament.AdvancePerGroup = 2
+		if req.GroupCount != nil {
+			tournament.GroupCount = *req.GroupCount
+		}
+		if req.AdvancePerGroup != nil {
+			tournament.AdvancePerGroup = *req.AdvancePerGroup
+		}
+
+		// Every group needs at least two players and enough players to fill the qualifying spots
+		smallestGroup := 0
+		if tournament.GroupCount > 0 {
+			smallestGroup = len(attendingPlayers) / tournament.GroupCount
+		}
+		if tournament.GroupCount < 1 || tournament.AdvancePerGroup < 1 ||
+			smallestGroup < 2 || smallestGroup < tournament.AdvancePerGroup ||
+			tournament.GroupCount*tournament.AdvancePerGroup < 2 {
+			return nil, fmt.Errorf("invalid group configuration")
+		}
+		tournament.Status = "group_stage"
+	}
+
+	seeded, err := s.seedPlayers(attendingPlayers, seeding)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.db.Begin()
+
+	if err := tx.Create(tournament).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create tournament: %w", err)
+	}
+
+	entrants := make([]models.TournamentEntrant, len(seeded))
+	for i, tp := range seeded {
+		entrants[i] = models.TournamentEntrant{
+			TournamentID:     tournament.ID,
+			TrainingPlayerID: tp.ID,
+			Seed:             i + 1,
+		}
+		if tournament.Format == "group_knockout" {
+			group := snakeGroup(i, tournament.GroupCount)
+			entrants[i].GroupIndex = &group
+		}
+		if err := tx.Create(&entrants[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to create tournament entrant: %w", err)
+		}
+	}
+
+	var matches []*models.TournamentMatch
+	switch tournament.Format {
+	case "single_elimination":
+		matches = buildSingleElimination(tournament.ID, entrantIDs(entrants))
+	case "double_elimination":
+		matches = buildDoubleElimination(tournament.ID, entrantIDs(entrants))
+	case "group_knockout":
+		matches = buildGroupStage(tournament.ID, entrants, tournament.GroupCount)
+	}
+
+	if err := s.startBracket(tx, tournament, matches); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetTournamentByTrainingSession(trainingSessionID)
+}
+
+// HandleGameCompleted advances the bracket after a tournament game has been
+// completed. Games that do not belong to a tournament are ignored.
+func (s *TournamentService) HandleGameCompleted(tx *gorm.DB, game *models.TrainingGame) error {
+	var match models.TournamentMatch
+	err := tx.Where("training_game_id = ?", game.ID).First(&match).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch tournament match: %w", err)
+	}
+
+	if match.Status != "pending" || game.Winner == nil {
+		return nil
+	}
+
+	var tournament models.Tournament
+	if err := tx.First(&tournament, "id = ?", match.TournamentID).Error; err != nil {
+		return fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+
+	var winner, loser *uuid.UUID
+	switch *game.Winner {
+	case "player1":
+		winner, loser = match.Participant1ID, match.Participant2ID
+	case "player2":
+		winner, loser = match.Participant2ID, match.Participant1ID
+	default:
+		if match.Bracket != "group" {
+			return fmt.Errorf("knockout games cannot end in a draw")
+		}
+	}
+
+	match.WinnerID = winner
+	match.Status = "completed"
+	if err := tx.Save(&match).Error; err != nil {
+		return fmt.Errorf("failed to update tournament match: %w", err)
+	}
+
+	if match.Bracket == "group" {
+		return s.finishGroupStageIfComplete(tx, &tournament)
+	}
+
+	return s.propagate(tx, &tournament, &match, winner, loser)
+}
+
+// startBracket stores the given matches and resolves all matches whose slots are already decided
+func (s *TournamentService) startBracket(tx *gorm.DB, tournament *models.Tournament, matches []*models.TournamentMatch) error {
+	for _, match := range matches {
+		if err := tx.Create(match).Error; err != nil {
+			return fmt.Errorf("failed to create tournament match: %w", err)
+		}
+	}
+
+	for _, match := range matches {
+		if !match.Slot1Ready || !match.Slot2Ready {
+			continue
+		}
+		// Reload, the match may have been resolved by an earlier bye
+		var current models.TournamentMatch
+		if err := tx.First(&current, "id = ?", match.ID).Error; err != nil {
+			return fmt.Errorf("failed to fetch tournament match: %w", err)
+		}
+		if err := s.resolveMatch(tx, tournament, &current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveMatch creates the game for a match once both participants are known,
+// or advances the remaining participant if the match turned out to be a bye.
+func (s *TournamentService) resolveMatch(tx *gorm.DB, tournament *models.Tournament, match *models.TournamentMatch) error {
+	if match.Status != "waiting" || !match.Slot1Ready || !match.Slot2Ready {
+		return nil
+	}
+
+	if match.Participant1ID != nil && match.Participant2ID != nil {
+		game, err := s.createMatchGame(tx, tournament, match)
+		if err != nil {
+			return err
+		}
+		match.TrainingGameID = &game.ID
+		match.Status = "pending"
+		if err := tx.Save(match).Error; err != nil {
+			return fmt.Errorf("failed to update tournament match: %w", err)
+		}
+		return nil
+	}
+
+	winner := match.Participant1ID
+	if winner == nil {
+		winner = match.Participant2ID
+	}
+	match.WinnerID = winner
+	match.Status = "bye"
+	if err := tx.Save(match).Error; err != nil {
+		return fmt.Errorf("failed to update tournament match: %w", err)
+	}
+
+	return s.propagate(tx, tournament, match, winner, nil)
+}
+
+// propagate moves the winner and loser of a decided match into their follow-up matches
+func (s *TournamentService) propagate(tx *gorm.DB, tournament *models.Tournament, match *models.TournamentMatch, winner, loser *uuid.UUID) error {
+	if match.NextMatchID == nil {
+		// The final has been decided
+		tournament.WinnerID = winner
+		tournament.Status = "completed"
+		if err := tx.Save(tournament).Error; err != nil {
+			return fmt.Errorf("failed to update tournament: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.fillSlot(tx, tournament, *match.NextMatchID, match.NextSlot, winner); err != nil {
+		return err
+	}
+
+	if match.LoserNextMatchID != nil {
+		if err := s.fillSlot(tx, tournament, *match.LoserNextMatchID, match.LoserNextSlot, loser); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TournamentService) fillSlot(tx *gorm.DB, tournament *models.Tournament, matchID uuid.UUID, slot int, participantID *uuid.UUID) error {
+	var match models.TournamentMatch
+	if err := tx.First(&match, "id = ?", matchID).Error; err != nil {
+		return fmt.Errorf("failed to fetch tournament match: %w", err)
+	}
+
+	if slot == 1 {
+		match.Participant1ID = participantID
+		match.Slot1Ready = true
+	} else {
+		match.Participant2ID = participantID
+		match.Slot2Ready = true
+	}
+
+	if err := tx.Save(&match).Error; err != nil {
+		return fmt.Errorf("failed to update tournament match: %w", err)
+	}
+
+	return s.resolveMatch(tx, tournament, &match)
+}
+
+func (s *TournamentService) createMatchGame(tx *gorm.DB, tournament *models.Tournament, match *models.TournamentMatch) (*models.TrainingGame, error) {
+	var participant1, participant2 models.TrainingPlayer
+	if err := tx.First(&participant1, "id = ?", *match.Participant1ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch training player: %w", err)
+	}
+	if err := tx.First(&participant2, "id = ?", *match.Participant2ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch training player: %w", err)
+	}
+
+	game := &models.TrainingGame{
+		TrainingSessionID: &tournament.TrainingSessionID,
+		GameModeID:        tournament.GameModeID,
+		Status:            "pending",
+	}
+
+	if participant1.IsGuest {
+		game.Guest1ID = participant1.GuestID
+		game.Guest1Name = participant1.GuestName
+	} else {
+		game.Player1ID = participant1.PlayerID
+	}
+
+	if participant2.IsGuest {
+		game.Guest2ID = participant2.GuestID
+		game.Guest2Name = participant2.GuestName
+	} else {
+		game.Player2ID = participant2.PlayerID
+	}
+
+	if err := tx.Create(game).Error; err != nil {
+		return nil, fmt.Errorf("failed to create game: %w", err)
+	}
+
+	return game, nil
+}
+
+// finishGroupStageIfComplete builds the knockout bracket from the group tables
+// once every group game has been completed.
+func (s *TournamentService) finishGroupStageIfComplete(tx *gorm.DB, tournament *models.Tournament) error {
+	var openCount int64
+	if err := tx.Model(&models.TournamentMatch{}).
+		Where("tournament_id = ? AND bracket = ? AND status NOT IN ?", tournament.ID, "group", []string{"completed", "bye"}).
+		Count(&openCount).Error; err != nil {
+		return fmt.Errorf("failed to count open group matches: %w", err)
+	}
+	if openCount > 0 {
+		return nil
+	}
+
+	var entrants []models.TournamentEntrant
+	if err := tx.Where("tournament_id = ?", tournament.ID).Order("seed").Find(&entrants).Error; err != nil {
+		return fmt.Errorf("failed to fetch tournament entrants: %w", err)
+	}
+
+	var groupMatches []models.TournamentMatch
+	if err := tx.Preload("TrainingGame").
+		Where("tournament_id = ? AND bracket = ?", tournament.ID, "group").
+		Find(&groupMatches).Error; err != nil {
+		return fmt.Errorf("failed to fetch group matches: %w", err)
+	}
+
+	type groupRow struct {
+		id      uuid.UUID
+		seed    int
+		points  int
+		legDiff int
+	}
+
+	rows := make(map[uuid.UUID]*groupRow)
+	groups := make([][]*groupRow, tournament.GroupCount)
+	for _, e := range entrants {
+		row := &groupRow{id: e.TrainingPlayerID, seed: e.Seed}
+		rows[e.TrainingPlayerID] = row
+		if e.GroupIndex != nil {
+			groups[*e.GroupIndex] = append(groups[*e.GroupIndex], row)
+		}
+	}
+
+	for _, m := range groupMatches {
+		if m.TrainingGame == nil || m.Participant1ID == nil || m.Participant2ID == nil {
+			continue
+		}
+		row1, row2 := rows[*m.Participant1ID], rows[*m.Participant2ID]
+		row1.legDiff += m.TrainingGame.Player1Score - m.TrainingGame.Player2Score
+		row2.legDiff += m.TrainingGame.Player2Score - m.TrainingGame.Player1Score
+		switch {
+		case m.WinnerID == nil:
+			row1.points++
+			row2.points++
+		case *m.WinnerID == row1.id:
+			row1.points += 2
+		default:
+			row2.points += 2
+		}
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].points != group[j].points {
+				return group[i].points > group[j].points
+			}
+			if group[i].legDiff != group[j].legDiff {
+				return group[i].legDiff > group[j].legDiff
+			}
+			return group[i].seed < group[j].seed
+		})
+	}
+
+	// Group winners are seeded first, then all runners-up, and so on
+	var qualifiers []*uuid.UUID
+	for place := 0; place < tournament.AdvancePerGroup; place++ {
+		for _, group := range groups {
+			if place < len(group) {
+				id := group[place].id
+				qualifiers = append(qualifiers, &id)
+			}
+		}
+	}
+
+	tournament.Status = "active"
+	if err := tx.Save(tournament).Error; err != nil {
+		return fmt.Errorf("failed to update tournament: %w", err)
+	}
+
+	return s.startBracket(tx, tournament, buildSingleElimination(tournament.ID, qualifiers))
+}
+
+// seedPlayers orders the attending players by seed, best seed first
+func (s *TournamentService) seedPlayers(players []models.TrainingPlayer, seeding string) ([]models.TrainingPlayer, error) {
+	seeded := make([]models.TrainingPlayer, len(players))
+	copy(seeded, players)
+	rand.Shuffle(len(seeded), func(i, j int) { seeded[i], seeded[j] = seeded[j], seeded[i] })
+
+	if seeding != "win_rate" {
+		return seeded, nil
+	}
+
+	winRates := make(map[uuid.UUID]float64)
+	for _, tp := range seeded {
+		if tp.PlayerID == nil {
+			continue
+		}
+		rate, err := s.playerWinRate(*tp.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		winRates[tp.ID] = rate
+	}
+
+	// Random order is kept as tie-breaker; guests have no history and are seeded last
+	sort.SliceStable(seeded, func(i, j int) bool {
+		return winRates[seeded[i].ID] > winRates[seeded[j].ID]
+	})
+
+	return seeded, nil
+}
+
+func (s *TournamentService) playerWinRate(playerID uuid.UUID) (float64, error) {
+	var played, won int64
+	err := s.db.Model(&models.TrainingGame{}).
+		Where("status = ? AND (player1_id = ? OR player2_id = ?)", "completed", playerID, playerID).
+		Count(&played).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count games: %w", err)
+	}
+	if played == 0 {
+		return 0, nil
+	}
+
+	err = s.db.Model(&models.TrainingGame{}).
+		Where("status = ? AND ((player1_id = ? AND winner = ?) OR (player2_id = ? AND winner = ?))",
+			"completed", playerID, "player1", playerID, "player2").
+		Count(&won).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count won games: %w", err)
+	}
+
+	return float64(won) / float64(played), nil
+}
+
+func entrantIDs(entrants []models.TournamentEntrant) []*uuid.UUID {
+	ids := make([]*uuid.UUID, len(entrants))
+	for i := range entrants {
+		ids[i] = &entrants[i].TrainingPlayerID
+	}
+	return ids
+}
+
+// snakeGroup distributes seeds over the groups as 1-2-3-3-2-1 so that groups are balanced
+func snakeGroup(index, groupCount int) int {
+	row := index / groupCount
+	if row%2 == 0 {
+		return index % groupCount
+	}
+	return groupCount - 1 - index%groupCount
+}
+
+// bracketSize returns the smallest power of two that fits the given number of players
+func bracketSize(players int) int {
+	size := 1
+	for size < players {
+		size *= 2
+	}
+	return size
+}
+
+// seedOrder returns the seeds in bracket order, so that the top seeds can only meet in the final
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+func newMatch(tournamentID uuid.UUID, bracket string, round, position int) *models.TournamentMatch {
+	return &models.TournamentMatch{
+		ID:           uuid.New(),
+		TournamentID: tournamentID,
+		Bracket:      bracket,
+		Round:        round,
+		Position:     position,
+		Status:       "waiting",
+	}
+}
+
+func linkWinner(from, to *models.TournamentMatch, slot int) {
+	from.NextMatchID = &to.ID
+	from.NextSlot = slot
+}
+
+func linkLoser(from, to *models.TournamentMatch, slot int) {
+	from.LoserNextMatchID = &to.ID
+	from.LoserNextSlot = slot
+}
+
+// buildWinnersBracket builds the rounds of a knockout bracket for the seeded
+// participants. Missing participants in the first round become byes.
+func buildWinnersBracket(tournamentID uuid.UUID, seeded []*uuid.UUID, size int) [][]*models.TournamentMatch {
+	order := seedOrder(size)
+	var rounds [][]*models.TournamentMatch
+
+	for round, count := 1, size/2; count >= 1; round, count = round+1, count/2 {
+		matches := make([]*models.TournamentMatch, count)
+		for i := range matches {
+			matches[i] = newMatch(tournamentID, "winners", round, i+1)
+		}
+		rounds = append(rounds, matches)
+	}
+
+	for i, match := range rounds[0] {
+		if seed := order[2*i]; seed <= len(seeded) {
+			match.Participant1ID = seeded[seed-1]
+		}
+		if seed := order[2*i+1]; seed <= len(seeded) {
+			match.Participant2ID = seeded[seed-1]
+		}
+		match.Slot1Ready = true
+		match.Slot2Ready = true
+	}
+
+	for r := 0; r < len(rounds)-1; r++ {
+		for i, match := range rounds[r] {
+			linkWinner(match, rounds[r+1][i/2], i%2+1)
+		}
+	}
+
+	return rounds
+}
+
+func buildSingleElimination(tournamentID uuid.UUID, seeded []*uuid.UUID) []*models.TournamentMatch {
+	var matches []*models.TournamentMatch
+	for _, round := range buildWinnersBracket(tournamentID, seeded, bracketSize(len(seeded))) {
+		matches = append(matches, round...)
+	}
+	return matches
+}
+
+// buildDoubleElimination builds a winners bracket, a losers bracket fed by the
+// losers of each winners round, and a grand final between both bracket winners.
+func buildDoubleElimination(tournamentID uuid.UUID, seeded []*uuid.UUID) []*models.TournamentMatch {
+	size := bracketSize(len(seeded))
+	if size < 4 {
+		size = 4
+	}
+
+	winners := buildWinnersBracket(tournamentID, seeded, size)
+
+	// The losers bracket alternates between rounds among its own players and
+	// rounds where the losers of the next winners round drop in.
+	var losers [][]*models.TournamentMatch
+	for round := 1; round <= 2*(len(winners)-1); round++ {
+		count := size >> uint((round+1)/2+1)
+		matches := make([]*models.TournamentMatch, count)
+		for i := range matches {
+			matches[i] = newMatch(tournamentID, "losers", round, i+1)
+		}
+		losers = append(losers, matches)
+	}
+
+	grandFinal := newMatch(tournamentID, "grand_final", 1, 1)
+
+	for i, match := range winners[0] {
+		linkLoser(match, losers[0][i/2], i%2+1)
+	}
+	for r := 1; r < len(winners); r++ {
+		dropIn := losers[2*r-1]
+		for i, match := range winners[r] {
+			linkLoser(match, dropIn[len(dropIn)-1-i], 2)
+		}
+	}
+	linkWinner(winners[len(winners)-1][0], grandFinal, 1)
+
+	for r, round := range losers {
+		for i, match := range round {
+			switch {
+			case r == len(losers)-1:
+				linkWinner(match, grandFinal, 2)
+			case r%2 == 0:
+				linkWinner(match, losers[r+1][i], 1)
+			default:
+				linkWinner(match, losers[r+1][i/2], i%2+1)
+			}
+		}
+	}
+
+	var matches []*models.TournamentMatch
+	for _, round := range winners {
+		matches = append(matches, round...)
+	}
+	for _, round := range losers {
+		matches = append(matches, round...)
+	}
+	return append(matches, grandFinal)
+}
+
+// buildGroupStage creates a round-robin within every group
+func buildGroupStage(tournamentID uuid.UUID, entrants []models.TournamentEntrant, groupCount int) []*models.TournamentMatch {
+	groups := make([][]uuid.UUID, groupCount)
+	for _, e := range entrants {
+		groups[*e.GroupIndex] = append(groups[*e.GroupIndex], e.TrainingPlayerID)
+	}
+
+	var matches []*models.TournamentMatch
+	for g, members := range groups {
+		position := 1
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				group := g
+				match := newMatch(tournamentID, "group", 1, position)
+				match.GroupIndex = &group
+				match.Participant1ID = &members[i]
+				match.Participant2ID = &members[j]
+				match.Slot1Ready = true
+				match.Slot2Ready = true
+				matches = append(matches, match)
+				position++
+			}
+		}
+	}
+	return matches
+}
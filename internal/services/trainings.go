@@ -1,28 +1,48 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"darts-training-app/internal/models"
-	"darts-training-app/internal/utils"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
 type TrainingService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	notifier      Notifier
+	ledgerService *LedgerService
+	costService   *CostService
 }
 
-func NewTrainingService(db *gorm.DB) *TrainingService {
+func NewTrainingService(db *gorm.DB, notifier Notifier) *TrainingService {
 	return &TrainingService{
-		db: db,
+		db:            db,
+		notifier:      notifier,
+		ledgerService: NewLedgerService(db),
+		costService:   NewCostService(db),
+	}
+}
+
+// WithContext returns the service bound to ctx
+func (s *TrainingService) WithContext(ctx context.Context) *TrainingService {
+	return &TrainingService{
+		db:            s.db.WithContext(ctx),
+		notifier:      s.notifier,
+		ledgerService: s.ledgerService.WithContext(ctx),
+		costService:   s.costService.WithContext(ctx),
 	}
 }
 
 func (s *TrainingService) GetAllTrainingSessions() ([]models.TrainingSession, error) {
 	var sessions []models.TrainingSession
 	err := s.db.Preload("Creator").
+		Preload("PricingPolicy").
 		Preload("TrainingPlayers.Player").
 		Preload("Games.GameMode").
 		Preload("Games.Player1").
@@ -38,6 +58,7 @@ func (s *TrainingService) GetAllTrainingSessions() ([]models.TrainingSession, er
 func (s *TrainingService) GetTrainingSessionByID(id uuid.UUID) (*models.TrainingSession, error) {
 	var session models.TrainingSession
 	err := s.db.Preload("Creator").
+		Preload("PricingPolicy").
 		Preload("TrainingPlayers.Player").
 		Preload("Games.GameMode").
 		Preload("Games.Player1").
@@ -52,19 +73,47 @@ func (s *TrainingService) GetTrainingSessionByID(id uuid.UUID) (*models.Training
 	return &session, nil
 }
 
-func (s *TrainingService) CreateTrainingSession(req *models.TrainingSessionCreateRequest, creatorID uuid.UUID) (*models.TrainingSession, error) {
+// CreateTrainingSession creates a planned session, creatorID is the player of
+// the caller and nil for callers without a player profile
+func (s *TrainingService) CreateTrainingSession(req *models.TrainingSessionCreateRequest, creatorID *uuid.UUID) (*models.TrainingSession, error) {
 	session := &models.TrainingSession{
 		Name:          req.Name,
 		Description:   req.Description,
 		TrainingDate:  req.TrainingDate,
-		CostPerPlayer: 5.00, // Default cost
+		CostPerPlayer: models.NewMoney(500, models.DefaultCurrency), // Default cost
 		Status:        "planned",
-		CreatedBy:     &creatorID,
+		PointsWin:     2,
+		PointsDraw:    1,
+		PointsLoss:    0,
+		Tiebreakers:   defaultTiebreakers,
+		CreatedBy:     creatorID,
 	}
 
 	if req.CostPerPlayer != nil {
 		session.CostPerPlayer = *req.CostPerPlayer
 	}
+	if req.PricingPolicyID != nil {
+		policyID, err := s.resolvePricingPolicyID(*req.PricingPolicyID)
+		if err != nil {
+			return nil, err
+		}
+		session.PricingPolicyID = policyID
+	}
+	if req.PointsWin != nil {
+		session.PointsWin = *req.PointsWin
+	}
+	if req.PointsDraw != nil {
+		session.PointsDraw = *req.PointsDraw
+	}
+	if req.PointsLoss != nil {
+		session.PointsLoss = *req.PointsLoss
+	}
+	if req.Tiebreakers != nil {
+		if err := validateTiebreakers(req.Tiebreakers); err != nil {
+			return nil, err
+		}
+		session.Tiebreakers = strings.Join(req.Tiebreakers, ",")
+	}
 
 	// Start transaction
 	tx := s.db.Begin()
@@ -74,6 +123,12 @@ func (s *TrainingService) CreateTrainingSession(req *models.TrainingSessionCreat
 		return nil, fmt.Errorf("failed to create training session: %w", err)
 	}
 
+	// Zero values are replaced by column defaults on create, so write the point scheme explicitly
+	if err := tx.Model(session).Select("PointsWin", "PointsDraw", "PointsLoss", "Tiebreakers").Updates(session).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create training session: %w", err)
+	}
+
 	// Auto-assign all players to training
 	var players []models.Player
 	if err := tx.Find(&players).Error; err != nil {
@@ -124,20 +179,31 @@ func (s *TrainingService) UpdateTrainingSession(id uuid.UUID, req *models.Traini
 	if req.CostPerPlayer != nil {
 		session.CostPerPlayer = *req.CostPerPlayer
 	}
-	if req.Status != nil {
-		// Validate status
-		validStatuses := []string{"planned", "active", "completed", "cancelled"}
-		statusValid := false
-		for _, status := range validStatuses {
-			if *req.Status == status {
-				statusValid = true
-				break
-			}
+	if req.PricingPolicyID != nil {
+		policyID, err := s.resolvePricingPolicyID(*req.PricingPolicyID)
+		if err != nil {
+			return nil, err
 		}
-		if !statusValid {
-			return nil, fmt.Errorf("invalid status: %s", *req.Status)
+		session.PricingPolicyID = policyID
+	}
+	if req.PointsWin != nil {
+		session.PointsWin = *req.PointsWin
+	}
+	if req.PointsDraw != nil {
+		session.PointsDraw = *req.PointsDraw
+	}
+	if req.PointsLoss != nil {
+		session.PointsLoss = *req.PointsLoss
+	}
+	if req.Tiebreakers != nil {
+		if err := validateTiebreakers(req.Tiebreakers); err != nil {
+			return nil, err
 		}
-		session.Status = *req.Status
+		session.Tiebreakers = strings.Join(req.Tiebreakers, ",")
+	}
+	if req.Status != nil && *req.Status != session.Status {
+		// Status changes go through the state machine
+		return nil, fmt.Errorf("status cannot be changed via update")
 	}
 
 	if err := s.db.Save(&session).Error; err != nil {
@@ -164,6 +230,45 @@ func (s *TrainingService) DeleteTrainingSession(id uuid.UUID) error {
 	// Start transaction for cascading deletes
 	tx := s.db.Begin()
 
+	// Delete tournament brackets
+	var tournamentIDs []uuid.UUID
+	if err := tx.Model(&models.Tournament{}).Where("training_session_id = ?", id).Pluck("id", &tournamentIDs).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to fetch tournaments: %w", err)
+	}
+	if len(tournamentIDs) > 0 {
+		if err := tx.Where("tournament_id IN ?", tournamentIDs).Delete(&models.TournamentMatch{}).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to delete tournament matches: %w", err)
+		}
+		if err := tx.Where("tournament_id IN ?", tournamentIDs).Delete(&models.TournamentEntrant{}).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to delete tournament entrants: %w", err)
+		}
+		if err := tx.Where("id IN ?", tournamentIDs).Delete(&models.Tournament{}).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to delete tournaments: %w", err)
+		}
+	}
+
+	// Delete status history
+	if err := tx.Where("training_session_id = ?", id).Delete(&models.TrainingSessionTransition{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete training session transitions: %w", err)
+	}
+
+	// Delete session expenses
+	if err := tx.Where("training_session_id = ?", id).Delete(&models.SessionExpense{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete session expenses: %w", err)
+	}
+
+	// Delete swiss byes
+	if err := tx.Where("training_session_id = ?", id).Delete(&models.SwissBye{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete swiss byes: %w", err)
+	}
+
 	// Delete training games
 	if err := tx.Where("training_session_id = ?", id).Delete(&models.TrainingGame{}).Error; err != nil {
 		tx.Rollback()
@@ -189,38 +294,79 @@ func (s *TrainingService) DeleteTrainingSession(id uuid.UUID) error {
 	return nil
 }
 
-func (s *TrainingService) StartTraining(id uuid.UUID) (*models.TrainingSession, error) {
-	session, err := s.GetTrainingSessionByID(id)
-	if err != nil {
-		return nil, err
+func (s *TrainingService) StartTraining(id uuid.UUID, actor *string) (*models.TrainingSession, error) {
+	return s.TransitionTrainingSession(id, "start", actor, nil)
+}
+
+// CancelTrainingSession cancels a planned or postponed training, cancels its
+// pending games and notifies every invited player.
+func (s *TrainingService) CancelTrainingSession(id uuid.UUID, actor *string, reason string) (*models.TrainingSession, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("cancellation reason is required")
 	}
 
-	if session.Status != "planned" {
-		return nil, fmt.Errorf("training session is not in planned status")
+	var session models.TrainingSession
+	if err := s.db.Preload("TrainingPlayers.Player").First(&session, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("training session not found")
+		}
+		return nil, fmt.Errorf("failed to fetch training session: %w", err)
 	}
+	trainingPlayers := session.TrainingPlayers
+	session.TrainingPlayers = nil
 
-	return s.UpdateTrainingSession(id, &models.TrainingSessionUpdateRequest{
-		Status: utils.StringPtr("active"),
-	})
-}
+	now := time.Now()
+	session.CancellationReason = &reason
+	session.CancelledAt = &now
 
-func (s *TrainingService) FinishTraining(id uuid.UUID) (*models.TrainingSession, error) {
-	session, err := s.GetTrainingSessionByID(id)
-	if err != nil {
+	tx := s.db.Begin()
+
+	if err := s.transitionSession(tx, &session, "cancel", actor, &reason); err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	if session.Status != "active" {
-		return nil, fmt.Errorf("training session is not active")
+	// Cancel games that have not been played
+	if err := tx.Model(&models.TrainingGame{}).
+		Where("training_session_id = ? AND status = ?", id, "pending").
+		Update("status", "cancelled").Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to cancel training games: %w", err)
 	}
 
-	return s.UpdateTrainingSession(id, &models.TrainingSessionUpdateRequest{
-		Status: utils.StringPtr("completed"),
-	})
-}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Notification failures must not undo the cancellation
+	for _, tp := range trainingPlayers {
+		if tp.IsGuest || tp.Player == nil || !tp.Player.NotifyTrainingUpdates {
+			continue
+		}
+		notification := Notification{
+			Type:              "training_cancelled",
+			PlayerID:          tp.Player.ID,
+			Email:             tp.Player.Email,
+			Name:              tp.Player.Name,
+			TrainingSessionID: &session.ID,
+			Subject:           fmt.Sprintf("Training \"%s\" abgesagt", session.Name),
+			Message:           fmt.Sprintf("Das Training \"%s\" am %s wurde abgesagt: %s", session.Name, session.TrainingDate.Format("02.01.2006 15:04"), reason),
+			Data: map[string]string{
+				"reason":        reason,
+				"training_date": session.TrainingDate.Format(time.RFC3339),
+			},
+		}
+		if err := s.notifier.Notify(notification); err != nil {
+			log.Error().Err(err).Str("player_id", tp.Player.ID.String()).Msg("Failed to send cancellation notification")
+		}
+	}
 
+	return s.GetTrainingSessionByID(id)
+}
 
-func (s *TrainingService) AddTrainingPlayer(trainingID uuid.UUID, guestName *string) (*models.TrainingPlayer, error) {
+// AddTrainingPlayer adds a guest to the training, either a known guest or a
+// new guest created from the name
+func (s *TrainingService) AddTrainingPlayer(trainingID uuid.UUID, req *models.TrainingGuestRequest) (*models.TrainingPlayer, error) {
 	// Check if training exists
 	var session models.TrainingSession
 	if err := s.db.First(&session, "id = ?", trainingID).Error; err != nil {
@@ -234,30 +380,79 @@ func (s *TrainingService) AddTrainingPlayer(trainingID uuid.UUID, guestName *str
 		return nil, fmt.Errorf("cannot add players to completed or cancelled training")
 	}
 
-	// Determine if this is a guest player
-	isGuest := guestName != nil && *guestName != ""
+	hasGuestID := req.GuestID != nil && *req.GuestID != ""
+	hasGuestName := req.GuestName != nil && strings.TrimSpace(*req.GuestName) != ""
+	if !hasGuestID && !hasGuestName {
+		return nil, fmt.Errorf("automatic player assignment is handled during training creation")
+	}
 
-	var trainingPlayer *models.TrainingPlayer
+	tx := s.db.Begin()
+
+	var guest models.Guest
+	if hasGuestID {
+		guestID, err := uuid.Parse(*req.GuestID)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("invalid guest ID format")
+		}
+		if err := tx.First(&guest, "id = ?", guestID).Error; err != nil {
+			tx.Rollback()
+			if err == gorm.ErrRecordNotFound {
+				return nil, fmt.Errorf("guest not found")
+			}
+			return nil, fmt.Errorf("failed to fetch guest: %w", err)
+		}
+		if guest.ConvertedPlayerID != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("guest has become a member")
+		}
 
-	if isGuest {
-		// Add guest player
-		trainingPlayer = &models.TrainingPlayer{
-			TrainingSessionID: trainingID,
-			GuestName:         guestName,
-			IsGuest:           true,
-			Attended:          true,
+		var existing int64
+		if err := tx.Model(&models.TrainingPlayer{}).
+			Where("training_session_id = ? AND guest_id = ?", trainingID, guest.ID).
+			Count(&existing).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to check training players: %w", err)
+		}
+		if existing > 0 {
+			tx.Rollback()
+			return nil, fmt.Errorf("guest is already part of the training")
 		}
 	} else {
-		return nil, fmt.Errorf("automatic player assignment is handled during training creation")
+		invitedBy, err := findInviter(tx, req.InvitedBy)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		guest = models.Guest{
+			Name:      strings.TrimSpace(*req.GuestName),
+			InvitedBy: invitedBy,
+		}
+		if err := tx.Create(&guest).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to create guest: %w", err)
+		}
 	}
 
-	if err := s.db.Create(trainingPlayer).Error; err != nil {
+	trainingPlayer := &models.TrainingPlayer{
+		TrainingSessionID: trainingID,
+		GuestID:           &guest.ID,
+		GuestName:         &guest.Name,
+		IsGuest:           true,
+		Attended:          true,
+	}
+	if err := tx.Create(trainingPlayer).Error; err != nil {
+		tx.Rollback()
 		return nil, fmt.Errorf("failed to add training player: %w", err)
 	}
 
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	// Load relationships for response
 	var result models.TrainingPlayer
-	if err := s.db.Preload("TrainingSession").Preload("Player").First(&result, "id = ?", trainingPlayer.ID).Error; err != nil {
+	if err := s.db.Preload("TrainingSession").Preload("Player").Preload("Guest").First(&result, "id = ?", trainingPlayer.ID).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch created training player: %w", err)
 	}
 
@@ -296,63 +491,27 @@ func (s *TrainingService) RemoveTrainingPlayer(trainingPlayerID uuid.UUID) error
 }
 
 func (s *TrainingService) GetTrainingCosts(trainingID uuid.UUID) (*models.TrainingCostsResponse, error) {
-	session, err := s.GetTrainingSessionByID(trainingID)
-	if err != nil {
-		return nil, err
-	}
-
-	var playerCosts []models.PlayerCost
-	totalCollected := 0.0
-
-	for _, tp := range session.TrainingPlayers {
-		if !tp.Attended {
-			continue // Skip non-attending players
-		}
-
-		cost := session.CostPerPlayer
-		gamesPlayed := 0
-
-		// Count games for this player
-		for _, game := range session.Games {
-			if game.Status == "completed" || game.Status == "playing" {
-				// Check if player participated in this game
-				if (tp.PlayerID != nil && (*tp.PlayerID == *game.Player1ID || *tp.PlayerID == *game.Player2ID)) ||
-					(tp.IsGuest && tp.GuestName != nil &&
-						(*tp.GuestName == *game.Guest1Name || *tp.GuestName == *game.Guest2Name)) {
-					gamesPlayed++
-				}
-			}
-		}
+	return s.costService.CalculateTrainingCosts(trainingID)
+}
 
-		// If player didn't participate in any games, cost is 0
-		if gamesPlayed == 0 {
-			cost = 0
-		}
+// resolvePricingPolicyID parses and validates a pricing policy reference, an empty string means no policy
+func (s *TrainingService) resolvePricingPolicyID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
 
-		playerCost := models.PlayerCost{
-			TotalCost:   cost,
-			GamesPlayed: gamesPlayed,
-			IsGuest:     tp.IsGuest,
-		}
+	policyID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pricing policy ID")
+	}
 
-		if tp.IsGuest {
-			playerCost.GuestName = tp.GuestName
-		} else {
-			playerCost.PlayerID = *tp.PlayerID
-			if tp.Player != nil {
-				playerName := tp.Player.Name
-				playerCost.PlayerName = &playerName
-			}
+	var policy models.PricingPolicy
+	if err := s.db.First(&policy, "id = ?", policyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("pricing policy not found")
 		}
-
-		playerCosts = append(playerCosts, playerCost)
-		totalCollected += cost
+		return nil, fmt.Errorf("failed to fetch pricing policy: %w", err)
 	}
 
-	return &models.TrainingCostsResponse{
-		TrainingSessionID: trainingID,
-		PlayerCosts:       playerCosts,
-		TotalCollected:    totalCollected,
-	}, nil
+	return &policyID, nil
 }
-